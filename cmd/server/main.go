@@ -3,8 +3,13 @@ package main
 import (
 	"database/sql"
 	"log"
+	"net"
 	"os"
+	"os/signal"
+	"regexp"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"cacc/pkg/cache"
@@ -15,6 +20,7 @@ import (
 	"cacc/pkg/repository"
 	"cacc/pkg/server"
 	"cacc/pkg/services"
+	socialpb "cacc/proto/socialpb"
 
 	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
@@ -57,24 +63,50 @@ func main() {
 	// ── Auth ────────────────────────────────────────────────────────────
 	authRepo := repository.NewAuthRepository(db)
 	emailSvc := services.NewEmailService()
-	authService := services.NewAuthService(authRepo, emailSvc, jwtSecret)
+	authService := services.NewAuthService(authRepo, emailSvc, services.NewPasswordHasher(), jwtSecret)
 	auth := handlers.NewAuth(wsHub, authService)
 
+	// ── Mídia (Cloudinary) ──────────────────────────────────────────────
+	mediaService := services.NewMediaService()
+
+	// ── Filtro de conteúdo ──────────────────────────────────────────────
+	moderationRepo := repository.NewModerationRepository(db)
+	contentFilter := services.NewContentFilter(redis, moderationRepo)
+
 	// ── Social ──────────────────────────────────────────────────────────
 	socialRepo := repository.NewSocialRepository(db)
 	notifRepo := repository.NewNotificationRepository(db)
-	socialService := services.NewSocialService(socialRepo, authRepo, notifRepo, redis)
+	linkPreviews := services.NewLinkPreviewService(redis)
+	socialService := services.NewSocialService(socialRepo, authRepo, notifRepo, mediaService, contentFilter, linkPreviews, redis)
 	social := handlers.NewSocial(wsHub, socialService)
-	notifHandler := handlers.NewNotification(notifRepo)
+	go social.WatchTrending(30 * time.Second)
+
+	// ── Mensagens diretas ───────────────────────────────────────────────
+	messagesRepo := repository.NewMessagesRepository(db)
+	messagesService := services.NewMessagesService(messagesRepo, socialRepo, authRepo)
+	messages := handlers.NewMessages(wsHub, messagesService)
 
 	// ── Notícias ────────────────────────────────────────────────────────
 	noticiasRepo := repository.NewNoticiasRepository(db)
 	noticiasService := services.NewNoticiasService(noticiasRepo, redis)
 	noticias := handlers.NewNoticias(noticiasService)
+	go noticiasService.ReindexarBusca()
+
+	// ── Notificações (resumo por e-mail depende das notícias) ───────────
+	digestService := services.NewDigestService(notifRepo, noticiasRepo, emailSvc, jwtSecret)
+	go digestService.Watch(15 * time.Minute)
+	notifHandler := handlers.NewNotification(services.NewNotificationService(notifRepo), digestService)
+
+	// ── Busca ───────────────────────────────────────────────────────────
+	searchService := services.NewSearchService(socialRepo, noticiasRepo, redis)
+	search := handlers.NewSearch(searchService)
+
+	// ── Feeds Atom/RSS/JSON ─────────────────────────────────────────────
+	syndication := handlers.NewSyndication(services.NewSyndicationService(socialRepo, authRepo, noticiasRepo, redis))
 
 	// ── Sugestões ───────────────────────────────────────────────────────
 	sugestaoRepo := repository.NewSugestoesRepository(db)
-	sugestaoService := services.NewSugestoesService(sugestaoRepo, redis)
+	sugestaoService := services.NewSugestoesService(sugestaoRepo, contentFilter, redis)
 	sugestoes := handlers.NewSugestoes(sugestaoService)
 
 	// ── Bus ─────────────────────────────────────────────────────────────
@@ -84,11 +116,15 @@ func main() {
 
 	// ── Galeria ─────────────────────────────────────────────────────────
 	galeriaRepo := repository.NewGaleriaRepository(db)
-	galeriaService := services.NewGaleriaService(galeriaRepo)
-	galeria := handlers.NewGaleria(galeriaService, socialRepo)
+	galeriaService := services.NewGaleriaService(galeriaRepo, mediaService, contentFilter)
+	galeria := handlers.NewGaleria(galeriaService, socialRepo, authRepo)
+
+	// ── Moderação ───────────────────────────────────────────────────────
+	moderationService := services.NewModerationService(moderationRepo, socialRepo, galeriaRepo, notifRepo, mediaService, redis)
+	moderation := handlers.NewModeration(moderationService)
 
 	// ── Fiber App ───────────────────────────────────────────────────────
-	app := server.NewApp("portal")
+	app := server.NewApp("portal", postUpload)
 
 	// ═══════════════════════════════════════════════════════════════════
 	//  Routes
@@ -142,6 +178,7 @@ func main() {
 	protected.Post("/logout", auth.Logout)
 	protected.Post("/logout-all", auth.LogoutAll)
 	protected.Get("/sessions", auth.Sessions)
+	protected.Delete("/sessions/:id", auth.RevokeSession)
 
 	app.Get("/hub/status", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -155,6 +192,7 @@ func main() {
 	// ── Notícias REST (public read, admin write) ────────────────────────
 	noticiasGroup := app.Group("/noticias")
 	noticiasGroup.Get("/destaques", noticias.Destaques)
+	noticiasGroup.Get("/feed.:format", syndication.NoticiasFeed)
 	noticiasGroup.Get("/:id", noticias.BuscarPorID)
 	noticiasGroup.Get("/", noticias.Listar)
 
@@ -170,20 +208,58 @@ func main() {
 	// ── Social (Feed, Threads, Profiles) ────────────────────────────────
 	socialGroup := app.Group("/social")
 	socialGroup.Get("/feed", middleware.OptionalAuthMiddleware, social.Feed)
+	socialGroup.Get("/feed.:format", syndication.SocialFeed)
 	socialGroup.Get("/feed/:id", middleware.OptionalAuthMiddleware, social.Thread)
+	socialGroup.Get("/feed/:id/replies", middleware.OptionalAuthMiddleware, social.Replies)
+	socialGroup.Get("/feed/:id/history", middleware.OptionalAuthMiddleware, social.History)
+	socialGroup.Get("/feed/:id/poll", middleware.OptionalAuthMiddleware, social.Poll)
+	socialGroup.Get("/feed/:id/likes", middleware.OptionalAuthMiddleware, social.Likers)
+	socialGroup.Get("/feed/:id/reactions", middleware.OptionalAuthMiddleware, social.Reactors)
+	socialGroup.Get("/profile/:username/followers", social.Followers)
+	socialGroup.Get("/profile/:username/following", social.Following)
+	socialGroup.Get("/profile/:username/posts", middleware.OptionalAuthMiddleware, social.ProfilePosts)
+	socialGroup.Get("/profile/:username/feed.:format", syndication.ProfileFeed)
 	socialGroup.Get("/profile/:username?", middleware.OptionalAuthMiddleware, social.Profile)
+	socialGroup.Get("/tags/:tag", middleware.OptionalAuthMiddleware, social.TagTimeline)
+	socialGroup.Get("/trending", social.Trending)
+	socialGroup.Get("/announcements", middleware.OptionalAuthMiddleware, social.Announcements)
 
 	socialPriv := socialGroup.Group("", middleware.AuthMiddleware)
 	socialPriv.Put("/profile", social.UpdateProfile)
+	socialPriv.Put("/profile/:username/follow", social.Follow)
+	socialPriv.Delete("/profile/:username/follow", social.Unfollow)
+	socialPriv.Put("/profile/:username/block", social.Block)
+	socialPriv.Delete("/profile/:username/block", social.Unblock)
+	socialPriv.Put("/profile/:username/mute", social.Mute)
+	socialPriv.Delete("/profile/:username/mute", social.Unmute)
+	socialPriv.Get("/blocks", social.Blocks)
+	socialPriv.Get("/mutes", social.Mutes)
 	socialPriv.Post("/feed", social.CreatePost)
 	socialPriv.Post("/feed/:id/reply", social.CreateReply)
 	socialPriv.Put("/feed/:id/like", social.LikePost)
 	socialPriv.Delete("/feed/:id/like", social.UnlikePost)
+	socialPriv.Put("/feed/:id/reactions/:emoji", social.React)
+	socialPriv.Delete("/feed/:id/reactions/:emoji", social.Unreact)
+	socialPriv.Post("/feed/:id/vote", social.Vote)
+	socialPriv.Post("/feed/:id/repost", social.CreateRepost)
+	socialPriv.Delete("/feed/:id/repost", social.UndoRepost)
+	socialPriv.Put("/feed/:id/bookmark", social.BookmarkPost)
+	socialPriv.Delete("/feed/:id/bookmark", social.UnbookmarkPost)
+	socialPriv.Get("/bookmarks", social.Bookmarks)
+	socialPriv.Put("/feed/:id/pin", social.PinPost)
+	socialPriv.Delete("/feed/:id/pin", social.UnpinPost)
+	socialPriv.Put("/feed/:id", social.EditPost)
 	socialPriv.Delete("/feed/:id", social.DeletePost)
 
+	socialAdmin := socialGroup.Group("/announcements", middleware.AuthMiddleware, middleware.AdminMiddleware)
+	socialAdmin.Put("/:id", social.PinAnnouncement)
+	socialAdmin.Delete("/:id", social.UnpinAnnouncement)
+
 	sugestoesGroup.Post("/", middleware.OptionalAuthMiddleware, sugestoes.Criar)
 
 	sugestoesAdmin := sugestoesGroup.Group("", middleware.AuthMiddleware, middleware.AdminMiddleware)
+	sugestoesAdmin.Get("/pendentes", sugestoes.Pendentes)
+	sugestoesAdmin.Put("/:id/aprovar", sugestoes.Aprovar)
 	sugestoesAdmin.Delete("/:id", sugestoes.Deletar)
 	sugestoesAdmin.Put("/:id", sugestoes.Atualizar)
 
@@ -204,9 +280,40 @@ func main() {
 	busPriv.Get("/contact", bus.GetContact)
 	busPriv.Put("/contact", bus.SetContact)
 
+	// O link de cancelamento do resumo vem assinado e dispensa login; precisa
+	// ser registrado antes do grupo autenticado.
+	app.Get("/notifications/digest/unsubscribe", notifHandler.ConfirmUnsubscribeDigest)
+	app.Post("/notifications/digest/unsubscribe", notifHandler.UnsubscribeDigest)
 	notifPriv := app.Group("/notifications", middleware.AuthMiddleware)
 	notifPriv.Get("/", notifHandler.GetNotifications)
+	notifPriv.Get("/unread", notifHandler.UnreadCount)
 	notifPriv.Put("/read", notifHandler.MarkAsRead)
+	notifPriv.Put("/:id/read", notifHandler.MarkOneAsRead)
+	notifPriv.Get("/preferences", notifHandler.Preferences)
+	notifPriv.Put("/preferences", notifHandler.UpdatePreferences)
+	notifPriv.Get("/digest", notifHandler.DigestSettings)
+	notifPriv.Put("/digest", notifHandler.UpdateDigestSettings)
+
+	// ── Mensagens diretas (REST; entrega, digitando e leitura pelo WS) ──
+	messagesPriv := app.Group("/messages", middleware.AuthMiddleware)
+	messagesPriv.Get("/settings", messages.Settings)
+	messagesPriv.Put("/settings", messages.UpdateSettings)
+	messagesPriv.Get("/unread", messages.Unread)
+	messagesPriv.Get("/conversations", messages.Conversations)
+	messagesPriv.Post("/conversations", limiter.New(limiter.Config{
+		Max:          20,
+		Expiration:   1 * time.Hour,
+		KeyGenerator: userKey,
+	}), messages.StartConversation)
+	messagesPriv.Get("/conversations/:id", messages.Conversation)
+	messagesPriv.Delete("/conversations/:id", messages.Leave)
+	messagesPriv.Get("/conversations/:id/messages", messages.Messages)
+	messagesPriv.Post("/conversations/:id/messages", limiter.New(limiter.Config{
+		Max:          30,
+		Expiration:   1 * time.Minute,
+		KeyGenerator: userKey,
+	}), messages.Send)
+	messagesPriv.Put("/conversations/:id/read", messages.MarkRead)
 
 	// ── Galeria (leitura pública, upload/delete autenticado) ─────────────
 	galeriaGroup := app.Group("/galeria")
@@ -215,6 +322,28 @@ func main() {
 	galeriaPriv.Post("/upload", galeria.Upload)
 	galeriaPriv.Delete("/:id", galeria.Delete)
 
+	// ── Moderação (denúncia autenticada, fila/ações admin) ──────────────
+	moderationGroup := app.Group("/moderation")
+	moderationGroup.Post("/reports", middleware.AuthMiddleware, limiter.New(limiter.Config{
+		Max:          20,
+		Expiration:   1 * time.Hour,
+		KeyGenerator: userKey,
+	}), moderation.Report)
+	moderationAdmin := moderationGroup.Group("", middleware.AuthMiddleware, middleware.AdminMiddleware)
+	moderationAdmin.Get("/reports", moderation.Queue)
+	moderationAdmin.Get("/reports/:id", moderation.GetReport)
+	moderationAdmin.Post("/reports/:id/actions", moderation.Act)
+	moderationAdmin.Get("/audit", moderation.AuditLog)
+
+	// ── Busca (posts, usuários, notícias) ───────────────────────────────
+	app.Get("/search", limiter.New(limiter.Config{
+		Max:        30,
+		Expiration: 1 * time.Minute,
+		KeyGenerator: func(c *fiber.Ctx) string {
+			return c.IP()
+		},
+	}), middleware.OptionalAuthMiddleware, search.Search)
+
 	// ── WebSocket ───────────────────────────────────────────────────────
 	app.Use("/ws", parseWSToken(jwtSecret))
 
@@ -231,6 +360,47 @@ func main() {
 		port = "8082"
 	}
 
+	// ── gRPC ────────────────────────────────────────────────────────────
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	grpcServer := server.NewGRPC()
+	socialpb.RegisterSocialServiceServer(grpcServer, handlers.NewSocialGRPC(social))
+	go func() {
+		lis, err := net.Listen("tcp", "0.0.0.0:"+grpcPort)
+		if err != nil {
+			log.Fatalf("[PORTAL] Failed to start gRPC: %v", err)
+		}
+		log.Printf("[PORTAL] gRPC starting on :%s", grpcPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("[PORTAL] gRPC stopped: %v", err)
+		}
+	}()
+
+	// SIGTERM (docker stop, deploy) para os dois servidores juntos. Streams
+	// do SubscribeFeed não terminam sozinhos, então o GracefulStop tem prazo.
+	go func() {
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+		<-stop
+		log.Printf("[PORTAL] Shutting down")
+
+		done := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(shutdownTimeout):
+			grpcServer.Stop()
+		}
+		if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+			log.Printf("[PORTAL] Shutdown: %v", err)
+		}
+	}()
+
 	addr := "0.0.0.0:" + port
 	log.Printf("[PORTAL] WebSocket: wss://<domain>/ws")
 	log.Printf("[PORTAL] Server starting on %s", addr)
@@ -240,6 +410,30 @@ func main() {
 	}
 }
 
+// shutdownTimeout is how long each server gets to finish its requests and
+// streams on SIGTERM before they are cut.
+const shutdownTimeout = 10 * time.Second
+
+// postUploadPath matches the routes that take attachments: new posts and
+// replies.
+var postUploadPath = regexp.MustCompile(`^/social/feed(/\d+/reply)?/?$`)
+
+// postUpload lets uploads through middleware.BodyLimit with
+// server.MaxUploadBody; everything else keeps the default limit.
+func postUpload(c *fiber.Ctx) bool {
+	return c.Method() == fiber.MethodPost && postUploadPath.MatchString(c.Path())
+}
+
+// userKey keys a rate limit on the logged-in user rather than the IP, so
+// people behind the same NAT (the campus network) don't share one budget.
+// Only for routes behind AuthMiddleware.
+func userKey(c *fiber.Ctx) string {
+	if userID, ok := c.Locals("user_id").(int); ok {
+		return "user:" + strconv.Itoa(userID)
+	}
+	return c.IP()
+}
+
 // parseWSToken returns a Fiber handler that parses JWT from query or header.
 // The secret is captured via closure – no os.Getenv per request.
 func parseWSToken(jwtSecret string) fiber.Handler {
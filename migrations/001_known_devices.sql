@@ -0,0 +1,9 @@
+-- Dispositivos já vistos por usuário, usados no alerta de "novo login".
+-- fingerprint = device|browser|os (sem versão), ver services/useragent.go
+CREATE TABLE IF NOT EXISTS known_devices (
+    user_id     INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint TEXT      NOT NULL,
+    first_seen  TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen   TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, fingerprint)
+);
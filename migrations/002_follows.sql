@@ -0,0 +1,10 @@
+-- Grafo de seguidores: follower_id segue followee_id.
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_follows_followee ON follows (followee_id, created_at DESC);
@@ -0,0 +1,13 @@
+-- Busca textual (GET /search). Posts e perfis vivem no Postgres; o índice é
+-- mantido pelo próprio banco a cada INSERT/UPDATE/DELETE.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('portuguese', COALESCE(texto, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_posts_search ON posts USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_social_profiles_search ON social_profiles USING GIN (
+    to_tsvector('simple', COALESCE(display_name, '') || ' ' || COALESCE(bio, ''))
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_username_prefix ON users (username text_pattern_ops);
@@ -0,0 +1,7 @@
+-- Busca textual de notícias (MariaDB). conteudo guarda o JSON do Editor.js,
+-- então o texto puro é gravado em texto_busca pelo noticiasService em
+-- Criar/Atualizar e só ele entra no índice FULLTEXT.
+ALTER TABLE noticias ADD COLUMN IF NOT EXISTS texto_busca MEDIUMTEXT NOT NULL DEFAULT '';
+
+CREATE FULLTEXT INDEX IF NOT EXISTS ft_noticias_busca ON noticias (titulo, resumo, texto_busca);
+CREATE FULLTEXT INDEX IF NOT EXISTS ft_noticias_titulo ON noticias (titulo);
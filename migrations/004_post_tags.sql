@@ -0,0 +1,17 @@
+-- Índice hashtag → post, preenchido por socialService ao criar posts/respostas.
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id    INT          NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag        VARCHAR(50)  NOT NULL,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tag, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_timeline ON post_tags (tag, created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_post_tags_post ON post_tags (post_id);
+
+-- Backfill aproximado dos posts existentes (a extração oficial é extractHashtags
+-- em pkg/services/hashtags.go).
+INSERT INTO post_tags (post_id, tag, created_at)
+SELECT DISTINCT p.id, lower(m[2]), p.created_at
+FROM posts p, regexp_matches(p.texto, '(^|[^[:alnum:]_&])#([[:alnum:]_]{1,50})', 'g') AS m
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,12 @@
+-- Edição de posts: edited_at marca o post como editado e post_revisions guarda
+-- cada texto substituído, válido a partir de created_at.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id         SERIAL PRIMARY KEY,
+    post_id    INT       NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    texto      TEXT      NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions (post_id, id DESC);
@@ -0,0 +1,18 @@
+-- Anexos de posts/respostas (imagens, GIFs, PDFs) hospedados no Cloudinary.
+CREATE TABLE IF NOT EXISTS post_attachments (
+    id            SERIAL PRIMARY KEY,
+    post_id       INT          NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    position      SMALLINT     NOT NULL DEFAULT 0,
+    kind          VARCHAR(10)  NOT NULL,
+    url           TEXT         NOT NULL,
+    public_id     TEXT         NOT NULL DEFAULT '',
+    resource_type VARCHAR(10)  NOT NULL DEFAULT 'image',
+    alt_text      VARCHAR(1000) NOT NULL DEFAULT '',
+    file_name     VARCHAR(255) NOT NULL DEFAULT '',
+    width         INT          NOT NULL DEFAULT 0,
+    height        INT          NOT NULL DEFAULT 0,
+    size          BIGINT       NOT NULL DEFAULT 0,
+    created_at    TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_attachments_post ON post_attachments (post_id, position);
@@ -0,0 +1,36 @@
+-- Enquetes dentro de posts/respostas. A enquete usa o id do post como chave.
+CREATE TABLE IF NOT EXISTS polls (
+    post_id   INT       PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    multiple  BOOLEAN   NOT NULL DEFAULT FALSE,
+    closes_at TIMESTAMP NULL,
+    voters    INT       NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    id       SERIAL       PRIMARY KEY,
+    post_id  INT          NOT NULL REFERENCES polls(post_id) ON DELETE CASCADE,
+    position SMALLINT     NOT NULL,
+    texto    VARCHAR(100) NOT NULL,
+    votes    INT          NOT NULL DEFAULT 0,
+    UNIQUE (post_id, id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_options_post ON poll_options (post_id, position);
+
+-- Uma cédula por usuário e enquete: o PK é o que garante o voto único.
+CREATE TABLE IF NOT EXISTS poll_ballots (
+    post_id    INT       NOT NULL REFERENCES polls(post_id) ON DELETE CASCADE,
+    user_id    INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id)
+);
+
+-- Opções escolhidas em cada cédula (várias só em enquetes de múltipla escolha).
+CREATE TABLE IF NOT EXISTS poll_votes (
+    post_id   INT NOT NULL,
+    user_id   INT NOT NULL,
+    option_id INT NOT NULL,
+    PRIMARY KEY (post_id, user_id, option_id),
+    FOREIGN KEY (post_id, user_id)   REFERENCES poll_ballots (post_id, user_id) ON DELETE CASCADE,
+    FOREIGN KEY (post_id, option_id) REFERENCES poll_options (post_id, id) ON DELETE CASCADE
+);
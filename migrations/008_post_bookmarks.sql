@@ -0,0 +1,10 @@
+-- Posts salvos pelo usuário. Somem junto com o post (ON DELETE CASCADE).
+CREATE TABLE IF NOT EXISTS post_bookmarks (
+    user_id    INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id    INT       NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_bookmarks_user ON post_bookmarks (user_id, created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_post_bookmarks_post ON post_bookmarks (post_id);
@@ -0,0 +1,19 @@
+-- Bloqueios: o bloqueado não vê, responde, curte nem menciona quem bloqueou
+-- (e vice-versa). Silenciar só esconde os posts/notificações do silenciado.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id   INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id   INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
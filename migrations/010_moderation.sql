@@ -0,0 +1,42 @@
+-- Denúncias e fila de moderação.
+ALTER TABLE posts   ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP NULL;
+ALTER TABLE galeria ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMP NULL;
+ALTER TABLE users   ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS reports (
+    id               SERIAL       PRIMARY KEY,
+    target_type      VARCHAR(10)  NOT NULL,  -- post, galeria, profile
+    target_id        INT          NOT NULL,
+    reported_user_id INT          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reporter_id      INT          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason           VARCHAR(20)  NOT NULL,
+    details          VARCHAR(1000) NOT NULL DEFAULT '',
+    snapshot         TEXT         NOT NULL DEFAULT '',
+    status           VARCHAR(10)  NOT NULL DEFAULT 'open',
+    created_at       TIMESTAMP    NOT NULL DEFAULT NOW(),
+    resolved_at      TIMESTAMP    NULL,
+    resolved_by      INT          NULL REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Uma denúncia aberta por usuário e alvo.
+CREATE UNIQUE INDEX IF NOT EXISTS uq_reports_open
+    ON reports (reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_reports_status ON reports (status, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_reports_target ON reports (target_type, target_id);
+
+-- Trilha de auditoria: toda decisão de moderação, com ou sem denúncia.
+CREATE TABLE IF NOT EXISTS moderation_actions (
+    id           SERIAL       PRIMARY KEY,
+    report_id    INT          NULL REFERENCES reports(id) ON DELETE SET NULL,
+    moderator_id INT          NOT NULL REFERENCES users(id),
+    action       VARCHAR(10)  NOT NULL,
+    target_type  VARCHAR(10)  NOT NULL,
+    target_id    INT          NOT NULL,
+    user_id      INT          NOT NULL,
+    note         VARCHAR(1000) NOT NULL DEFAULT '',
+    until        TIMESTAMP    NULL,
+    created_at   TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_created ON moderation_actions (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_report ON moderation_actions (report_id);
@@ -0,0 +1,3 @@
+-- Filtro de conteúdo: conteúdo retido pelo filtro entra na fila de moderação
+-- como denúncia automática (reason = 'auto_filter'), sem denunciante.
+ALTER TABLE reports ALTER COLUMN reporter_id DROP NOT NULL;
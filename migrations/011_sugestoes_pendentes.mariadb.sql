@@ -0,0 +1,6 @@
+-- Sugestões retidas pelo filtro de conteúdo ficam fora da listagem pública
+-- até um admin aprovar (PUT /sugestoes/:id/aprovar).
+ALTER TABLE sugestoes ADD COLUMN IF NOT EXISTS pendente BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE sugestoes ADD COLUMN IF NOT EXISTS motivo_pendente VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_sugestoes_pendente ON sugestoes (pendente, id);
@@ -0,0 +1,27 @@
+-- ATENÇÃO, perda de dados: esta migração não tem volta.
+--   * Reposts simples duplicados do mesmo usuário são APAGADOS (fica o mais
+--     antigo de cada par usuário/original), junto com o que depende deles
+--     em cascata (post_bookmarks, por exemplo).
+--   * A FK posts_repost_id_fkey é removida; o banco deixa de garantir que
+--     repost_id aponte para um post existente.
+-- Faça backup da tabela posts antes de aplicar em produção.
+--
+-- Reposts e citações. Um repost simples é um post raiz com repost_id e texto
+-- vazio; uma citação tem texto. Apagar o original leva junto os reposts
+-- simples (feito pelo DeletePost) e deixa as citações apontando para um id
+-- que não existe mais, exibido como "original indisponível" — por isso a FK
+-- sai.
+ALTER TABLE posts DROP CONSTRAINT IF EXISTS posts_repost_id_fkey;
+
+-- Reposts simples duplicados (a rota antiga não impedia) antes do índice único.
+DELETE FROM posts a
+USING posts b
+WHERE a.repost_id = b.repost_id
+  AND a.user_id = b.user_id
+  AND a.texto = '' AND b.texto = ''
+  AND a.parent_id IS NULL AND b.parent_id IS NULL
+  AND a.id > b.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_posts_plain_repost
+    ON posts (user_id, repost_id) WHERE texto = '' AND parent_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_repost_id ON posts (repost_id) WHERE repost_id IS NOT NULL;
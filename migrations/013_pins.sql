@@ -0,0 +1,12 @@
+-- Post fixado no perfil (um por usuário) e avisos fixados por admins no topo
+-- do feed, com validade.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS pinned_post_id INT NULL REFERENCES posts(id) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS feed_announcements (
+    post_id    INT       PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE,
+    pinned_by  INT       NULL REFERENCES users(id) ON DELETE SET NULL,
+    pinned_at  TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_feed_announcements_expires ON feed_announcements (expires_at);
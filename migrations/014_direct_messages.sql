@@ -0,0 +1,34 @@
+-- Mensagens diretas: conversas 1:1 (direct_key "menor:maior" garante uma só
+-- por par) e grupos pequenos. last_read_message_id de cada membro dá o
+-- contador de não lidas e os recibos de leitura.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS dm_policy VARCHAR(16) NOT NULL DEFAULT 'everyone';
+
+CREATE TABLE IF NOT EXISTS conversations (
+    id              SERIAL PRIMARY KEY,
+    is_group        BOOLEAN      NOT NULL DEFAULT FALSE,
+    title           VARCHAR(80)  NOT NULL DEFAULT '',
+    direct_key      VARCHAR(32)  NULL UNIQUE,
+    created_by      INT          NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP    NOT NULL DEFAULT NOW(),
+    last_message_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS conversation_members (
+    conversation_id      INT       NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id              INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    joined_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_read_message_id INT       NOT NULL DEFAULT 0,
+    PRIMARY KEY (conversation_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_members_user ON conversation_members (user_id);
+
+CREATE TABLE IF NOT EXISTS direct_messages (
+    id              SERIAL PRIMARY KEY,
+    conversation_id INT       NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    sender_id       INT       NULL REFERENCES users(id) ON DELETE SET NULL,
+    texto           TEXT      NOT NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_direct_messages_conversation ON direct_messages (conversation_id, id DESC);
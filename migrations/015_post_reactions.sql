@@ -0,0 +1,29 @@
+-- Reações com emoji (conjunto fixo, validado no serviço). Um usuário pode
+-- usar vários emojis no mesmo post, mas cada um uma vez só. A curtida antiga
+-- vira a reação 👍 e posts.likes segue como o contador dela.
+CREATE TABLE IF NOT EXISTS post_reactions (
+    id         SERIAL PRIMARY KEY,
+    post_id    INT         NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    user_id    INT         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji      VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP   NOT NULL DEFAULT NOW(),
+    UNIQUE (post_id, user_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_reactions_post ON post_reactions (post_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_post_reactions_user ON post_reactions (user_id);
+
+DO $$
+BEGIN
+    IF to_regclass('post_likes') IS NOT NULL THEN
+        INSERT INTO post_reactions (post_id, user_id, emoji)
+        SELECT post_id, user_id, '👍' FROM post_likes
+        ON CONFLICT (post_id, user_id, emoji) DO NOTHING;
+
+        UPDATE posts p SET likes = c.n
+        FROM (SELECT post_id, COUNT(*) AS n FROM post_reactions WHERE emoji = '👍' GROUP BY post_id) c
+        WHERE c.post_id = p.id AND p.likes <> c.n;
+
+        DROP TABLE post_likes;
+    END IF;
+END $$;
@@ -0,0 +1,39 @@
+-- Notificações agrupadas, preferências por categoria e limpeza junto com o
+-- post. Curtidas, reações, reposts e follows do mesmo tipo (e post) se
+-- juntam numa só notificação enquanto ela não foi lida; notification_actors
+-- guarda quem entrou no grupo e actor_count quantos são.
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS actor_count INT NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS notification_actors (
+    notification_id INT       NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+    actor_id        INT       NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (notification_id, actor_id)
+);
+
+INSERT INTO notification_actors (notification_id, actor_id, created_at)
+SELECT id, actor_id, created_at FROM notifications WHERE actor_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+CREATE INDEX IF NOT EXISTS idx_notifications_open_group
+    ON notifications (user_id, type, post_id) WHERE is_read = false;
+
+-- Canais por categoria (likes, replies, mentions, reposts, follows, system).
+-- Sem linha, vale o padrão de models.DefaultNotificationPreferences.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id  INT         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(16) NOT NULL,
+    in_app   BOOLEAN     NOT NULL,
+    email    BOOLEAN     NOT NULL,
+    PRIMARY KEY (user_id, category)
+);
+
+-- Notificações de posts apagados somem com eles (antes ficavam com post_id
+-- NULL apontando para nada).
+DELETE FROM notifications
+WHERE type IN ('like', 'reaction', 'reply', 'mention', 'repost', 'quote')
+  AND (post_id IS NULL OR NOT EXISTS (SELECT 1 FROM posts p WHERE p.id = notifications.post_id));
+
+ALTER TABLE notifications DROP CONSTRAINT IF EXISTS notifications_post_id_fkey;
+ALTER TABLE notifications
+    ADD CONSTRAINT notifications_post_id_fkey FOREIGN KEY (post_id) REFERENCES posts(id) ON DELETE CASCADE;
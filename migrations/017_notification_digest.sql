@@ -0,0 +1,14 @@
+-- Resumo por e-mail das notificações não lidas (e notícias em destaque).
+-- Só recebe quem escolheu uma frequência; last_period guarda o último período
+-- enviado ("2026-10-18" ou "2026-W42") e é o que impede duas réplicas de
+-- mandarem o mesmo resumo: só um UPDATE consegue trocar o período.
+CREATE TABLE IF NOT EXISTS notification_digests (
+    user_id      INT         PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    frequency    VARCHAR(8)  NOT NULL DEFAULT 'off',
+    last_period  VARCHAR(16),
+    last_sent_at TIMESTAMP,
+    updated_at   TIMESTAMP   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_digests_due
+    ON notification_digests (frequency, user_id) WHERE frequency <> 'off';
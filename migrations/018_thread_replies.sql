@@ -0,0 +1,4 @@
+-- A thread passou a ser carregada por uma consulta recursiva que, a cada
+-- nível, lê as respostas de cada post em ordem (created_at, id); o mesmo
+-- índice serve à paginação de GET /social/feed/:id/replies.
+CREATE INDEX IF NOT EXISTS idx_posts_parent ON posts (parent_id, created_at, id) WHERE parent_id IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Localização aproximada da sessão, resolvida uma vez no login (GEOIP_URL)
+-- em vez de a cada GET /auth/sessions.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS location VARCHAR(200) NOT NULL DEFAULT '';
@@ -26,6 +26,7 @@ func Unauthorized(msg string) *AppError { return &AppError{Code: ErrUnauthorized
 func Forbidden(msg string) *AppError    { return &AppError{Code: ErrForbidden, Message: msg} }
 func NotFound(msg string) *AppError     { return &AppError{Code: ErrNotFound, Message: msg} }
 func Conflict(msg string) *AppError     { return &AppError{Code: ErrConflict, Message: msg} }
+func TooMany(msg string) *AppError      { return &AppError{Code: ErrTooMany, Message: msg} }
 func Internal(msg string) *AppError     { return &AppError{Code: ErrInternal, Message: msg} }
 
 func Wrap(msg string, err error) *AppError {
@@ -3,8 +3,10 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -103,6 +105,51 @@ func (r *Redis) DelPattern(pattern string) {
 	}
 }
 
+// Incr increments a counter, starting its TTL on the first increment, and
+// returns the new value (0 if Redis is unavailable).
+func (r *Redis) Incr(key string, ttl time.Duration) int64 {
+	n, err := r.client.Incr(r.ctx, key).Result()
+	if err != nil {
+		return 0
+	}
+	if n == 1 {
+		r.client.Expire(r.ctx, key, ttl)
+	}
+	return n
+}
+
+// ScoredMember is one entry of a sorted set.
+type ScoredMember struct {
+	Member string
+	Score  float64
+}
+
+// ZIncrBy adds incr to member's score in a sorted set and (re)sets the key TTL.
+func (r *Redis) ZIncrBy(key, member string, incr float64, ttl time.Duration) {
+	pipe := r.client.Pipeline()
+	pipe.ZIncrBy(r.ctx, key, incr, member)
+	pipe.Expire(r.ctx, key, ttl)
+	pipe.Exec(r.ctx)
+}
+
+// ZUnionTop sums the sorted sets in keys, scaling each by the matching weight,
+// and returns the n highest-scored members. Missing keys count as empty.
+func (r *Redis) ZUnionTop(keys []string, weights []float64, n int) []ScoredMember {
+	zs, err := r.client.ZUnionWithScores(r.ctx, redis.ZStore{Keys: keys, Weights: weights}).Result()
+	if err != nil {
+		return nil
+	}
+	sort.Slice(zs, func(i, j int) bool { return zs[i].Score > zs[j].Score })
+	if len(zs) > n {
+		zs = zs[:n]
+	}
+	out := make([]ScoredMember, len(zs))
+	for i, z := range zs {
+		out[i] = ScoredMember{Member: fmt.Sprint(z.Member), Score: z.Score}
+	}
+	return out
+}
+
 func (r *Redis) Close() {
 	r.client.Close()
 }
@@ -302,7 +302,7 @@ func (ah *AuthHandler) Sessions(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"erro": "Usuário não autenticado"})
 	}
 
-	sessions, err := ah.service.Sessions(userID)
+	sessions, err := ah.service.Sessions(userID, c.Cookies("refresh_token"))
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro interno"})
 	}
@@ -311,6 +311,8 @@ func (ah *AuthHandler) Sessions(c *fiber.Ctx) error {
 	for _, s := range sessions {
 		mappedSessions = append(mappedSessions, fiber.Map{
 			"id": s.ID, "user_agent": s.UserAgent, "ip": s.IP,
+			"device": s.Device, "browser": s.Browser, "os": s.OS,
+			"location": s.Location, "current": s.Current,
 			"expires_at": s.ExpiresAt, "created_at": s.CreatedAt,
 		})
 	}
@@ -318,6 +320,25 @@ func (ah *AuthHandler) Sessions(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"sessions": mappedSessions})
 }
 
+// DELETE /auth/sessions/:id
+func (ah *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok || userID <= 0 {
+		return c.Status(401).JSON(fiber.Map{"erro": "Usuário não autenticado"})
+	}
+
+	sessionID, err := c.ParamsInt("id")
+	if err != nil || sessionID <= 0 {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	if err := ah.service.RevokeSession(userID, sessionID); err != nil {
+		return respondErr(c, err)
+	}
+
+	return c.JSON(fiber.Map{"status": "ok", "id": sessionID})
+}
+
 // ─── GetUserByUUID ───────────────────────────────────────────────────────────
 
 func (ah *AuthHandler) GetUserByUUID(c *fiber.Ctx) error {
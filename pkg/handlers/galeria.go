@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"cacc/pkg/apperror"
 	"cacc/pkg/repository"
 	"cacc/pkg/services"
 	"fmt"
@@ -14,22 +15,25 @@ import (
 type GaleriaHandler struct {
 	service    services.GaleriaService
 	socialRepo repository.SocialRepository // para buscar displayName e avatar
+	authRepo   repository.AuthRepository   // suspensão
 }
 
-func NewGaleria(s services.GaleriaService, socialRepo repository.SocialRepository) *GaleriaHandler {
-	return &GaleriaHandler{service: s, socialRepo: socialRepo}
+func NewGaleria(s services.GaleriaService, socialRepo repository.SocialRepository, authRepo repository.AuthRepository) *GaleriaHandler {
+	return &GaleriaHandler{service: s, socialRepo: socialRepo, authRepo: authRepo}
 }
 
-// GET /galeria/list?limit=30&offset=0
+// GET /galeria/list?limit=30&cursor=
 func (gh *GaleriaHandler) List(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 30)
-	offset := c.QueryInt("offset", 0)
 
-	items, err := gh.service.List(limit, offset)
+	page, err := gh.service.List(limit, c.Query("cursor"))
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao listar galeria"})
 	}
-	return c.JSON(items)
+	return c.JSON(page)
 }
 
 // POST /galeria/upload  (multipart/form-data: file + caption)
@@ -40,6 +44,10 @@ func (gh *GaleriaHandler) Upload(c *fiber.Ctx) error {
 	}
 	username, _ := c.Locals("username").(string)
 
+	if until := gh.authRepo.SuspendedUntil(userID); until != nil {
+		return c.Status(403).JSON(fiber.Map{"erro": "Sua conta está suspensa até " + until.Format("02/01/2006 15:04")})
+	}
+
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"erro": "Nenhum arquivo enviado (campo: file)"})
@@ -87,9 +95,15 @@ func (gh *GaleriaHandler) Upload(c *fiber.Ctx) error {
 
 	item, err := gh.service.Upload(data, fileHeader.Filename, userID, username, authorName, avatarURL, caption)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": err.Error()})
 	}
 
+	if item.Held {
+		return c.Status(202).JSON(item)
+	}
 	return c.Status(201).JSON(item)
 }
 
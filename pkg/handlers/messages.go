@@ -0,0 +1,274 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/envelope"
+	"cacc/pkg/hub"
+	"cacc/pkg/models"
+	"cacc/pkg/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MessagesHandler struct {
+	hub     *hub.Hub
+	service services.MessagesService
+}
+
+func NewMessages(h *hub.Hub, s services.MessagesService) *MessagesHandler {
+	mh := &MessagesHandler{hub: h, service: s}
+	h.On("dm.typing", mh.wsTyping)
+	h.On("dm.read", mh.wsRead)
+	return mh
+}
+
+// GET /messages/settings
+func (mh *MessagesHandler) Settings(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+	return c.JSON(mh.service.Settings(userID))
+}
+
+// PUT /messages/settings
+func (mh *MessagesHandler) UpdateSettings(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+
+	var req models.DMSettings
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	settings, err := mh.service.UpdateSettings(userID, req)
+	if err != nil {
+		return respondErr(c, err)
+	}
+	return c.JSON(settings)
+}
+
+// GET /messages/unread
+func (mh *MessagesHandler) Unread(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+	return c.JSON(fiber.Map{"unread": mh.service.UnreadTotal(userID)})
+}
+
+// GET /messages/conversations?cursor=
+func (mh *MessagesHandler) Conversations(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := mh.service.Conversations(userID, c.QueryInt("limit", 20), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar conversas"})
+	}
+	return c.JSON(page)
+}
+
+// POST /messages/conversations
+func (mh *MessagesHandler) StartConversation(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+
+	var req models.CreateConversationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	conv, created, err := mh.service.StartConversation(userID, req)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao criar conversa"})
+	}
+	if created {
+		return c.Status(201).JSON(conv)
+	}
+	return c.JSON(conv)
+}
+
+// GET /messages/conversations/:id
+func (mh *MessagesHandler) Conversation(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	conv, err := mh.service.Conversation(userID, id)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar conversa"})
+	}
+	return c.JSON(conv)
+}
+
+// DELETE /messages/conversations/:id — sai do grupo
+func (mh *MessagesHandler) Leave(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	rest, err := mh.service.Leave(userID, id)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao sair da conversa"})
+	}
+
+	mh.hub.SendToUsers(rest, "dm_member_left", "messages", fiber.Map{
+		"conversation_id": id,
+		"user_id":         userID,
+	})
+	return c.SendStatus(204)
+}
+
+// GET /messages/conversations/:id/messages?cursor=
+func (mh *MessagesHandler) Messages(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := mh.service.Messages(userID, id, c.QueryInt("limit", 50), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar mensagens"})
+	}
+	return c.JSON(page)
+}
+
+// POST /messages/conversations/:id/messages
+func (mh *MessagesHandler) Send(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	var req models.SendMessageRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	msg, members, err := mh.service.Send(userID, id, req.Texto)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao enviar mensagem"})
+	}
+
+	// Includes the sender so their other tabs/devices stay in sync.
+	mh.hub.SendToUsers(members, "dm_message", "messages", msg)
+	return c.Status(201).JSON(msg)
+}
+
+// PUT /messages/conversations/:id/read
+func (mh *MessagesHandler) MarkRead(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	var req struct {
+		MessageID int `json:"message_id"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+		}
+	}
+
+	receipt, err := mh.markRead(userID, id, req.MessageID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao marcar como lida"})
+	}
+	if receipt == nil {
+		return c.SendStatus(204)
+	}
+	return c.JSON(receipt)
+}
+
+func (mh *MessagesHandler) markRead(userID, conversationID, messageID int) (*models.ReadReceipt, error) {
+	receipt, members, err := mh.service.MarkRead(userID, conversationID, messageID)
+	if err != nil || receipt == nil {
+		return receipt, err
+	}
+	mh.hub.SendToUsers(members, "dm_read", "messages", receipt)
+	return receipt, nil
+}
+
+// dmPayload is the data of the dm.typing / dm.read WebSocket actions.
+type dmPayload struct {
+	ConversationID int `json:"conversation_id"`
+	MessageID      int `json:"message_id"`
+}
+
+func (mh *MessagesHandler) parseWS(env envelope.Envelope) (dmPayload, bool) {
+	var p dmPayload
+	if env.UserID == 0 {
+		mh.hub.ReplyError(env, 401, "Não autenticado")
+		return p, false
+	}
+	if err := json.Unmarshal(env.Data, &p); err != nil || p.ConversationID <= 0 {
+		mh.hub.ReplyError(env, 400, "conversation_id inválido")
+		return p, false
+	}
+	return p, true
+}
+
+func (mh *MessagesHandler) replyWSError(env envelope.Envelope, err error) {
+	if ae, ok := err.(*apperror.AppError); ok {
+		mh.hub.ReplyError(env, int(ae.Code), ae.Message)
+		return
+	}
+	mh.hub.ReplyError(env, 500, "Erro interno")
+}
+
+// WS dm.typing {conversation_id}
+func (mh *MessagesHandler) wsTyping(env envelope.Envelope) {
+	p, ok := mh.parseWS(env)
+	if !ok {
+		return
+	}
+	others, err := mh.service.Typing(env.UserID, p.ConversationID)
+	if err != nil {
+		mh.replyWSError(env, err)
+		return
+	}
+	mh.hub.SendToUsers(others, "dm_typing", "messages", fiber.Map{
+		"conversation_id": p.ConversationID,
+		"user_id":         env.UserID,
+		"username":        env.Username,
+	})
+	mh.hub.Reply(env, fiber.Map{"ok": true})
+}
+
+// WS dm.read {conversation_id, message_id?}
+func (mh *MessagesHandler) wsRead(env envelope.Envelope) {
+	p, ok := mh.parseWS(env)
+	if !ok {
+		return
+	}
+	receipt, err := mh.markRead(env.UserID, p.ConversationID, p.MessageID)
+	if err != nil {
+		mh.replyWSError(env, err)
+		return
+	}
+	mh.hub.Reply(env, receipt)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"strconv"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ModerationHandler struct {
+	service services.ModerationService
+}
+
+func NewModeration(s services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: s}
+}
+
+// POST /moderation/reports
+func (mh *ModerationHandler) Report(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok || userID == 0 {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	var req models.CreateReportRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	report, err := mh.service.Report(userID, req)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao registrar denúncia"})
+	}
+	return c.Status(201).JSON(fiber.Map{"id": report.ID, "status": report.Status})
+}
+
+// GET /moderation/reports?status=open&cursor= (admin)
+func (mh *ModerationHandler) Queue(c *fiber.Ctx) error {
+	page, err := mh.service.Queue(c.Query("status"), c.QueryInt("limit", 30), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar denúncias"})
+	}
+	return c.JSON(page)
+}
+
+// GET /moderation/reports/:id (admin)
+func (mh *ModerationHandler) GetReport(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	report, err := mh.service.GetReport(id)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar denúncia"})
+	}
+	return c.JSON(report)
+}
+
+// POST /moderation/reports/:id/actions (admin)
+func (mh *ModerationHandler) Act(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	moderatorID, ok := c.Locals("user_id").(int)
+	if !ok || moderatorID == 0 {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	var req models.ModerationActionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	report, err := mh.service.Act(moderatorID, id, req)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao aplicar ação"})
+	}
+	return c.JSON(report)
+}
+
+// GET /moderation/audit?cursor= (admin)
+func (mh *ModerationHandler) AuditLog(c *fiber.Ctx) error {
+	page, err := mh.service.AuditLog(c.QueryInt("limit", 50), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar auditoria"})
+	}
+	return c.JSON(page)
+}
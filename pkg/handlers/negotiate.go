@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"cacc/pkg/models"
+	socialpb "cacc/proto/socialpb"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+const mimeProtobuf = "application/x-protobuf"
+
+// wantsProto tells whether the client prefers protobuf over JSON. Without an
+// Accept header, or with */*, the answer stays JSON.
+func wantsProto(c *fiber.Ctx) bool {
+	return c.Accepts(fiber.MIMEApplicationJSON, mimeProtobuf) == mimeProtobuf
+}
+
+// negotiate answers v as JSON, or msg() as protobuf for Accept:
+// application/x-protobuf, keeping whatever status was already set. Errors
+// are always JSON.
+func negotiate(c *fiber.Ctx, v interface{}, msg func() proto.Message) error {
+	c.Vary(fiber.HeaderAccept)
+	if !wantsProto(c) {
+		return c.JSON(v)
+	}
+	data, err := proto.Marshal(msg())
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao serializar resposta"})
+	}
+	c.Set(fiber.HeaderContentType, mimeProtobuf)
+	return c.Send(data)
+}
+
+func feedResponse(page models.Page[models.Post]) func() proto.Message {
+	return func() proto.Message {
+		return &socialpb.FeedResponse{Posts: models.PostsToProto(page.Items), NextCursor: page.NextCursor}
+	}
+}
+
+func postResponse(post models.Post) func() proto.Message {
+	return func() proto.Message {
+		return &socialpb.PostResponse{Post: post.ToProto()}
+	}
+}
+
+func likeResponse(res map[string]interface{}) func() proto.Message {
+	return func() proto.Message { return likeResult(res) }
+}
+
+// likeResult reads the map the reaction endpoints return.
+func likeResult(res map[string]interface{}) *socialpb.LikeResponse {
+	postID, _ := res["post_id"].(int)
+	likes, _ := res["likes"].(int)
+	emoji, _ := res["emoji"].(string)
+	reactions, _ := res["reactions"].([]models.ReactionCount)
+	return &socialpb.LikeResponse{Result: &socialpb.LikeResult{
+		PostId:    int32(postID),
+		Likes:     int32(likes),
+		Emoji:     emoji,
+		Reactions: models.ReactionsToProto(reactions),
+	}}
+}
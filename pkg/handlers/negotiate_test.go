@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"cacc/pkg/models"
+	socialpb "cacc/proto/socialpb"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiate(t *testing.T) {
+	page := models.Page[models.Post]{Items: []models.Post{{ID: 1, Texto: "oi"}}, NextCursor: "abc"}
+	app := fiber.New()
+	app.Get("/feed", func(c *fiber.Ctx) error {
+		return negotiate(c.Status(201), page, feedResponse(page))
+	})
+
+	cases := []struct {
+		accept, contentType string
+	}{
+		{"", fiber.MIMEApplicationJSON},
+		{"*/*", fiber.MIMEApplicationJSON},
+		{"application/json", fiber.MIMEApplicationJSON},
+		{mimeProtobuf, mimeProtobuf},
+		{"application/json;q=0.5, application/x-protobuf", mimeProtobuf},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/feed", nil)
+		if tc.accept != "" {
+			req.Header.Set("Accept", tc.accept)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("Accept %q: %v", tc.accept, err)
+		}
+		if resp.StatusCode != 201 {
+			t.Errorf("Accept %q: status %d, esperado 201", tc.accept, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != tc.contentType && ct != tc.contentType+"; charset=utf-8" {
+			t.Errorf("Accept %q: Content-Type %q, esperado %q", tc.accept, ct, tc.contentType)
+		}
+		if resp.Header.Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: faltou Vary: Accept", tc.accept)
+		}
+		if tc.contentType != mimeProtobuf {
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		var feed socialpb.FeedResponse
+		if err := proto.Unmarshal(body, &feed); err != nil {
+			t.Fatalf("corpo não é protobuf: %v", err)
+		}
+		if len(feed.Posts) != 1 || feed.Posts[0].Texto != "oi" || feed.NextCursor != "abc" {
+			t.Errorf("FeedResponse inesperado: %v", &feed)
+		}
+	}
+}
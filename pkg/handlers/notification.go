@@ -1,62 +1,217 @@
 package handlers
 
 import (
+	"cacc/pkg/apperror"
 	"cacc/pkg/models"
-	"cacc/pkg/repository"
+	"cacc/pkg/services"
+	"fmt"
+	"html"
+	"net/url"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type NotificationHandler struct {
-	repo repository.NotificationRepository
+	service services.NotificationService
+	digest  services.DigestService
 }
 
-func NewNotification(repo repository.NotificationRepository) *NotificationHandler {
-	return &NotificationHandler{repo: repo}
+func NewNotification(s services.NotificationService, digest services.DigestService) *NotificationHandler {
+	return &NotificationHandler{service: s, digest: digest}
 }
 
+// GET /notifications?cursor= — não marca nada como lido
 func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(int)
 	if !ok {
 		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
 	}
 
-	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	offset, _ := strconv.Atoi(c.Query("offset", "0"))
+	page, err := h.service.List(userID, c.QueryInt("limit", 20), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar notificações"})
+	}
+	return c.JSON(page)
+}
+
+// GET /notifications/unread
+func (h *NotificationHandler) UnreadCount(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+	n, err := h.service.UnreadCount(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao contar notificações"})
+	}
+	return c.JSON(fiber.Map{"unread": n})
+}
+
+// PUT /notifications/read — tudo, ou {"up_to_id": N} para N e as anteriores
+func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
 
-	if limit <= 0 || limit > 50 {
-		limit = 20
+	var req struct {
+		UpToID int `json:"up_to_id"`
 	}
-	if offset < 0 {
-		offset = 0
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+		}
+	}
+
+	if err := h.service.MarkRead(userID, req.UpToID, true); err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao atualizar"})
+	}
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// PUT /notifications/:id/read
+func (h *NotificationHandler) MarkOneAsRead(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	if err := h.service.MarkRead(userID, id, false); err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao atualizar"})
+	}
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// GET /notifications/preferences
+func (h *NotificationHandler) Preferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
 	}
 
-	notifs, err := h.repo.GetNotifications(userID, limit, offset)
+	prefs, err := h.service.Preferences(userID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar notificações"})
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar preferências"})
 	}
+	return c.JSON(prefs)
+}
 
-	// Mark as read after fetching
-	go h.repo.MarkAsRead(userID)
+// PUT /notifications/preferences — [{"category": "likes", "email": false}, ...]
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
 
-	if notifs == nil {
-		notifs = []models.Notification{} // To return empty array instead of null
+	var req []models.NotificationPreferenceUpdate
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
 	}
 
-	return c.JSON(notifs)
+	prefs, err := h.service.UpdatePreferences(userID, req)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao salvar preferências"})
+	}
+	return c.JSON(prefs)
 }
 
-func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
+// GET /notifications/digest
+func (h *NotificationHandler) DigestSettings(c *fiber.Ctx) error {
 	userID, ok := c.Locals("user_id").(int)
 	if !ok {
 		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
 	}
 
-	err := h.repo.MarkAsRead(userID)
+	settings, err := h.digest.Settings(userID)
 	if err != nil {
-		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao atualizar"})
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar resumo por e-mail"})
 	}
+	return c.JSON(settings)
+}
 
-	return c.JSON(fiber.Map{"status": "ok"})
+// PUT /notifications/digest — {"frequency": "off" | "daily" | "weekly"}
+func (h *NotificationHandler) UpdateDigestSettings(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	var req models.DigestSettings
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	settings, err := h.digest.UpdateSettings(userID, req.Frequency)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao salvar resumo por e-mail"})
+	}
+	return c.JSON(settings)
+}
+
+// GET /notifications/digest/unsubscribe?token= — link do e-mail, sem login.
+// Só confirma: leitores de e-mail e antivírus abrem links sozinhos, então
+// quem cancela é o POST do botão (ou o one-click do RFC 8058).
+func (h *NotificationHandler) ConfirmUnsubscribeDigest(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if err := h.digest.CheckUnsubscribe(token); err != nil {
+		return c.Status(400).Type("html").SendString(unsubscribePage("Link inválido",
+			"Este link de cancelamento não é válido. Você pode desligar o resumo nas preferências do portal.", ""))
+	}
+	return c.Type("html").SendString(unsubscribePage("Cancelar resumo por e-mail",
+		"Você deixará de receber o resumo de notificações por e-mail.", token))
+}
+
+// POST /notifications/digest/unsubscribe?token= — botão da página acima ou
+// one-click do cliente de e-mail
+func (h *NotificationHandler) UnsubscribeDigest(c *fiber.Ctx) error {
+	if err := h.digest.Unsubscribe(c.Query("token")); err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao cancelar inscrição"})
+	}
+	if c.FormValue("confirmar") != "" {
+		return c.Type("html").SendString(unsubscribePage("Inscrição cancelada",
+			"Você não vai mais receber o resumo por e-mail.", ""))
+	}
+	return c.JSON(fiber.Map{"status": "ok", "mensagem": "Você não vai mais receber o resumo por e-mail"})
+}
+
+// unsubscribePage is the bare page behind the e-mail link; with a token it
+// carries the form that confirms the unsubscribe.
+func unsubscribePage(title, text, token string) string {
+	form := ""
+	if token != "" {
+		form = fmt.Sprintf(`<form method="post" action="?token=%s"><button type="submit" name="confirmar" value="1">Cancelar inscrição</button></form>`,
+			html.EscapeString(url.QueryEscape(token)))
+	}
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>%s</title></head>
+<body style="font-family:sans-serif;max-width:480px;margin:48px auto;padding:0 16px">
+<h1 style="font-size:20px">%s</h1>
+<p>%s</p>
+%s
+</body>
+</html>`, html.EscapeString(title), html.EscapeString(title), html.EscapeString(text), form)
 }
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type fakeDigest struct {
+	services.DigestService
+	unsubscribed int
+}
+
+func (f *fakeDigest) CheckUnsubscribe(token string) error {
+	if token != "1.ok" {
+		return apperror.Validation("link de cancelamento inválido")
+	}
+	return nil
+}
+
+func (f *fakeDigest) Unsubscribe(token string) error {
+	if err := f.CheckUnsubscribe(token); err != nil {
+		return err
+	}
+	f.unsubscribed++
+	return nil
+}
+
+func TestUnsubscribeDigestOnlyOnPost(t *testing.T) {
+	digest := &fakeDigest{}
+	h := NewNotification(nil, digest)
+	app := fiber.New()
+	app.Get("/unsubscribe", h.ConfirmUnsubscribeDigest)
+	app.Post("/unsubscribe", h.UnsubscribeDigest)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/unsubscribe?token=1.ok", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 || digest.unsubscribed != 0 {
+		t.Fatalf("GET: status %d, %d cancelamentos; esperado 200 e nenhum", resp.StatusCode, digest.unsubscribed)
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/unsubscribe?token=x", nil))
+	if resp.StatusCode != 400 {
+		t.Errorf("GET com token inválido: status %d, esperado 400", resp.StatusCode)
+	}
+
+	req := httptest.NewRequest("POST", "/unsubscribe?token=1.ok", strings.NewReader("List-Unsubscribe=One-Click"))
+	req.Header.Set("Content-Type", fiber.MIMEApplicationForm)
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 || digest.unsubscribed != 1 {
+		t.Errorf("POST: status %d, %d cancelamentos; esperado 200 e um", resp.StatusCode, digest.unsubscribed)
+	}
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/services"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SearchHandler struct {
+	service services.SearchService
+}
+
+func NewSearch(s services.SearchService) *SearchHandler {
+	return &SearchHandler{service: s}
+}
+
+// GET /search?q=&type=posts|users|noticias&limit=20&cursor=
+func (sh *SearchHandler) Search(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.Search(c.Query("q"), c.Query("type"), c.QueryInt("limit", 20), c.Query("cursor"), userID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao buscar"})
+	}
+
+	return c.JSON(page)
+}
@@ -1,13 +1,23 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"cacc/pkg/apperror"
 	"cacc/pkg/hub"
+	"cacc/pkg/models"
 	"cacc/pkg/services"
+	socialpb "cacc/proto/socialpb"
 
 	"github.com/gofiber/fiber/v2"
+	"google.golang.org/protobuf/proto"
 )
 
 type SocialHandler struct {
@@ -23,18 +33,21 @@ func NewSocial(h *hub.Hub, s services.SocialService) *SocialHandler {
 // FEED & THREADS
 // ──────────────────────────────────────────────
 
-// GET /social/feed
+// GET /social/feed?mode=global|following&cursor=
 func (sh *SocialHandler) Feed(c *fiber.Ctx) error {
 	limit := c.QueryInt("limit", 30)
-	offset := c.QueryInt("offset", 0)
+	mode := c.Query("mode", services.FeedModeGlobal)
 	userID, _ := c.Locals("user_id").(int)
 
-	posts, err := sh.service.Feed(limit, offset, userID)
+	page, err := sh.service.Feed(mode, limit, c.Query("cursor"), userID)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar feed"})
 	}
 
-	return c.JSON(posts)
+	return negotiate(c, page, feedResponse(page))
 }
 
 // GET /social/feed/:id
@@ -48,13 +61,36 @@ func (sh *SocialHandler) Thread(c *fiber.Ctx) error {
 
 	post, err := sh.service.Thread(postID, userID)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		if err.Error() == "sql: no rows in result set" {
 			return c.Status(404).JSON(fiber.Map{"erro": "Post não encontrado"})
 		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar post"})
 	}
 
-	return c.JSON(post)
+	return negotiate(c, post, func() proto.Message {
+		return &socialpb.ThreadResponse{Post: post.ToProto()}
+	})
+}
+
+// GET /social/feed/:id/replies?cursor= — respostas diretas, mais antigas primeiro
+func (sh *SocialHandler) Replies(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.Replies(postID, userID, c.QueryInt("limit", 20), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar respostas"})
+	}
+	return negotiate(c, page, feedResponse(page))
 }
 
 // ──────────────────────────────────────────────
@@ -79,7 +115,24 @@ func (sh *SocialHandler) Profile(c *fiber.Ctx) error {
 		return c.Status(404).JSON(fiber.Map{"erro": "Perfil não encontrado"})
 	}
 
-	return c.JSON(profile)
+	return negotiate(c, profile, func() proto.Message {
+		return &socialpb.ProfileResponse{Profile: profile.ToProto()}
+	})
+}
+
+// GET /social/profile/:username/posts?cursor=
+func (sh *SocialHandler) ProfilePosts(c *fiber.Ctx) error {
+	requestingUserID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.ProfilePosts(c.Params("username"), requestingUserID, c.QueryInt("limit", 30), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar posts"})
+	}
+
+	return negotiate(c, page, feedResponse(page))
 }
 
 // PUT /social/profile
@@ -127,6 +180,264 @@ func (sh *SocialHandler) UpdateProfile(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"status": "ok"})
 }
 
+// ──────────────────────────────────────────────
+// BOOKMARKS
+// ──────────────────────────────────────────────
+
+// GET /social/bookmarks?cursor=
+func (sh *SocialHandler) Bookmarks(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	page, err := sh.service.Bookmarks(userID, c.QueryInt("limit", 30), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar posts salvos"})
+	}
+	return negotiate(c, page, feedResponse(page))
+}
+
+// PUT /social/feed/:id/bookmark
+func (sh *SocialHandler) BookmarkPost(c *fiber.Ctx) error {
+	return sh.handlePrivateToggle(c, sh.service.Bookmark)
+}
+
+// DELETE /social/feed/:id/bookmark
+func (sh *SocialHandler) UnbookmarkPost(c *fiber.Ctx) error {
+	return sh.handlePrivateToggle(c, sh.service.Unbookmark)
+}
+
+// PUT /social/feed/:id/pin
+func (sh *SocialHandler) PinPost(c *fiber.Ctx) error {
+	return sh.handlePrivateToggle(c, sh.service.PinPost)
+}
+
+// DELETE /social/feed/:id/pin
+func (sh *SocialHandler) UnpinPost(c *fiber.Ctx) error {
+	return sh.handlePrivateToggle(c, sh.service.UnpinPost)
+}
+
+// handlePrivateToggle runs a per-user action on a post (bookmark, profile
+// pin). Unlike likes, nothing is broadcast.
+func (sh *SocialHandler) handlePrivateToggle(c *fiber.Ctx, serviceAction func(int, int) (map[string]interface{}, error)) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	res, err := serviceAction(userID, postID)
+	if err != nil {
+		return respondErr(c, err)
+	}
+	return c.JSON(res)
+}
+
+// GET /social/announcements
+func (sh *SocialHandler) Announcements(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+	posts, err := sh.service.Announcements(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar avisos"})
+	}
+	return c.JSON(posts)
+}
+
+// PUT /social/announcements/:id (admin) {"duration": "7d"}
+func (sh *SocialHandler) PinAnnouncement(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	adminID, _ := c.Locals("user_id").(int)
+
+	var req struct {
+		Duration string `json:"duration"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+		}
+	}
+
+	post, err := sh.service.PinAnnouncement(adminID, postID, req.Duration)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao fixar aviso"})
+	}
+
+	sh.broadcastAnnouncements()
+	return c.JSON(post)
+}
+
+// DELETE /social/announcements/:id (admin)
+func (sh *SocialHandler) UnpinAnnouncement(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	if err := sh.service.UnpinAnnouncement(postID); err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao remover aviso"})
+	}
+
+	sh.broadcastAnnouncements()
+	return c.JSON(fiber.Map{"status": "unpinned", "post_id": postID})
+}
+
+// broadcastAnnouncements sends the current announcements as an anonymous
+// viewer sees them; clients refetch the feed head for their own view.
+func (sh *SocialHandler) broadcastAnnouncements() {
+	go func() {
+		posts, err := sh.service.Announcements(0)
+		if err == nil {
+			sh.hub.Broadcast("announcements_updated", "social", posts)
+		}
+	}()
+}
+
+// ──────────────────────────────────────────────
+// FOLLOW GRAPH
+// ──────────────────────────────────────────────
+
+// PUT /social/profile/:username/follow
+func (sh *SocialHandler) Follow(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Follow)
+}
+
+// DELETE /social/profile/:username/follow
+func (sh *SocialHandler) Unfollow(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Unfollow)
+}
+
+func (sh *SocialHandler) handleFollowRequest(c *fiber.Ctx, serviceAction func(int, string) (map[string]interface{}, error)) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok || userID == 0 {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	res, err := serviceAction(userID, c.Params("username"))
+	if err != nil {
+		return respondErr(c, err)
+	}
+	return c.JSON(res)
+}
+
+// PUT /social/profile/:username/block
+func (sh *SocialHandler) Block(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Block)
+}
+
+// DELETE /social/profile/:username/block
+func (sh *SocialHandler) Unblock(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Unblock)
+}
+
+// PUT /social/profile/:username/mute
+func (sh *SocialHandler) Mute(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Mute)
+}
+
+// DELETE /social/profile/:username/mute
+func (sh *SocialHandler) Unmute(c *fiber.Ctx) error {
+	return sh.handleFollowRequest(c, sh.service.Unmute)
+}
+
+// GET /social/blocks
+func (sh *SocialHandler) Blocks(c *fiber.Ctx) error {
+	return sh.handleOwnList(c, sh.service.Blocks)
+}
+
+// GET /social/mutes
+func (sh *SocialHandler) Mutes(c *fiber.Ctx) error {
+	return sh.handleOwnList(c, sh.service.Mutes)
+}
+
+func (sh *SocialHandler) handleOwnList(c *fiber.Ctx, load func(userID, limit, offset int) ([]models.UserSummary, error)) error {
+	userID, ok := c.Locals("user_id").(int)
+	if !ok || userID == 0 {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	users, err := load(userID, c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar lista"})
+	}
+	return c.JSON(users)
+}
+
+// GET /social/profile/:username/followers
+func (sh *SocialHandler) Followers(c *fiber.Ctx) error {
+	users, err := sh.service.Followers(c.Params("username"), c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		return respondErr(c, err)
+	}
+	return c.JSON(users)
+}
+
+// GET /social/profile/:username/following
+func (sh *SocialHandler) Following(c *fiber.Ctx) error {
+	users, err := sh.service.Following(c.Params("username"), c.QueryInt("limit", 50), c.QueryInt("offset", 0))
+	if err != nil {
+		return respondErr(c, err)
+	}
+	return c.JSON(users)
+}
+
+// ──────────────────────────────────────────────
+// HASHTAGS & TRENDING
+// ──────────────────────────────────────────────
+
+// GET /social/tags/:tag?cursor=
+func (sh *SocialHandler) TagTimeline(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.TagTimeline(c.Params("tag"), c.QueryInt("limit", 30), c.Query("cursor"), userID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar hashtag"})
+	}
+	return negotiate(c, page, feedResponse(page))
+}
+
+// GET /social/trending?limit=10
+func (sh *SocialHandler) Trending(c *fiber.Ctx) error {
+	return c.JSON(sh.service.Trending(c.QueryInt("limit", 10)))
+}
+
+// WatchTrending recomputes the top hashtags every interval and broadcasts
+// trending_updated when the ranking changes. Decay alone can reorder tags,
+// so this runs on a timer instead of only after new posts.
+func (sh *SocialHandler) WatchTrending(interval time.Duration) {
+	var last string
+	for range time.Tick(interval) {
+		trending := sh.service.Trending(10)
+		tags := make([]string, len(trending))
+		for i, t := range trending {
+			tags[i] = t.Tag
+		}
+		if current := strings.Join(tags, ","); current != last {
+			last = current
+			sh.hub.Broadcast("trending_updated", "social", trending)
+		}
+	}
+}
+
 // ──────────────────────────────────────────────
 // POSTA & INTERACTIONS
 // ──────────────────────────────────────────────
@@ -139,27 +450,32 @@ func (sh *SocialHandler) CreatePost(c *fiber.Ctx) error {
 	}
 	username, _ := c.Locals("username").(string)
 
-	var req struct {
-		Texto string `json:"texto"`
-	}
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	body, err := parsePostBody(c)
+	if err != nil {
+		return respondErr(c, err)
 	}
-	texto := strings.TrimSpace(req.Texto)
-	if texto == "" {
+	if body.empty() {
 		return c.Status(400).JSON(fiber.Map{"erro": "Post vazio"})
 	}
-	if len(texto) > 5000 {
+	if len(body.Texto) > 5000 {
 		return c.Status(400).JSON(fiber.Map{"erro": "Post muito longo"})
 	}
 
-	post, err := sh.service.CreatePost(texto, username, userID)
+	post, err := sh.service.CreatePost(body.Texto, username, userID, body.Files, body.Poll)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao salvar post"})
 	}
 
+	// Posts retidos pelo filtro só aparecem depois da moderação.
+	if post.Held {
+		return negotiate(c.Status(202), post, postResponse(post))
+	}
 	go sh.hub.Broadcast("new_post", "social", post)
-	return c.Status(201).JSON(post)
+	sh.unfurl(post)
+	return negotiate(c.Status(201), post, postResponse(post))
 }
 
 // POST /social/feed/:id/reply
@@ -175,30 +491,122 @@ func (sh *SocialHandler) CreateReply(c *fiber.Ctx) error {
 	}
 	username, _ := c.Locals("username").(string)
 
-	var req struct {
-		Texto string `json:"texto"`
-	}
-	if err := c.BodyParser(&req); err != nil {
-		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	body, err := parsePostBody(c)
+	if err != nil {
+		return respondErr(c, err)
 	}
-	texto := strings.TrimSpace(req.Texto)
-	if texto == "" {
+	if body.empty() {
 		return c.Status(400).JSON(fiber.Map{"erro": "Comentário vazio"})
 	}
 
-	reply, err := sh.service.CreateReply(texto, username, userID, parentID)
+	reply, err := sh.service.CreateReply(body.Texto, username, userID, parentID, body.Files, body.Poll)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao criar comentário"})
 	}
 
+	if reply.Held {
+		return negotiate(c.Status(202), reply, postResponse(reply))
+	}
 	go sh.hub.Broadcast("new_reply", "social", fiber.Map{
 		"reply":     reply,
 		"parent_id": parentID,
 	})
-	return c.Status(201).JSON(reply)
+	sh.unfurl(reply)
+	return negotiate(c.Status(201), reply, postResponse(reply))
+}
+
+// unfurl resolves the preview of the first link in a new or edited post in
+// the background and pushes post_preview_ready when it is available.
+func (sh *SocialHandler) unfurl(post models.Post) {
+	go func() {
+		preview, err := sh.service.ResolvePreview(post.ID, post.UserID, post.Texto)
+		if err != nil || preview == nil {
+			return
+		}
+		sh.hub.Broadcast("post_preview_ready", "social", fiber.Map{
+			"post_id": post.ID,
+			"user_id": post.UserID,
+			"preview": preview,
+		})
+	}()
 }
 
-// POST /social/feed/:id/repost
+// postBody is what CreatePost and CreateReply read from the request.
+type postBody struct {
+	Texto string
+	Files []services.MediaFile
+	Poll  *models.PollRequest
+}
+
+// parsePostBody aceita JSON ({"texto": ..., "poll": {...}}) ou
+// multipart/form-data com "texto", um ou mais "files", opcionalmente um
+// "alt" por arquivo na mesma ordem e "poll" com o mesmo JSON da enquete.
+func parsePostBody(c *fiber.Ctx) (postBody, error) {
+	if !strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+		var req struct {
+			Texto string              `json:"texto"`
+			Poll  *models.PollRequest `json:"poll"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return postBody{}, apperror.Validation("JSON inválido")
+		}
+		return postBody{Texto: strings.TrimSpace(req.Texto), Poll: req.Poll}, nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return postBody{}, apperror.Validation("Formulário inválido")
+	}
+	var body postBody
+	if v := form.Value["texto"]; len(v) > 0 {
+		body.Texto = strings.TrimSpace(v[0])
+	}
+	if v := form.Value["poll"]; len(v) > 0 && strings.TrimSpace(v[0]) != "" {
+		if err := json.Unmarshal([]byte(v[0]), &body.Poll); err != nil {
+			return postBody{}, apperror.Validation("Enquete inválida")
+		}
+	}
+	alts := form.Value["alt"]
+
+	files := form.File["files"]
+	if limit := services.PostMaxAttachments(); len(files) > limit {
+		return postBody{}, apperror.Validation(fmt.Sprintf("no máximo %d anexos por post", limit))
+	}
+	maxSize := services.MaxMediaSize()
+	for _, fh := range files {
+		if fh.Size > maxSize {
+			return postBody{}, apperror.Validation(fmt.Sprintf("%s muito grande (máx %d MB)", filepath.Base(fh.Filename), maxSize/(1024*1024)))
+		}
+	}
+
+	for i, fh := range files {
+		f, err := fh.Open()
+		if err != nil {
+			return postBody{}, apperror.Validation("Erro ao abrir arquivo")
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return postBody{}, apperror.Validation("Erro ao ler arquivo")
+		}
+		mf := services.MediaFile{Data: data, FileName: fh.Filename}
+		if i < len(alts) {
+			mf.AltText = strings.TrimSpace(alts[i])
+		}
+		body.Files = append(body.Files, mf)
+	}
+	return body, nil
+}
+
+// empty reports whether the post has nothing to show.
+func (b postBody) empty() bool {
+	return b.Texto == "" && len(b.Files) == 0 && b.Poll == nil
+}
+
+// POST /social/feed/:id/repost  (body opcional: {"texto": "..."} para citar)
 func (sh *SocialHandler) CreateRepost(c *fiber.Ctx) error {
 	repostID, err := strconv.Atoi(c.Params("id"))
 	if err != nil {
@@ -210,14 +618,59 @@ func (sh *SocialHandler) CreateRepost(c *fiber.Ctx) error {
 		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
 	}
 
-	// Will add this to service interface next.
-	post, err := sh.service.CreateRepost(userID, repostID)
+	var req struct {
+		Texto string `json:"texto"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+		}
+	}
+	texto := strings.TrimSpace(req.Texto)
+	if len(texto) > 5000 {
+		return c.Status(400).JSON(fiber.Map{"erro": "Post muito longo"})
+	}
+
+	post, err := sh.service.CreateRepost(userID, repostID, texto)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao fazer repost"})
 	}
 
+	if post.Held {
+		return negotiate(c.Status(202), post, postResponse(post))
+	}
 	go sh.hub.Broadcast("new_post", "social", post)
-	return c.Status(201).JSON(post)
+	sh.unfurl(post)
+	return negotiate(c.Status(201), post, postResponse(post))
+}
+
+// DELETE /social/feed/:id/repost
+func (sh *SocialHandler) UndoRepost(c *fiber.Ctx) error {
+	repostID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	res, err := sh.service.UndoRepost(userID, repostID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao desfazer repost"})
+	}
+
+	go sh.hub.Broadcast("post_deleted", "social", fiber.Map{
+		"post_id": res["deleted_id"],
+	})
+	return c.JSON(res)
 }
 
 func (sh *SocialHandler) LikePost(c *fiber.Ctx) error {
@@ -241,11 +694,214 @@ func (sh *SocialHandler) handleLikeRequest(c *fiber.Ctx, serviceAction func(int,
 
 	res, err := serviceAction(userID, postID)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": err.Error()})
 	}
 
-	go sh.hub.Broadcast("post_liked", "social", res)
-	return c.JSON(res)
+	go sh.broadcastReaction(res)
+	return negotiate(c, res, likeResponse(res))
+}
+
+// PUT /social/feed/:id/reactions/:emoji
+func (sh *SocialHandler) React(c *fiber.Ctx) error {
+	return sh.handleReactionRequest(c, sh.service.React)
+}
+
+// DELETE /social/feed/:id/reactions/:emoji
+func (sh *SocialHandler) Unreact(c *fiber.Ctx) error {
+	return sh.handleReactionRequest(c, sh.service.Unreact)
+}
+
+func (sh *SocialHandler) handleReactionRequest(c *fiber.Ctx, serviceAction func(int, int, string) (map[string]interface{}, error)) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	emoji, err := url.PathUnescape(c.Params("emoji"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "reação inválida"})
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	res, err := serviceAction(userID, postID, emoji)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": err.Error()})
+	}
+
+	go sh.broadcastReaction(res)
+	return negotiate(c, res, likeResponse(res))
+}
+
+// broadcastReaction sends post_reacted for every reaction change and, for
+// 👍, the legacy post_liked as well.
+func (sh *SocialHandler) broadcastReaction(res map[string]interface{}) {
+	sh.hub.Broadcast("post_reacted", "social", fiber.Map{
+		"post_id":   res["post_id"],
+		"emoji":     res["emoji"],
+		"reactions": res["reactions"],
+	})
+	if res["emoji"] == models.ReactionLike {
+		sh.hub.Broadcast("post_liked", "social", fiber.Map{"post_id": res["post_id"], "likes": res["likes"]})
+	}
+}
+
+// GET /social/feed/:id/likes?cursor=
+func (sh *SocialHandler) Likers(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.Likers(postID, userID, c.QueryInt("limit", 50), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar curtidas"})
+	}
+	return c.JSON(page)
+}
+
+// GET /social/feed/:id/reactions?emoji=&cursor=
+func (sh *SocialHandler) Reactors(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	page, err := sh.service.Reactors(postID, userID, c.Query("emoji"), c.QueryInt("limit", 50), c.Query("cursor"))
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar reações"})
+	}
+	return c.JSON(page)
+}
+
+// PUT /social/feed/:id
+func (sh *SocialHandler) EditPost(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	var req struct {
+		Texto string `json:"texto"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+	texto := strings.TrimSpace(req.Texto)
+	if texto == "" {
+		return c.Status(400).JSON(fiber.Map{"erro": "Post vazio"})
+	}
+	if len(texto) > 5000 {
+		return c.Status(400).JSON(fiber.Map{"erro": "Post muito longo"})
+	}
+
+	post, err := sh.service.Edit(userID, postID, texto)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao editar post"})
+	}
+
+	if post.Held {
+		return negotiate(c.Status(202), post, postResponse(post))
+	}
+	go sh.hub.Broadcast("post_edited", "social", post)
+	sh.unfurl(post)
+	return negotiate(c, post, postResponse(post))
+}
+
+// GET /social/feed/:id/poll
+func (sh *SocialHandler) Poll(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	poll, err := sh.service.Poll(postID, userID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar enquete"})
+	}
+	return c.JSON(poll)
+}
+
+// POST /social/feed/:id/vote
+func (sh *SocialHandler) Vote(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+
+	userID, ok := c.Locals("user_id").(int)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"erro": "Não autenticado"})
+	}
+
+	var req struct {
+		Options []int `json:"options"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "JSON inválido"})
+	}
+
+	poll, err := sh.service.Vote(userID, postID, req.Options)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao registrar voto"})
+	}
+
+	// Só o total de votantes vai para todos: os placares ficam ocultos para
+	// quem ainda não votou, que busca GET /social/feed/:id/poll depois.
+	go sh.hub.Broadcast("poll_voted", "social", fiber.Map{
+		"post_id": postID,
+		"voters":  poll.Voters,
+	})
+	return c.JSON(poll)
+}
+
+// GET /social/feed/:id/history
+func (sh *SocialHandler) History(c *fiber.Ctx) error {
+	postID, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID inválido"})
+	}
+	userID, _ := c.Locals("user_id").(int)
+
+	revisions, err := sh.service.History(postID, userID)
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao carregar histórico"})
+	}
+	return c.JSON(revisions)
 }
 
 func (sh *SocialHandler) DeletePost(c *fiber.Ctx) error {
@@ -270,5 +926,7 @@ func (sh *SocialHandler) DeletePost(c *fiber.Ctx) error {
 	go sh.hub.Broadcast("post_deleted", "social", fiber.Map{
 		"post_id": postID,
 	})
-	return c.JSON(fiber.Map{"status": "deleted", "post_id": postID})
+	return negotiate(c, fiber.Map{"status": "deleted", "post_id": postID}, func() proto.Message {
+		return &socialpb.DeleteResponse{Result: &socialpb.DeleteResult{Id: int32(postID), Status: "deleted"}}
+	})
 }
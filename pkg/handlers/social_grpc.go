@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/envelope"
+	"cacc/pkg/middleware"
+	"cacc/pkg/models"
+	"cacc/pkg/services"
+	socialpb "cacc/proto/socialpb"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// subscribeBuffer is how many events a SubscribeFeed stream may fall behind
+// before it starts missing them.
+const subscribeBuffer = 64
+
+// SocialGRPC serves socialpb.SocialService with the same service, checks
+// and hub events as the REST handlers in social.go.
+type SocialGRPC struct {
+	socialpb.UnimplementedSocialServiceServer
+	sh *SocialHandler
+}
+
+func NewSocialGRPC(sh *SocialHandler) *SocialGRPC {
+	return &SocialGRPC{sh: sh}
+}
+
+func (g *SocialGRPC) Feed(ctx context.Context, req *socialpb.FeedRequest) (*socialpb.FeedResponse, error) {
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = 30
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = services.FeedModeGlobal
+	}
+
+	page, err := g.sh.service.Feed(mode, limit, req.Cursor, middleware.GRPCUserFrom(ctx).UserID)
+	if err != nil {
+		return nil, grpcError(err, "Erro ao carregar feed")
+	}
+	return &socialpb.FeedResponse{Posts: models.PostsToProto(page.Items), NextCursor: page.NextCursor}, nil
+}
+
+func (g *SocialGRPC) Thread(ctx context.Context, req *socialpb.ThreadRequest) (*socialpb.ThreadResponse, error) {
+	post, err := g.sh.service.Thread(int(req.Id), middleware.GRPCUserFrom(ctx).UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Post não encontrado")
+		}
+		return nil, grpcError(err, "Erro ao carregar post")
+	}
+	return &socialpb.ThreadResponse{Post: post.ToProto()}, nil
+}
+
+// Profile without a username is the caller's own profile.
+func (g *SocialGRPC) Profile(ctx context.Context, req *socialpb.ProfileRequest) (*socialpb.ProfileResponse, error) {
+	user := middleware.GRPCUserFrom(ctx)
+	profileUserID := 0
+	if req.Username == "" {
+		if user.UserID == 0 {
+			return nil, status.Error(codes.Unauthenticated, "Não autenticado")
+		}
+		profileUserID = user.UserID
+	}
+
+	profile, err := g.sh.service.Profile(req.Username, profileUserID, user.UserID)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, "Perfil não encontrado")
+	}
+	return &socialpb.ProfileResponse{Profile: profile.ToProto()}, nil
+}
+
+func (g *SocialGRPC) CreatePost(ctx context.Context, req *socialpb.CreatePostRequest) (*socialpb.PostResponse, error) {
+	user, err := requireGRPCUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Texto) == "" {
+		return nil, status.Error(codes.InvalidArgument, "Post vazio")
+	}
+	if len(req.Texto) > 5000 {
+		return nil, status.Error(codes.InvalidArgument, "Post muito longo")
+	}
+
+	post, err := g.sh.service.CreatePost(req.Texto, user.Username, user.UserID, nil, nil)
+	if err != nil {
+		return nil, grpcError(err, "Erro ao salvar post")
+	}
+	if !post.Held {
+		go g.sh.hub.Broadcast("new_post", "social", post)
+		g.sh.unfurl(post)
+	}
+	return &socialpb.PostResponse{Post: post.ToProto()}, nil
+}
+
+func (g *SocialGRPC) Comment(ctx context.Context, req *socialpb.CommentRequest) (*socialpb.PostResponse, error) {
+	user, err := requireGRPCUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(req.Texto) == "" {
+		return nil, status.Error(codes.InvalidArgument, "Comentário vazio")
+	}
+
+	parentID := int(req.ParentId)
+	reply, err := g.sh.service.CreateReply(req.Texto, user.Username, user.UserID, parentID, nil, nil)
+	if err != nil {
+		return nil, grpcError(err, "Erro ao criar comentário")
+	}
+	if !reply.Held {
+		go g.sh.hub.Broadcast("new_reply", "social", fiber.Map{
+			"reply":     reply,
+			"parent_id": parentID,
+		})
+		g.sh.unfurl(reply)
+	}
+	return &socialpb.PostResponse{Post: reply.ToProto()}, nil
+}
+
+func (g *SocialGRPC) Like(ctx context.Context, req *socialpb.LikeRequest) (*socialpb.LikeResponse, error) {
+	return g.like(ctx, req, g.sh.service.Like)
+}
+
+func (g *SocialGRPC) Unlike(ctx context.Context, req *socialpb.LikeRequest) (*socialpb.LikeResponse, error) {
+	return g.like(ctx, req, g.sh.service.Unlike)
+}
+
+func (g *SocialGRPC) like(ctx context.Context, req *socialpb.LikeRequest, serviceAction func(int, int) (map[string]interface{}, error)) (*socialpb.LikeResponse, error) {
+	user, err := requireGRPCUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := serviceAction(user.UserID, int(req.Id))
+	if err != nil {
+		return nil, grpcError(err, "Erro ao curtir")
+	}
+	go g.sh.broadcastReaction(res)
+	return likeResult(res), nil
+}
+
+func (g *SocialGRPC) DeletePost(ctx context.Context, req *socialpb.DeletePostRequest) (*socialpb.DeleteResponse, error) {
+	user, err := requireGRPCUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	postID := int(req.Id)
+	if err := g.sh.service.Delete(user.UserID, postID); err != nil {
+		if err.Error() == "post não encontrado ou sem permissão" {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, grpcError(err, "Erro ao remover")
+	}
+
+	go g.sh.hub.Broadcast("post_deleted", "social", fiber.Map{
+		"post_id": postID,
+	})
+	return &socialpb.DeleteResponse{Result: &socialpb.DeleteResult{Id: int32(postID), Status: "deleted"}}, nil
+}
+
+// SubscribeFeed streams the hub's "social" events until the client goes
+// away. Like the WebSocket, events are not replayed: a client that
+// reconnects reloads the feed with Feed. Events by an author blocked with
+// the caller are dropped, as in the feed itself.
+func (g *SocialGRPC) SubscribeFeed(req *socialpb.SubscribeFeedRequest, stream socialpb.SocialService_SubscribeFeedServer) error {
+	ctx := stream.Context()
+	wanted := make(map[string]bool, len(req.Actions))
+	for _, a := range req.Actions {
+		wanted[a] = true
+	}
+
+	userID := middleware.GRPCUserFrom(ctx).UserID
+	events, cancel := g.sh.hub.Subscribe(userID, subscribeBuffer)
+	defer cancel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case env, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if env.Service != "social" || (len(wanted) > 0 && !wanted[env.Action]) {
+				continue
+			}
+			ev := feedEvent(env)
+			if userID > 0 && g.sh.service.BlockedWith(eventAuthor(env, ev), userID) {
+				continue
+			}
+			if err := stream.Send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// feedEvent converts a hub event, typing the post it carries when there is
+// one. The raw JSON goes along in data either way.
+func feedEvent(env envelope.Envelope) *socialpb.FeedEvent {
+	ev := &socialpb.FeedEvent{Action: env.Action, Ts: env.Timestamp, Data: env.Data}
+
+	switch env.Action {
+	case "new_post", "post_edited":
+		var post models.Post
+		if json.Unmarshal(env.Data, &post) == nil {
+			ev.Post = post.ToProto()
+			ev.PostId = int32(post.ID)
+		}
+	case "new_reply":
+		var data struct {
+			Reply    models.Post `json:"reply"`
+			ParentID int         `json:"parent_id"`
+		}
+		if json.Unmarshal(env.Data, &data) == nil {
+			ev.Post = data.Reply.ToProto()
+			ev.PostId = int32(data.Reply.ID)
+			ev.ParentId = int32(data.ParentID)
+		}
+	default:
+		var data struct {
+			PostID int `json:"post_id"`
+		}
+		if json.Unmarshal(env.Data, &data) == nil {
+			ev.PostId = int32(data.PostID)
+		}
+	}
+	return ev
+}
+
+// eventAuthor is the user behind an event: the author of the post it
+// carries, or its user_id. 0 when the event has neither.
+func eventAuthor(env envelope.Envelope, ev *socialpb.FeedEvent) int {
+	if ev.Post != nil {
+		return int(ev.Post.UserId)
+	}
+	var data struct {
+		UserID int `json:"user_id"`
+	}
+	json.Unmarshal(env.Data, &data)
+	return data.UserID
+}
+
+func requireGRPCUser(ctx context.Context) (middleware.GRPCUser, error) {
+	user := middleware.GRPCUserFrom(ctx)
+	if user.UserID == 0 {
+		return user, status.Error(codes.Unauthenticated, "Não autenticado")
+	}
+	return user, nil
+}
+
+// grpcError maps an AppError to the matching gRPC code; anything else is
+// Internal with fallback as the message, like the 500s of the REST API.
+func grpcError(err error, fallback string) error {
+	var ae *apperror.AppError
+	if !errors.As(err, &ae) {
+		return status.Error(codes.Internal, fallback)
+	}
+	code := codes.Internal
+	switch ae.Code {
+	case apperror.ErrValidation:
+		code = codes.InvalidArgument
+	case apperror.ErrUnauthorized:
+		code = codes.Unauthenticated
+	case apperror.ErrForbidden:
+		code = codes.PermissionDenied
+	case apperror.ErrNotFound:
+		code = codes.NotFound
+	case apperror.ErrConflict:
+		code = codes.AlreadyExists
+	case apperror.ErrTooMany:
+		code = codes.ResourceExhausted
+	}
+	return status.Error(code, ae.Message)
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/envelope"
+	"cacc/pkg/hub"
+	"cacc/pkg/middleware"
+	"cacc/pkg/models"
+	"cacc/pkg/server"
+	"cacc/pkg/services"
+	socialpb "cacc/proto/socialpb"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestGRPCError(t *testing.T) {
+	cases := []struct {
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{apperror.Validation("texto inválido"), codes.InvalidArgument, "texto inválido"},
+		{apperror.Forbidden("bloqueado"), codes.PermissionDenied, "bloqueado"},
+		{apperror.NotFound("post não encontrado"), codes.NotFound, "post não encontrado"},
+		{apperror.TooMany("calma"), codes.ResourceExhausted, "calma"},
+		{context.DeadlineExceeded, codes.Internal, "Erro ao carregar feed"},
+	}
+	for _, tc := range cases {
+		st := status.Convert(grpcError(tc.err, "Erro ao carregar feed"))
+		if st.Code() != tc.code || st.Message() != tc.msg {
+			t.Errorf("%v: obteve %s %q, esperado %s %q", tc.err, st.Code(), st.Message(), tc.code, tc.msg)
+		}
+	}
+}
+
+func TestFeedEvent(t *testing.T) {
+	post := models.Post{ID: 5, Texto: "oi", Author: "ana", CreatedAt: time.Now()}
+	reply, _ := envelope.NewEvent("new_reply", "social", fiber.Map{"reply": post, "parent_id": 2})
+	ev := feedEvent(reply)
+	if ev.Action != "new_reply" || ev.Post.GetTexto() != "oi" || ev.PostId != 5 || ev.ParentId != 2 || len(ev.Data) == 0 {
+		t.Errorf("new_reply mal convertido: %v", ev)
+	}
+
+	deleted, _ := envelope.NewEvent("post_deleted", "social", fiber.Map{"post_id": 9})
+	if ev := feedEvent(deleted); ev.Post != nil || ev.PostId != 9 {
+		t.Errorf("post_deleted mal convertido: %v", ev)
+	}
+}
+
+// dialSocialGRPC serves SocialGRPC in memory with the real interceptors.
+func dialSocialGRPC(t *testing.T, h *hub.Hub, svc services.SocialService) socialpb.SocialServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := server.NewGRPC()
+	socialpb.RegisterSocialServiceServer(srv, NewSocialGRPC(NewSocial(h, svc)))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return socialpb.NewSocialServiceClient(conn)
+}
+
+func TestSocialGRPCAuth(t *testing.T) {
+	middleware.InitSecrets("segredo-de-teste", "admin")
+	client := dialSocialGRPC(t, hub.New(), nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Sem token: rotas que exigem login respondem Unauthenticated.
+	_, err := client.CreatePost(ctx, &socialpb.CreatePostRequest{Texto: "oi"})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("CreatePost sem token: esperado Unauthenticated, obteve %v", err)
+	}
+
+	// Token inválido é recusado mesmo em rotas abertas.
+	bad := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer nope")
+	_, err = client.Feed(bad, &socialpb.FeedRequest{})
+	if st := status.Convert(err); st.Code() != codes.Unauthenticated || st.Message() != "Token inválido" {
+		t.Errorf("Feed com token inválido: obteve %v", err)
+	}
+
+	// Token válido passa; o texto vazio cai na validação, antes do serviço.
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 7, "username": "ana"}).SignedString([]byte("segredo-de-teste"))
+	good := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	_, err = client.CreatePost(good, &socialpb.CreatePostRequest{Texto: "  "})
+	if st := status.Convert(err); st.Code() != codes.InvalidArgument || st.Message() != "Post vazio" {
+		t.Errorf("CreatePost autenticado e vazio: obteve %v", err)
+	}
+}
+
+func TestSocialGRPCSubscribeFeed(t *testing.T) {
+	h := hub.New()
+	client := dialSocialGRPC(t, h, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := client.SubscribeFeed(ctx, &socialpb.SubscribeFeedRequest{Actions: []string{"new_post", "post_deleted"}})
+	if err != nil {
+		t.Fatalf("SubscribeFeed: %v", err)
+	}
+
+	// A inscrição só existe depois que o servidor recebe a chamada;
+	// repete até o primeiro evento chegar.
+	got := make(chan *socialpb.FeedEvent, 4)
+	go func() {
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				close(got)
+				return
+			}
+			got <- ev
+		}
+	}()
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	var first *socialpb.FeedEvent
+	for first == nil {
+		select {
+		case ev, ok := <-got:
+			if !ok {
+				t.Fatal("stream fechou antes do primeiro evento")
+			}
+			first = ev
+		case <-ticker.C:
+			h.Broadcast("new_post", "social", models.Post{ID: 1, Texto: "primeiro"})
+		case <-ctx.Done():
+			t.Fatal("nenhum evento recebido")
+		}
+	}
+	if first.Action != "new_post" || first.Post.GetTexto() != "primeiro" {
+		t.Errorf("primeiro evento inesperado: %v", first)
+	}
+
+	// Drena os new_post repetidos; fora do filtro ou de outro serviço não chega.
+	h.Broadcast("post_liked", "social", fiber.Map{"post_id": 1, "likes": 3})
+	h.Broadcast("post_deleted", "noticias", fiber.Map{"post_id": 2})
+	h.Broadcast("post_deleted", "social", fiber.Map{"post_id": 1})
+	for {
+		select {
+		case ev, ok := <-got:
+			if !ok {
+				t.Fatal("stream fechou antes do post_deleted")
+			}
+			if ev.Action == "new_post" {
+				continue
+			}
+			if ev.Action != "post_deleted" || ev.PostId != 1 {
+				t.Errorf("evento inesperado: %v", ev)
+			}
+			return
+		case <-ctx.Done():
+			t.Fatal("post_deleted não chegou")
+		}
+	}
+}
+
+// blockingSocial blocks everyone against user 9.
+type blockingSocial struct{ services.SocialService }
+
+func (blockingSocial) BlockedWith(authorID, userID int) bool { return authorID == 9 || userID == 9 }
+
+func TestSocialGRPCSubscribeFeedSkipsBlocked(t *testing.T) {
+	middleware.InitSecrets("segredo-de-teste", "admin")
+	h := hub.New()
+	client := dialSocialGRPC(t, h, blockingSocial{})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": 7, "username": "ana"}).SignedString([]byte("segredo-de-teste"))
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+	stream, err := client.SubscribeFeed(ctx, &socialpb.SubscribeFeedRequest{})
+	if err != nil {
+		t.Fatalf("SubscribeFeed: %v", err)
+	}
+
+	got := make(chan *socialpb.FeedEvent, 16)
+	go func() {
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				close(got)
+				return
+			}
+			got <- ev
+		}
+	}()
+
+	// Até a inscrição existir, manda o post bloqueado e logo depois um
+	// liberado; o bloqueado nunca pode aparecer antes dele.
+	ticker := time.NewTicker(20 * time.Millisecond)
+	defer ticker.Stop()
+	for subscribed := false; !subscribed; {
+		select {
+		case ev, ok := <-got:
+			if !ok {
+				t.Fatal("stream fechou antes do primeiro evento")
+			}
+			if ev.Post.GetUserId() != 3 {
+				t.Fatalf("evento de autor bloqueado entregue: %v", ev)
+			}
+			subscribed = true
+		case <-ticker.C:
+			h.Broadcast("new_post", "social", models.Post{ID: 1, UserID: 9, Texto: "bloqueado"})
+			h.Broadcast("new_post", "social", models.Post{ID: 2, UserID: 3, Texto: "liberado"})
+		case <-ctx.Done():
+			t.Fatal("nenhum evento recebido")
+		}
+	}
+
+	h.Broadcast("profile_updated", "social", fiber.Map{"user_id": 9, "display_name": "x"})
+	h.Broadcast("post_deleted", "social", fiber.Map{"post_id": 5})
+	for {
+		select {
+		case ev, ok := <-got:
+			if !ok {
+				t.Fatal("stream fechou antes do post_deleted")
+			}
+			switch {
+			case ev.Action == "new_post" && ev.Post.GetUserId() == 3:
+				continue
+			case ev.Action != "post_deleted":
+				t.Fatalf("evento inesperado: %v", ev)
+			}
+			return
+		case <-ctx.Done():
+			t.Fatal("post_deleted não chegou")
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
 	"cacc/pkg/services"
 	"strconv"
 	"strings"
@@ -60,14 +62,54 @@ func (sg *SugestoesHandler) Criar(c *fiber.Ctx) error {
 		categoria = "Geral"
 	}
 
-	sugestao, err := sg.service.Criar(texto, username, categoria)
+	// O filtro conta envios por usuário logado, ou por IP quando anônimo.
+	source := services.IPSource(c.IP())
+	if userID, _ := c.Locals("user_id").(int); userID != 0 {
+		source = services.UserSource(userID)
+	}
+
+	sugestao, err := sg.service.Criar(texto, username, categoria, source)
 	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
 		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao salvar"})
 	}
 
+	if sugestao.Pendente {
+		return c.Status(202).JSON(sugestao)
+	}
 	return c.Status(201).JSON(sugestao)
 }
 
+// GET /sugestoes/pendentes (admin)
+func (sg *SugestoesHandler) Pendentes(c *fiber.Ctx) error {
+	lista, err := sg.service.Pendentes()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao buscar sugestões"})
+	}
+	if lista == nil {
+		lista = []models.Sugestao{}
+	}
+	return c.JSON(lista)
+}
+
+// PUT /sugestoes/:id/aprovar (admin)
+func (sg *SugestoesHandler) Aprovar(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"erro": "ID Invalido"})
+	}
+
+	if err := sg.service.Aprovar(id); err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro interno"})
+	}
+	return c.JSON(fiber.Map{"status": "approved"})
+}
+
 // DELETE /sugestoes/:id (admin)
 func (sg *SugestoesHandler) Deletar(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
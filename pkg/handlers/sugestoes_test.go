@@ -26,7 +26,7 @@ func (m *sugestoesServiceMock) Listar() ([]models.Sugestao, error) {
 	return nil, nil
 }
 
-func (m *sugestoesServiceMock) Criar(texto, author, categoria string) (models.Sugestao, error) {
+func (m *sugestoesServiceMock) Criar(texto, author, categoria, source string) (models.Sugestao, error) {
 	m.lastTexto = texto
 	m.lastAuthor = author
 	m.lastCategoria = categoria
@@ -41,6 +41,14 @@ func (m *sugestoesServiceMock) Atualizar(id int, texto, categoria string) error
 	return nil
 }
 
+func (m *sugestoesServiceMock) Pendentes() ([]models.Sugestao, error) {
+	return nil, nil
+}
+
+func (m *sugestoesServiceMock) Aprovar(id int) error {
+	return nil
+}
+
 func TestSugestoesPostSemLogin(t *testing.T) {
 	t.Parallel()
 
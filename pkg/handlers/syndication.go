@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/services"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type SyndicationHandler struct {
+	service services.SyndicationService
+}
+
+func NewSyndication(s services.SyndicationService) *SyndicationHandler {
+	return &SyndicationHandler{service: s}
+}
+
+// GET /social/feed.:format — atom, rss ou json
+func (h *SyndicationHandler) SocialFeed(c *fiber.Ctx) error {
+	feed, err := h.service.SocialFeed(c.Params("format"))
+	return h.send(c, feed, err)
+}
+
+// GET /social/profile/:username/feed.:format
+func (h *SyndicationHandler) ProfileFeed(c *fiber.Ctx) error {
+	feed, err := h.service.ProfileFeed(c.Params("username"), c.Params("format"))
+	return h.send(c, feed, err)
+}
+
+// GET /noticias/feed.:format
+func (h *SyndicationHandler) NoticiasFeed(c *fiber.Ctx) error {
+	feed, err := h.service.NoticiasFeed(c.Params("format"))
+	return h.send(c, feed, err)
+}
+
+// send answers 304 when the reader's If-None-Match / If-Modified-Since
+// still match the feed.
+func (h *SyndicationHandler) send(c *fiber.Ctx, feed models.SyndicationFeed, err error) error {
+	if err != nil {
+		if _, ok := err.(*apperror.AppError); ok {
+			return respondErr(c, err)
+		}
+		return c.Status(500).JSON(fiber.Map{"erro": "Erro ao gerar feed"})
+	}
+
+	c.Set(fiber.HeaderETag, feed.ETag)
+	c.Set(fiber.HeaderLastModified, feed.Updated.UTC().Format(http.TimeFormat))
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	c.Set(fiber.HeaderContentType, feed.ContentType)
+	return c.SendString(feed.Body)
+}
@@ -39,6 +39,15 @@ type Hub struct {
 	// so replies go to the exact socket that sent the request
 	connMu  sync.RWMutex
 	connMap map[string]*clientConn
+
+	// subs are in-process listeners (gRPC SubscribeFeed streams) that get
+	// the same broadcasts as the sockets
+	subs map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	userID int
+	ch     chan envelope.Envelope
 }
 
 func New() *Hub {
@@ -47,6 +56,7 @@ func New() *Hub {
 		byUser:   make(map[int][]*clientConn),
 		handlers: make(map[string]ActionHandler),
 		connMap:  make(map[string]*clientConn),
+		subs:     make(map[*subscriber]struct{}),
 	}
 	go h.cleanupConnMap()
 	return h
@@ -230,6 +240,7 @@ func (h *Hub) Broadcast(action, service string, data interface{}) {
 	for _, cc := range h.clients {
 		cc.send(raw)
 	}
+	h.publish(env, 0)
 }
 
 // BroadcastExcept sends to all clients except the given user
@@ -249,6 +260,60 @@ func (h *Hub) BroadcastExcept(action, service string, data interface{}, exceptUs
 			cc.send(raw)
 		}
 	}
+	h.publish(env, exceptUserID)
+}
+
+// Subscribe registers a listener for everything Broadcast and
+// BroadcastExcept send. The returned cancel must be called when the listener
+// goes away. A listener that falls more than buffer events behind misses
+// events instead of holding up the broadcasts.
+func (h *Hub) Subscribe(userID, buffer int) (<-chan envelope.Envelope, func()) {
+	sub := &subscriber{userID: userID, ch: make(chan envelope.Envelope, buffer)}
+	h.mu.Lock()
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, sub)
+			h.mu.Unlock()
+			close(sub.ch)
+		})
+	}
+}
+
+// publish hands env to the subscribers; callers hold h.mu.
+func (h *Hub) publish(env envelope.Envelope, exceptUserID int) {
+	for sub := range h.subs {
+		if exceptUserID != 0 && sub.userID == exceptUserID {
+			continue
+		}
+		select {
+		case sub.ch <- env:
+		default:
+		}
+	}
+}
+
+// SendToUsers sends an event to every connection of the given users
+func (h *Hub) SendToUsers(userIDs []int, action, service string, data interface{}) {
+	env, err := envelope.NewEvent(action, service, data)
+	if err != nil {
+		return
+	}
+	raw, err := env.Marshal()
+	if err != nil {
+		return
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for _, id := range userIDs {
+		for _, cc := range h.byUser[id] {
+			cc.send(raw)
+		}
+	}
 }
 
 func (h *Hub) ClientCount() int {
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimit refuses a request whose declared Content-Length is over limit,
+// or over uploadLimit when upload(c) holds, before any of the body is read.
+// It needs the app to stream request bodies (see server.NewApp). Chunked
+// bodies carry no length to check, so they are refused with 411. A refused
+// body is never read, so the connection is closed rather than reused.
+func BodyLimit(limit, uploadLimit int, upload func(*fiber.Ctx) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		n := c.Request().Header.ContentLength()
+		if n == -1 {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusLengthRequired).JSON(fiber.Map{"erro": "Content-Length obrigatório"})
+		}
+		allowed := limit
+		if upload != nil && upload(c) {
+			allowed = uploadLimit
+		}
+		if n > allowed {
+			c.Context().SetConnectionClose()
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"erro": "Requisição muito grande"})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCUser is who made a gRPC call, taken from the same JWT the REST API
+// uses. UserID is 0 for anonymous calls.
+type GRPCUser struct {
+	UserID   int
+	UUID     string
+	Username string
+}
+
+type grpcUserKey struct{}
+
+// GRPCUserFrom returns the caller identity the interceptors stored in ctx.
+func GRPCUserFrom(ctx context.Context) GRPCUser {
+	u, _ := ctx.Value(grpcUserKey{}).(GRPCUser)
+	return u
+}
+
+// grpcAuth reads "authorization: Bearer <token>" from the metadata, like
+// OptionalAuthMiddleware: no token means anonymous, a bad token is refused.
+// Methods that need a login check GRPCUserFrom themselves.
+func grpcAuth(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ctx, nil
+	}
+	if !strings.HasPrefix(values[0], "Bearer ") {
+		return nil, status.Error(codes.Unauthenticated, "Token não informado")
+	}
+	userID, userUUID, username, ok := parseJWT(values[0][7:])
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "Token inválido")
+	}
+	return context.WithValue(ctx, grpcUserKey{}, GRPCUser{UserID: userID, UUID: userUUID, Username: username}), nil
+}
+
+func GRPCAuthUnary(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := grpcAuth(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func GRPCAuthStream(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := grpcAuth(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context { return s.ctx }
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Digest frequencies. Nobody gets a digest until they pick one.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+type DigestSettings struct {
+	Frequency  string     `json:"frequency"`
+	LastSentAt *time.Time `json:"last_sent_at,omitempty"`
+}
+
+// DigestRecipient is an opted-in user with a verified e-mail.
+type DigestRecipient struct {
+	UserID    int
+	Username  string
+	Email     string
+	Frequency string
+}
+
+// Digest is the content of one e-mail.
+type Digest struct {
+	Frequency      string
+	Notifications  []Notification
+	More           int // notificações não lidas além das listadas
+	Noticias       []Noticia
+	PortalURL      string
+	UnsubscribeURL string
+}
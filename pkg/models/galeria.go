@@ -13,4 +13,7 @@ type GaleriaItem struct {
 	PublicID   string    `json:"public_id,omitempty"` // ID Cloudinary para deleção
 	Caption    string    `json:"caption"`             // legenda opcional
 	CreatedAt  time.Time `json:"created_at"`
+	Held       bool      `json:"held,omitempty"` // retida pelo filtro, aguardando moderação
 }
+
+func GaleriaCursor(g GaleriaItem) Cursor { return Cursor{CreatedAt: g.CreatedAt, ID: g.ID} }
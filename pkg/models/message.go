@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Who may start a direct conversation with a user (users.dm_policy).
+const (
+	DMPolicyEveryone  = "everyone"
+	DMPolicyFollowers = "followers" // só quem segue o usuário
+)
+
+// Conversation is a one-to-one or small group DM thread as seen by one
+// member: Unread counts the messages from others after their last read.
+type Conversation struct {
+	ID            int                  `json:"id"`
+	IsGroup       bool                 `json:"is_group"`
+	Title         string               `json:"title,omitempty"`
+	Members       []ConversationMember `json:"members"`
+	LastMessage   *Message             `json:"last_message,omitempty"`
+	Unread        int                  `json:"unread"`
+	LastMessageAt time.Time            `json:"last_message_at"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// ConversationMember carries the member's read receipt: every message up to
+// LastReadID was seen.
+type ConversationMember struct {
+	ID          int    `json:"id"`
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+	LastReadID  int    `json:"last_read_id"`
+}
+
+type Message struct {
+	ID             int       `json:"id"`
+	ConversationID int       `json:"conversation_id"`
+	SenderID       int       `json:"sender_id"` // 0 se a conta foi excluída
+	Sender         string    `json:"sender"`
+	Texto          string    `json:"texto"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ReadReceipt is pushed to the other members when someone reads up to
+// MessageID.
+type ReadReceipt struct {
+	ConversationID int `json:"conversation_id"`
+	UserID         int `json:"user_id"`
+	MessageID      int `json:"message_id"`
+}
+
+type CreateConversationRequest struct {
+	Usernames []string `json:"usernames"`
+	Title     string   `json:"title"`
+}
+
+type SendMessageRequest struct {
+	Texto string `json:"texto"`
+}
+
+type DMSettings struct {
+	AllowFrom string `json:"allow_from"` // everyone | followers
+}
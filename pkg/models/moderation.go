@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+// Report targets.
+const (
+	ReportPost    = "post" // posts e respostas
+	ReportGaleria = "galeria"
+	ReportProfile = "profile" // target_id é o id do usuário
+)
+
+// Report statuses.
+const (
+	ReportOpen      = "open"
+	ReportActioned  = "actioned"
+	ReportDismissed = "dismissed"
+)
+
+// ReportAutoFilter is the reason of reports filed by the content filter when
+// it holds content; those have reporter_id 0.
+const ReportAutoFilter = "auto_filter"
+
+// Moderator actions, recorded in the audit trail.
+const (
+	ModHide    = "hide"
+	ModUnhide  = "unhide"
+	ModDelete  = "delete"
+	ModWarn    = "warn"
+	ModSuspend = "suspend"
+	ModDismiss = "dismiss"
+)
+
+// Report is a user's complaint about a post, gallery image or profile.
+// Snapshot keeps the reported text/URL so the decision can still be read
+// after the content is deleted.
+type Report struct {
+	ID             int                `json:"id"`
+	TargetType     string             `json:"target_type"`
+	TargetID       int                `json:"target_id"`
+	ReportedUserID int                `json:"reported_user_id"`
+	ReportedUser   string             `json:"reported_user,omitempty"`
+	ReporterID     int                `json:"reporter_id"`
+	Reporter       string             `json:"reporter,omitempty"`
+	Reason         string             `json:"reason"`
+	Details        string             `json:"details,omitempty"`
+	Snapshot       string             `json:"snapshot,omitempty"`
+	Status         string             `json:"status"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ResolvedAt     *time.Time         `json:"resolved_at,omitempty"`
+	ResolvedBy     *int               `json:"resolved_by,omitempty"`
+	Actions        []ModerationAction `json:"actions,omitempty"`
+}
+
+type CreateReportRequest struct {
+	TargetType string `json:"target_type"`
+	TargetID   int    `json:"target_id"`
+	Reason     string `json:"reason"`
+	Details    string `json:"details"`
+}
+
+// ModerationAction is one entry of the audit trail.
+type ModerationAction struct {
+	ID          int        `json:"id"`
+	ReportID    *int       `json:"report_id,omitempty"`
+	ModeratorID int        `json:"moderator_id"`
+	Moderator   string     `json:"moderator,omitempty"`
+	Action      string     `json:"action"`
+	TargetType  string     `json:"target_type"`
+	TargetID    int        `json:"target_id"`
+	UserID      int        `json:"user_id"` // usuário afetado
+	Note        string     `json:"note,omitempty"`
+	Until       *time.Time `json:"until,omitempty"` // fim da suspensão
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type ModerationActionRequest struct {
+	Action   string `json:"action"`
+	Note     string `json:"note"`
+	Duration string `json:"duration"` // suspensão, ex.: "72h"
+}
+
+func ReportCursor(r Report) Cursor { return Cursor{CreatedAt: r.CreatedAt, ID: r.ID} }
+
+func ModerationActionCursor(a ModerationAction) Cursor {
+	return Cursor{CreatedAt: a.CreatedAt, ID: a.ID}
+}
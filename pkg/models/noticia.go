@@ -22,6 +22,7 @@ type Noticia struct {
 	ImageURL    string        `json:"image_url,omitempty"`
 	Destaque    bool          `json:"destaque"`
 	Tags        []string      `json:"tags,omitempty"`
+	TextoBusca  string        `json:"-"` // texto puro indexado pela busca
 	CreatedAt   time.Time     `json:"created_at"`
 	UpdatedAt   time.Time     `json:"updated_at"`
 }
@@ -3,13 +3,85 @@ package models
 import "time"
 
 type Notification struct {
-	ID          int       `json:"id"`
-	UserID      int       `json:"user_id"`
-	ActorID     *int      `json:"actor_id,omitempty"`
-	ActorName   string    `json:"actor_name,omitempty"`
-	ActorAvatar string    `json:"actor_avatar,omitempty"`
-	Type        string    `json:"type"`
-	PostID      *int      `json:"post_id,omitempty"`
-	IsRead      bool      `json:"is_read"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          int    `json:"id"`
+	UserID      int    `json:"user_id"`
+	ActorID     *int   `json:"actor_id,omitempty"` // o mais recente, quando agrupada
+	ActorName   string `json:"actor_name,omitempty"`
+	ActorAvatar string `json:"actor_avatar,omitempty"`
+	// ActorCount is how many users are behind a grouped notification
+	// ("ana e mais 12 curtiram seu post" = 13).
+	ActorCount int       `json:"actor_count"`
+	Type       string    `json:"type"`
+	Category   string    `json:"category"`
+	PostID     *int      `json:"post_id,omitempty"`
+	IsRead     bool      `json:"is_read"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NotificationCursor(n Notification) Cursor { return Cursor{CreatedAt: n.CreatedAt, ID: n.ID} }
+
+// Notification categories, the unit of the user's preferences.
+const (
+	NotifyLikes    = "likes"
+	NotifyReplies  = "replies"
+	NotifyMentions = "mentions"
+	NotifyReposts  = "reposts"
+	NotifyFollows  = "follows"
+	NotifySystem   = "system" // moderação e avisos do portal
+)
+
+var NotificationCategories = []string{NotifyLikes, NotifyReplies, NotifyMentions, NotifyReposts, NotifyFollows, NotifySystem}
+
+// NotificationCategory maps a notification type to its category; unknown
+// types are system notices.
+func NotificationCategory(notificationType string) string {
+	switch notificationType {
+	case "like", "reaction":
+		return NotifyLikes
+	case "reply":
+		return NotifyReplies
+	case "mention":
+		return NotifyMentions
+	case "repost", "quote":
+		return NotifyReposts
+	case "follow":
+		return NotifyFollows
+	}
+	return NotifySystem
+}
+
+// GroupedNotification tells whether unread notifications of this type about
+// the same post merge into one.
+func GroupedNotification(notificationType string) bool {
+	switch notificationType {
+	case "like", "reaction", "repost", "follow":
+		return true
+	}
+	return false
+}
+
+// NotificationPreference says where a category is delivered: in the portal
+// and/or by e-mail.
+type NotificationPreference struct {
+	Category string `json:"category"`
+	InApp    bool   `json:"in_app"`
+	Email    bool   `json:"email"`
+}
+
+// DefaultNotificationPreferences applies to categories the user never set.
+func DefaultNotificationPreferences() map[string]NotificationPreference {
+	prefs := make(map[string]NotificationPreference, len(NotificationCategories))
+	for _, c := range NotificationCategories {
+		email := c == NotifyReplies || c == NotifyMentions || c == NotifySystem
+		prefs[c] = NotificationPreference{Category: c, InApp: true, Email: email}
+	}
+	return prefs
+}
+
+// NotificationPreferenceUpdate changes one category; omitted channels keep
+// their current value.
+type NotificationPreferenceUpdate struct {
+	Category string `json:"category"`
+	InApp    *bool  `json:"in_app"`
+	Email    *bool  `json:"email"`
 }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Page is the envelope for cursor-paginated lists. NextCursor is empty on the
+// last page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Cursor marks a position in a list ordered by (created_at DESC, id DESC).
+// Clients only ever see it encoded as an opaque token.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int
+}
+
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%d.%d", c.CreatedAt.UnixNano(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a token produced by Cursor.Encode. An empty token means
+// "first page" and yields a nil cursor. The time comes back in UTC, like the
+// TIMESTAMP columns it is compared with, whatever the server's TZ.
+func DecodeCursor(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d.%d", &nanos, &id); err != nil || id <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &Cursor{CreatedAt: time.Unix(0, nanos).UTC(), ID: id}, nil
+}
+
+// NewPage trims a result fetched with limit+1 rows and derives the next
+// cursor from the last item kept.
+func NewPage[T any](items []T, limit int, key func(T) Cursor) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	page := Page[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = key(page.Items[limit-1]).Encode()
+	}
+	return page
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2026, 3, 14, 15, 9, 26, 535897000, time.UTC), ID: 42}
+
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor retornou erro: %v", err)
+	}
+	if !got.CreatedAt.Equal(c.CreatedAt) || got.ID != c.ID {
+		t.Errorf("esperado %+v, obteve %+v", c, *got)
+	}
+}
+
+// TIMESTAMP sem fuso compara o relógio de parede: com TZ=America/Sao_Paulo
+// um cursor em hora local pularia ou repetiria 3 horas de posts.
+func TestDecodeCursorIsUTCWithNonUTCLocal(t *testing.T) {
+	saved := time.Local
+	defer func() { time.Local = saved }()
+	time.Local = time.FixedZone("BRT", -3*60*60)
+
+	c := Cursor{CreatedAt: time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), ID: 7}
+	got, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("DecodeCursor retornou erro: %v", err)
+	}
+	if got.CreatedAt.Location() != time.UTC || got.CreatedAt.Hour() != 15 {
+		t.Errorf("esperado 15h UTC, obteve %v", got.CreatedAt)
+	}
+}
+
+func TestDecodeCursorEmptyAndInvalid(t *testing.T) {
+	if c, err := DecodeCursor(""); c != nil || err != nil {
+		t.Errorf("token vazio deveria ser primeira página (c=%v err=%v)", c, err)
+	}
+	for _, tok := range []string{"%%%", "bm9wZQ", "MTIzLjA"} {
+		if _, err := DecodeCursor(tok); err != ErrInvalidCursor {
+			t.Errorf("token '%s' deveria ser inválido, err=%v", tok, err)
+		}
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	now := time.Now()
+	posts := []Post{{ID: 3, CreatedAt: now}, {ID: 2, CreatedAt: now}, {ID: 1, CreatedAt: now}}
+
+	page := NewPage(posts, 2, PostCursor)
+	if len(page.Items) != 2 || page.NextCursor == "" {
+		t.Fatalf("esperava 2 itens e next_cursor, obteve %d itens e '%s'", len(page.Items), page.NextCursor)
+	}
+	next, _ := DecodeCursor(page.NextCursor)
+	if next.ID != 2 {
+		t.Errorf("next_cursor deveria apontar para o id 2, obteve %d", next.ID)
+	}
+
+	last := NewPage(posts[:1], 2, PostCursor)
+	if last.NextCursor != "" {
+		t.Errorf("última página não deveria ter next_cursor")
+	}
+
+	empty := NewPage[Post](nil, 2, PostCursor)
+	if empty.Items == nil {
+		t.Errorf("items deveria ser [] e não null")
+	}
+}
@@ -7,35 +7,154 @@ import (
 )
 
 type Post struct {
-	ID         int       `json:"id"`
-	Texto      string    `json:"texto"`
-	Author     string    `json:"author"`      // Username / @handle
-	AuthorName string    `json:"author_name"` // Display Name (perfil social)
-	AvatarURL  string    `json:"avatar_url"`
-	UserID     int       `json:"user_id"`
-	ParentID   *int      `json:"parent_id,omitempty"`
-	RepostID   *int      `json:"repost_id,omitempty"`
-	Repost     *Post     `json:"repost,omitempty"`
-	Likes      int       `json:"likes"`
-	Liked      bool      `json:"liked"`
-	ReplyCount int       `json:"reply_count"`
-	CreatedAt  time.Time `json:"created_at"`
-	Replies    []Post    `json:"replies,omitempty"`
+	ID         int    `json:"id"`
+	Texto      string `json:"texto"`
+	Author     string `json:"author"`      // Username / @handle
+	AuthorName string `json:"author_name"` // Display Name (perfil social)
+	AvatarURL  string `json:"avatar_url"`
+	UserID     int    `json:"user_id"`
+	ParentID   *int   `json:"parent_id,omitempty"`
+	RepostID   *int   `json:"repost_id,omitempty"`
+	Repost     *Post  `json:"repost,omitempty"`
+	// RepostUnavailable marks a repost whose original was deleted, hidden or
+	// is from someone the viewer blocked; Repost is nil then.
+	RepostUnavailable bool            `json:"repost_unavailable,omitempty"`
+	RepostCount       int             `json:"repost_count"`
+	Reposted          bool            `json:"reposted"` // viewer has a plain repost of this post
+	Likes             int             `json:"likes"`    // reações 👍
+	Liked             bool            `json:"liked"`
+	Reactions         []ReactionCount `json:"reactions,omitempty"`
+	Bookmarked        bool            `json:"bookmarked"`
+	ReplyCount        int             `json:"reply_count"`
+	CreatedAt         time.Time       `json:"created_at"`
+	EditedAt          *time.Time      `json:"edited_at,omitempty"`
+	BookmarkedAt      *time.Time      `json:"bookmarked_at,omitempty"` // only in GET /social/bookmarks
+	Held              bool            `json:"held,omitempty"`          // retido pelo filtro, aguardando moderação
+	Pinned            bool            `json:"pinned,omitempty"`        // fixado no perfil ou aviso no topo do feed
+	PinnedUntil       *time.Time      `json:"pinned_until,omitempty"`  // só avisos do feed
+	Attachments       []Attachment    `json:"attachments,omitempty"`
+	Poll              *Poll           `json:"poll,omitempty"`
+	Preview           *LinkPreview    `json:"preview,omitempty"`
+	Replies           []Post          `json:"replies,omitempty"`
+	// HasMoreReplies says the post has replies beyond the ones in Replies;
+	// GET /social/feed/:id/replies?cursor=RepliesCursor lists the rest.
+	HasMoreReplies bool   `json:"has_more_replies,omitempty"`
+	RepliesCursor  string `json:"replies_cursor,omitempty"`
+	// ContinueThread marks a post at the thread's depth limit whose replies
+	// weren't loaded; GET /social/feed/:id opens the thread from it.
+	ContinueThread bool `json:"continue_thread,omitempty"`
 }
 
+// ThreadReply is a row of the recursive thread query, before nesting.
+type ThreadReply struct {
+	Post
+	Depth      int
+	HasReplies bool // has replies the viewer can see, loaded or not
+}
+
+// LinkPreview is the OpenGraph/Twitter-card summary of the first link in a
+// post, filled in asynchronously after the post is created.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// Reactions a post accepts, in display order. ReactionLike is the one
+// behind the legacy like endpoints and Post.Likes/Liked.
+var Reactions = []string{"👍", "❤️", "😂", "😮", "😢", "🎉"}
+
+const ReactionLike = "👍"
+
+// ReactionCount is how many users reacted to a post with Emoji; Reacted
+// tells whether the requesting user is one of them.
+type ReactionCount struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted"`
+}
+
+// Reactor is a user who reacted to a post; Since is when.
+type Reactor struct {
+	UserSummary
+	Emoji      string `json:"emoji"`
+	ReactionID int    `json:"-"`
+}
+
+// PostRevision is one version of a post's text, valid from CreatedAt until
+// the next revision. The live text is returned first with Current set.
+type PostRevision struct {
+	Texto     string    `json:"texto"`
+	CreatedAt time.Time `json:"created_at"`
+	Current   bool      `json:"current,omitempty"`
+}
+
+// PostCursor is the keyset position of a post in (created_at, id) order.
+func PostCursor(p Post) Cursor { return Cursor{CreatedAt: p.CreatedAt, ID: p.ID} }
+
+// ToProto is the protobuf form of p, used for Accept: application/x-protobuf
+// and the gRPC service. Timestamps go as unix millis; nil pointers become 0.
 func (p *Post) ToProto() *socialpb.Post {
 	pb := &socialpb.Post{
-		Id:         int32(p.ID),
-		Texto:      p.Texto,
-		Author:     p.Author,
-		UserId:     int32(p.UserID),
-		Likes:      int32(p.Likes),
-		ReplyCount: int32(p.ReplyCount),
-		CreatedAt:  p.CreatedAt.UnixMilli(),
+		Id:                int32(p.ID),
+		Texto:             p.Texto,
+		Author:            p.Author,
+		AuthorName:        p.AuthorName,
+		AvatarUrl:         p.AvatarURL,
+		UserId:            int32(p.UserID),
+		RepostUnavailable: p.RepostUnavailable,
+		RepostCount:       int32(p.RepostCount),
+		Reposted:          p.Reposted,
+		Likes:             int32(p.Likes),
+		Liked:             p.Liked,
+		Bookmarked:        p.Bookmarked,
+		ReplyCount:        int32(p.ReplyCount),
+		CreatedAt:         p.CreatedAt.UnixMilli(),
+		EditedAt:          unixMilli(p.EditedAt),
+		BookmarkedAt:      unixMilli(p.BookmarkedAt),
+		Held:              p.Held,
+		Pinned:            p.Pinned,
+		PinnedUntil:       unixMilli(p.PinnedUntil),
+		HasMoreReplies:    p.HasMoreReplies,
+		RepliesCursor:     p.RepliesCursor,
+		ContinueThread:    p.ContinueThread,
 	}
 	if p.ParentID != nil {
 		pb.ParentId = int32(*p.ParentID)
 	}
+	if p.RepostID != nil {
+		pb.RepostId = int32(*p.RepostID)
+	}
+	if p.Repost != nil {
+		pb.Repost = p.Repost.ToProto()
+	}
+	pb.Reactions = ReactionsToProto(p.Reactions)
+	for _, a := range p.Attachments {
+		pb.Attachments = append(pb.Attachments, &socialpb.Attachment{
+			Id:       int32(a.ID),
+			Kind:     a.Kind,
+			Url:      a.URL,
+			AltText:  a.AltText,
+			FileName: a.FileName,
+			Width:    int32(a.Width),
+			Height:   int32(a.Height),
+			Size:     a.Size,
+		})
+	}
+	if p.Poll != nil {
+		pb.Poll = p.Poll.ToProto()
+	}
+	if p.Preview != nil {
+		pb.Preview = &socialpb.LinkPreview{
+			Url:         p.Preview.URL,
+			Title:       p.Preview.Title,
+			Description: p.Preview.Description,
+			ImageUrl:    p.Preview.ImageURL,
+			SiteName:    p.Preview.SiteName,
+		}
+	}
 	for i := range p.Replies {
 		pb.Replies = append(pb.Replies, p.Replies[i].ToProto())
 	}
@@ -44,18 +163,69 @@ func (p *Post) ToProto() *socialpb.Post {
 
 func PostFromProto(pb *socialpb.Post) Post {
 	p := Post{
-		ID:         int(pb.Id),
-		Texto:      pb.Texto,
-		Author:     pb.Author,
-		UserID:     int(pb.UserId),
-		Likes:      int(pb.Likes),
-		ReplyCount: int(pb.ReplyCount),
-		CreatedAt:  time.UnixMilli(pb.CreatedAt),
+		ID:                int(pb.Id),
+		Texto:             pb.Texto,
+		Author:            pb.Author,
+		AuthorName:        pb.AuthorName,
+		AvatarURL:         pb.AvatarUrl,
+		UserID:            int(pb.UserId),
+		RepostUnavailable: pb.RepostUnavailable,
+		RepostCount:       int(pb.RepostCount),
+		Reposted:          pb.Reposted,
+		Likes:             int(pb.Likes),
+		Liked:             pb.Liked,
+		Bookmarked:        pb.Bookmarked,
+		ReplyCount:        int(pb.ReplyCount),
+		CreatedAt:         time.UnixMilli(pb.CreatedAt).UTC(),
+		EditedAt:          fromUnixMilli(pb.EditedAt),
+		BookmarkedAt:      fromUnixMilli(pb.BookmarkedAt),
+		Held:              pb.Held,
+		Pinned:            pb.Pinned,
+		PinnedUntil:       fromUnixMilli(pb.PinnedUntil),
+		HasMoreReplies:    pb.HasMoreReplies,
+		RepliesCursor:     pb.RepliesCursor,
+		ContinueThread:    pb.ContinueThread,
 	}
 	if pb.ParentId != 0 {
 		pid := int(pb.ParentId)
 		p.ParentID = &pid
 	}
+	if pb.RepostId != 0 {
+		rid := int(pb.RepostId)
+		p.RepostID = &rid
+	}
+	if pb.Repost != nil {
+		original := PostFromProto(pb.Repost)
+		p.Repost = &original
+	}
+	for _, rc := range pb.Reactions {
+		p.Reactions = append(p.Reactions, ReactionCount{Emoji: rc.Emoji, Count: int(rc.Count), Reacted: rc.Reacted})
+	}
+	for _, a := range pb.Attachments {
+		p.Attachments = append(p.Attachments, Attachment{
+			ID:       int(a.Id),
+			Kind:     a.Kind,
+			URL:      a.Url,
+			AltText:  a.AltText,
+			FileName: a.FileName,
+			Width:    int(a.Width),
+			Height:   int(a.Height),
+			Size:     a.Size,
+		})
+	}
+	if pb.Poll != nil {
+		poll := PollFromProto(pb.Poll)
+		p.Poll = &poll
+	}
+	if pb.Preview != nil {
+		p.Preview = &LinkPreview{
+			URL:         pb.Preview.Url,
+			Title:       pb.Preview.Title,
+			Description: pb.Preview.Description,
+			ImageURL:    pb.Preview.ImageUrl,
+			SiteName:    pb.Preview.SiteName,
+		}
+	}
 	for _, r := range pb.Replies {
 		p.Replies = append(p.Replies, PostFromProto(r))
 	}
@@ -65,6 +235,39 @@ func PostFromProto(pb *socialpb.Post) Post {
 	return p
 }
 
+// PostsToProto converts a list of posts, e.g. the items of a Page[Post].
+func PostsToProto(posts []Post) []*socialpb.Post {
+	pbs := make([]*socialpb.Post, len(posts))
+	for i := range posts {
+		pbs[i] = posts[i].ToProto()
+	}
+	return pbs
+}
+
+// ReactionsToProto converts the reaction counts of a post.
+func ReactionsToProto(reactions []ReactionCount) []*socialpb.ReactionCount {
+	var pbs []*socialpb.ReactionCount
+	for _, rc := range reactions {
+		pbs = append(pbs, &socialpb.ReactionCount{Emoji: rc.Emoji, Count: int32(rc.Count), Reacted: rc.Reacted})
+	}
+	return pbs
+}
+
+func unixMilli(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func fromUnixMilli(ms int64) *time.Time {
+	if ms == 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms).UTC()
+	return &t
+}
+
 type Profile struct {
 	Username    string `json:"username"`     // @handle
 	DisplayName string `json:"display_name"` // Nome Perfil
@@ -72,5 +275,149 @@ type Profile struct {
 	AvatarURL   string `json:"avatar_url"`
 	TotalPosts  int    `json:"total_posts"`
 	TotalLikes  int    `json:"total_likes"`
+	Followers   int    `json:"followers"`
+	Following   int    `json:"following"`
+	IsFollowing bool   `json:"is_following"` // requesting user follows this profile
+	PinnedPost  *Post  `json:"pinned_post,omitempty"`
 	Posts       []Post `json:"posts"`
+	NextCursor  string `json:"next_cursor,omitempty"` // next page via GET /social/profile/:username/posts
+}
+
+func (p *Profile) ToProto() *socialpb.Profile {
+	pb := &socialpb.Profile{
+		Username:    p.Username,
+		DisplayName: p.DisplayName,
+		Bio:         p.Bio,
+		AvatarUrl:   p.AvatarURL,
+		TotalPosts:  int32(p.TotalPosts),
+		TotalLikes:  int32(p.TotalLikes),
+		Followers:   int32(p.Followers),
+		Following:   int32(p.Following),
+		IsFollowing: p.IsFollowing,
+		NextCursor:  p.NextCursor,
+		Posts:       PostsToProto(p.Posts),
+	}
+	if p.PinnedPost != nil {
+		pb.PinnedPost = p.PinnedPost.ToProto()
+	}
+	return pb
+}
+
+func ProfileFromProto(pb *socialpb.Profile) Profile {
+	p := Profile{
+		Username:    pb.Username,
+		DisplayName: pb.DisplayName,
+		Bio:         pb.Bio,
+		AvatarURL:   pb.AvatarUrl,
+		TotalPosts:  int(pb.TotalPosts),
+		TotalLikes:  int(pb.TotalLikes),
+		Followers:   int(pb.Followers),
+		Following:   int(pb.Following),
+		IsFollowing: pb.IsFollowing,
+		NextCursor:  pb.NextCursor,
+		Posts:       make([]Post, 0, len(pb.Posts)),
+	}
+	if pb.PinnedPost != nil {
+		pinned := PostFromProto(pb.PinnedPost)
+		p.PinnedPost = &pinned
+	}
+	for _, post := range pb.Posts {
+		p.Posts = append(p.Posts, PostFromProto(post))
+	}
+	return p
+}
+
+// UserSummary is the compact user card used in follower/following lists.
+type UserSummary struct {
+	ID          int       `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url"`
+	Since       time.Time `json:"since"`
+}
+
+// TrendingTag is a hashtag with its time-decayed usage score.
+type TrendingTag struct {
+	Tag   string  `json:"tag"`
+	Score float64 `json:"score"`
+}
+
+// Attachment is a file (image, GIF or PDF) attached to a post or reply.
+type Attachment struct {
+	ID           int    `json:"id"`
+	Kind         string `json:"kind"` // image, gif, pdf
+	URL          string `json:"url"`
+	AltText      string `json:"alt_text"`
+	FileName     string `json:"file_name,omitempty"`
+	Width        int    `json:"width,omitempty"`
+	Height       int    `json:"height,omitempty"`
+	Size         int64  `json:"size"`
+	PublicID     string `json:"-"` // Cloudinary, para deleção
+	ResourceType string `json:"-"` // Cloudinary: image ou raw
+}
+
+// Poll is a poll carried by a post, keyed by the post's ID. Vote counts are
+// zeroed and ResultsVisible is false until the viewer votes or it closes.
+type Poll struct {
+	PostID         int          `json:"post_id"`
+	Options        []PollOption `json:"options"`
+	Multiple       bool         `json:"multiple"`
+	ClosesAt       *time.Time   `json:"closes_at,omitempty"`
+	Closed         bool         `json:"closed"`
+	Voters         int          `json:"voters"`
+	Voted          bool         `json:"voted"`
+	MyChoices      []int        `json:"my_choices,omitempty"`
+	ResultsVisible bool         `json:"results_visible"`
+}
+
+type PollOption struct {
+	ID    int    `json:"id"`
+	Texto string `json:"texto"`
+	Votes int    `json:"votes"`
+}
+
+func (p *Poll) ToProto() *socialpb.Poll {
+	pb := &socialpb.Poll{
+		PostId:         int32(p.PostID),
+		Multiple:       p.Multiple,
+		ClosesAt:       unixMilli(p.ClosesAt),
+		Closed:         p.Closed,
+		Voters:         int32(p.Voters),
+		Voted:          p.Voted,
+		ResultsVisible: p.ResultsVisible,
+	}
+	for _, o := range p.Options {
+		pb.Options = append(pb.Options, &socialpb.PollOption{Id: int32(o.ID), Texto: o.Texto, Votes: int32(o.Votes)})
+	}
+	for _, id := range p.MyChoices {
+		pb.MyChoices = append(pb.MyChoices, int32(id))
+	}
+	return pb
+}
+
+func PollFromProto(pb *socialpb.Poll) Poll {
+	p := Poll{
+		PostID:         int(pb.PostId),
+		Options:        make([]PollOption, 0, len(pb.Options)),
+		Multiple:       pb.Multiple,
+		ClosesAt:       fromUnixMilli(pb.ClosesAt),
+		Closed:         pb.Closed,
+		Voters:         int(pb.Voters),
+		Voted:          pb.Voted,
+		ResultsVisible: pb.ResultsVisible,
+	}
+	for _, o := range pb.Options {
+		p.Options = append(p.Options, PollOption{ID: int(o.Id), Texto: o.Texto, Votes: int(o.Votes)})
+	}
+	for _, id := range pb.MyChoices {
+		p.MyChoices = append(p.MyChoices, int(id))
+	}
+	return p
+}
+
+// PollRequest is the poll part of a new post or reply.
+type PollRequest struct {
+	Options  []string   `json:"options"`
+	Multiple bool       `json:"multiple"`
+	ClosesAt *time.Time `json:"closes_at"`
 }
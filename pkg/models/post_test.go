@@ -0,0 +1,156 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	socialpb "cacc/proto/socialpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// fullPost sets every field of Post. Timestamps travel as unix millis, so
+// they're whole milliseconds here.
+func fullPost() Post {
+	at := func(min int) *time.Time {
+		t := time.Date(2026, 10, 18, 12, min, 30, 123000000, time.UTC)
+		return &t
+	}
+	parent, repostID := 7, 3
+	return Post{
+		ID:                10,
+		Texto:             "olá #cacc",
+		Author:            "ana",
+		AuthorName:        "Ana Souza",
+		AvatarURL:         "https://img.example/ana.png",
+		UserID:            2,
+		ParentID:          &parent,
+		RepostID:          &repostID,
+		Repost:            &Post{ID: 3, Texto: "original", Author: "bia", UserID: 4, CreatedAt: *at(1), Replies: []Post{}},
+		RepostUnavailable: true,
+		RepostCount:       5,
+		Reposted:          true,
+		Likes:             8,
+		Liked:             true,
+		Reactions:         []ReactionCount{{Emoji: "👍", Count: 8, Reacted: true}, {Emoji: "🎉", Count: 1}},
+		Bookmarked:        true,
+		ReplyCount:        1,
+		CreatedAt:         *at(2),
+		EditedAt:          at(3),
+		BookmarkedAt:      at(4),
+		Held:              true,
+		Pinned:            true,
+		PinnedUntil:       at(5),
+		Attachments: []Attachment{
+			{ID: 1, Kind: "image", URL: "https://img.example/1.png", AltText: "gato", Width: 640, Height: 480, Size: 2048},
+			{ID: 2, Kind: "pdf", URL: "https://img.example/2.pdf", FileName: "edital.pdf", Size: 1 << 20},
+		},
+		Poll: &Poll{
+			PostID:         10,
+			Options:        []PollOption{{ID: 1, Texto: "sim", Votes: 3}, {ID: 2, Texto: "não", Votes: 1}},
+			Multiple:       true,
+			ClosesAt:       at(6),
+			Closed:         true,
+			Voters:         4,
+			Voted:          true,
+			MyChoices:      []int{1, 2},
+			ResultsVisible: true,
+		},
+		Preview: &LinkPreview{URL: "https://ufes.br", Title: "UFES", Description: "portal", ImageURL: "https://ufes.br/og.png", SiteName: "UFES"},
+		Replies: []Post{{
+			ID: 11, Texto: "resposta", Author: "bia", UserID: 4, ParentID: &[]int{10}[0], CreatedAt: *at(7),
+			ContinueThread: true, Replies: []Post{},
+		}},
+		HasMoreReplies: true,
+		RepliesCursor:  "cursor",
+		ContinueThread: true,
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	return string(b)
+}
+
+// roundTrip goes through the wire format too, not only the Go structs.
+func roundTrip(t *testing.T, msg proto.Message, dest proto.Message) {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	if err := proto.Unmarshal(data, dest); err != nil {
+		t.Fatalf("proto.Unmarshal: %v", err)
+	}
+}
+
+// A field added to Post or Profile without a proto counterpart would slip
+// through the round trips below unless fullPost/fullProfile set it.
+func TestRoundTripFixturesSetEveryField(t *testing.T) {
+	for _, v := range []interface{}{fullPost(), fullProfile()} {
+		rv := reflect.ValueOf(v)
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Field(i).IsZero() {
+				t.Errorf("%s.%s não está preenchido no teste", rv.Type().Name(), rv.Type().Field(i).Name)
+			}
+		}
+	}
+}
+
+func TestPostProtoRoundTrip(t *testing.T) {
+	for name, p := range map[string]Post{
+		"completo": fullPost(),
+		"mínimo":   {ID: 1, Texto: "oi", Author: "ana", UserID: 2, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+	} {
+		var pb socialpb.Post
+		roundTrip(t, p.ToProto(), &pb)
+		got := PostFromProto(&pb)
+
+		if want, have := mustJSON(t, p), mustJSON(t, got); want != have {
+			t.Errorf("%s: JSON diferente depois do protobuf\nesperado %s\nobteve   %s", name, want, have)
+		}
+	}
+}
+
+func fullProfile() Profile {
+	pinned := fullPost()
+	return Profile{
+		Username:    "ana",
+		DisplayName: "Ana Souza",
+		Bio:         "Computação 2024/1",
+		AvatarURL:   "https://img.example/ana.png",
+		TotalPosts:  12,
+		TotalLikes:  40,
+		Followers:   3,
+		Following:   5,
+		IsFollowing: true,
+		PinnedPost:  &pinned,
+		Posts:       []Post{fullPost(), {ID: 9, Texto: "antigo", Author: "ana", UserID: 2, CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		NextCursor:  "próxima",
+	}
+}
+
+func TestProfileProtoRoundTrip(t *testing.T) {
+	p := fullProfile()
+
+	var pb socialpb.Profile
+	roundTrip(t, p.ToProto(), &pb)
+	got := ProfileFromProto(&pb)
+
+	if want, have := mustJSON(t, p), mustJSON(t, got); want != have {
+		t.Errorf("JSON diferente depois do protobuf\nesperado %s\nobteve   %s", want, have)
+	}
+}
+
+func TestProfileFromProtoEmptyPosts(t *testing.T) {
+	got := ProfileFromProto(&socialpb.Profile{Username: "ana"})
+	if got.Posts == nil {
+		t.Error("posts deveria ser [] e não null no JSON")
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Search result types accepted by GET /search?type=.
+const (
+	SearchPosts    = "posts"
+	SearchUsers    = "users"
+	SearchNoticias = "noticias"
+)
+
+// SearchHit is one ranked result. Snippet is HTML-escaped text with the
+// matched terms wrapped in <mark>; exactly one of Post, User or Noticia is set.
+type SearchHit struct {
+	Type      string       `json:"type"`
+	ID        int          `json:"id"`
+	Title     string       `json:"title,omitempty"`
+	Snippet   string       `json:"snippet"`
+	Rank      float64      `json:"rank"`
+	CreatedAt time.Time    `json:"created_at"`
+	Post      *Post        `json:"post,omitempty"`
+	User      *UserSummary `json:"user,omitempty"`
+	Noticia   *Noticia     `json:"noticia,omitempty"`
+}
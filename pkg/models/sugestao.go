@@ -8,4 +8,8 @@ type Sugestao struct {
 	CreatedAt time.Time `json:"created_at"`
 	Author    string    `json:"author"`
 	Categoria string    `json:"categoria"`
+
+	// Retida pelo filtro de conteúdo, aguardando aprovação de um admin.
+	Pendente       bool   `json:"pendente,omitempty"`
+	MotivoPendente string `json:"motivo_pendente,omitempty"`
 }
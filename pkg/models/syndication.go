@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Syndication formats, served at .../feed.{format}.
+const (
+	FeedAtom = "atom"
+	FeedRSS  = "rss"
+	FeedJSON = "json"
+)
+
+// SyndicationFeed is a rendered feed document plus what HTTP caching needs.
+type SyndicationFeed struct {
+	Body        string    `json:"body"`
+	ContentType string    `json:"content_type"`
+	ETag        string    `json:"etag"`
+	Updated     time.Time `json:"updated"`
+}
@@ -55,4 +55,11 @@ type Session struct {
 	IP           string    `json:"ip"`
 	ExpiresAt    time.Time `json:"expires_at"`
 	CreatedAt    time.Time `json:"created_at"`
+	Location     string    `json:"location,omitempty"` // resolvida no login, se GEOIP_URL estiver configurada
+
+	// Derived from UserAgent when listing sessions – not persisted.
+	Device  string `json:"device"`
+	Browser string `json:"browser"`
+	OS      string `json:"os"`
+	Current bool   `json:"current"`
 }
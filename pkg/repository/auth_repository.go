@@ -16,6 +16,9 @@ type AuthRepository interface {
 	GetUserByUUID(uuid string) (models.User, error)
 	UpdatePassword(userID int, newHashedPassword string) error
 	VerifyEmail(userID int) error
+	// SuspendedUntil returns the end of userID's suspension, or nil when the
+	// user is not suspended.
+	SuspendedUntil(userID int) *time.Time
 
 	// Email Verification tokens
 	CreateEmailVerificationToken(userID int, tokenHash string, expiresAt time.Time) error
@@ -33,13 +36,19 @@ type AuthRepository interface {
 
 	// Sessions
 	CreateSession(userID int, tokenHash, userAgent, ip string, expiresAt time.Time) error
+	SetSessionLocation(tokenHash, location string) error
 	GetSessionByToken(tokenHash string) (models.Session, models.User, error)
 	UpdateSession(sessionID int, newTokenHash string, expiresAt time.Time) error
 	DeleteSessionByID(sessionID int) error
+	DeleteSessionForUser(sessionID, userID int) (bool, error)
 	DeleteSessionByToken(tokenHash string) error
 	DeleteAllSessionsByUserID(userID int) error
 	GetActiveSessionsByUserID(userID int) ([]models.Session, error)
 	EnforceSessionLimit(userID int, maxSessions int) error
+
+	// Known devices (new-login alerts)
+	CountKnownDevices(userID int) (int, error)
+	RememberDevice(userID int, fingerprint string) (isNew bool, err error)
 }
 
 type authRepository struct {
@@ -244,6 +253,17 @@ func (r *authRepository) VerifyEmail(userID int) error {
 	return err
 }
 
+func (r *authRepository) SuspendedUntil(userID int) *time.Time {
+	var until sql.NullTime
+	r.db.QueryRow(`
+		SELECT suspended_until FROM users WHERE id = $1 AND suspended_until > NOW()
+	`, userID).Scan(&until)
+	if !until.Valid {
+		return nil
+	}
+	return &until.Time
+}
+
 // ─── Email Verification Tokens ───────────────────────────────────────────────
 
 func (r *authRepository) CreateEmailVerificationToken(userID int, tokenHash string, expiresAt time.Time) error {
@@ -317,6 +337,11 @@ func (r *authRepository) CreateSession(userID int, tokenHash, userAgent, ip stri
 	return err
 }
 
+func (r *authRepository) SetSessionLocation(tokenHash, location string) error {
+	_, err := r.db.Exec(`UPDATE sessions SET location = $1 WHERE refresh_token = $2`, location, tokenHash)
+	return err
+}
+
 func (r *authRepository) GetSessionByToken(tokenHash string) (models.Session, models.User, error) {
 	var session models.Session
 	var user models.User
@@ -359,6 +384,16 @@ func (r *authRepository) DeleteSessionByID(sessionID int) error {
 	return err
 }
 
+// DeleteSessionForUser removes a session only if it belongs to userID.
+func (r *authRepository) DeleteSessionForUser(sessionID, userID int) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM sessions WHERE id = $1 AND user_id = $2`, sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
 func (r *authRepository) DeleteSessionByToken(tokenHash string) error {
 	_, err := r.db.Exec(`DELETE FROM sessions WHERE refresh_token = $1`, tokenHash)
 	return err
@@ -371,7 +406,7 @@ func (r *authRepository) DeleteAllSessionsByUserID(userID int) error {
 
 func (r *authRepository) GetActiveSessionsByUserID(userID int) ([]models.Session, error) {
 	rows, err := r.db.Query(
-		`SELECT id, user_agent, ip, expires_at, created_at FROM sessions
+		`SELECT id, refresh_token, user_agent, ip, location, expires_at, created_at FROM sessions
 		 WHERE user_id = $1 AND expires_at > NOW() ORDER BY created_at DESC`, userID,
 	)
 	if err != nil {
@@ -382,7 +417,8 @@ func (r *authRepository) GetActiveSessionsByUserID(userID int) ([]models.Session
 	var sessions []models.Session
 	for rows.Next() {
 		var s models.Session
-		if err := rows.Scan(&s.ID, &s.UserAgent, &s.IP, &s.ExpiresAt, &s.CreatedAt); err == nil {
+		if err := rows.Scan(&s.ID, &s.RefreshToken, &s.UserAgent, &s.IP, &s.Location, &s.ExpiresAt, &s.CreatedAt); err == nil {
+			s.UserID = userID
 			sessions = append(sessions, s)
 		}
 	}
@@ -401,3 +437,31 @@ func (r *authRepository) EnforceSessionLimit(userID int, maxSessions int) error
 	)
 	return err
 }
+
+// ─── Known Devices ───────────────────────────────────────────────────────────
+
+func (r *authRepository) CountKnownDevices(userID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM known_devices WHERE user_id = $1`, userID).Scan(&n)
+	return n, err
+}
+
+// RememberDevice records the fingerprint for the user. isNew is true only the
+// first time a given fingerprint is seen; later calls just bump last_seen.
+func (r *authRepository) RememberDevice(userID int, fingerprint string) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(
+		`INSERT INTO known_devices (user_id, fingerprint) VALUES ($1, $2)
+		 ON CONFLICT (user_id, fingerprint) DO NOTHING
+		 RETURNING 1`,
+		userID, fingerprint,
+	).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		_, err = r.db.Exec(
+			`UPDATE known_devices SET last_seen = NOW() WHERE user_id = $1 AND fingerprint = $2`,
+			userID, fingerprint,
+		)
+		return false, err
+	}
+	return err == nil, err
+}
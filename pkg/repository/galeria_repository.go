@@ -7,8 +7,8 @@ import (
 )
 
 type GaleriaRepository interface {
-	List(limit, offset int) ([]models.GaleriaItem, error)
-	Create(userID int, author, authorName, avatarURL, imageURL, publicID, caption string) (models.GaleriaItem, error)
+	List(limit int, cursor *models.Cursor) ([]models.GaleriaItem, error)
+	Create(userID int, author, authorName, avatarURL, imageURL, publicID, caption string, hidden bool) (models.GaleriaItem, error)
 	Delete(id, userID int) error
 	GetByID(id int) (models.GaleriaItem, error)
 }
@@ -21,21 +21,17 @@ func NewGaleriaRepository(db *sql.DB) GaleriaRepository {
 	return &galeriaRepository{db: db}
 }
 
-func (r *galeriaRepository) List(limit, offset int) ([]models.GaleriaItem, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 30
-	}
-	if offset < 0 {
-		offset = 0
-	}
-
+func (r *galeriaRepository) List(limit int, cursor *models.Cursor) ([]models.GaleriaItem, error) {
+	before, beforeID := keysetBounds(cursor)
 	rows, err := r.db.Query(`
 		SELECT g.id, g.user_id, g.author, g.author_name, g.avatar_url,
 		       g.image_url, g.public_id, g.caption, g.created_at
 		FROM galeria g
-		ORDER BY g.created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset)
+		WHERE g.hidden_at IS NULL
+		  AND (g.created_at, g.id) < ($2, $3)
+		ORDER BY g.created_at DESC, g.id DESC
+		LIMIT $1
+	`, limit, before, beforeID)
 	if err != nil {
 		return nil, err
 	}
@@ -61,14 +57,14 @@ func (r *galeriaRepository) List(limit, offset int) ([]models.GaleriaItem, error
 	return items, rows.Err()
 }
 
-func (r *galeriaRepository) Create(userID int, author, authorName, avatarURL, imageURL, publicID, caption string) (models.GaleriaItem, error) {
+func (r *galeriaRepository) Create(userID int, author, authorName, avatarURL, imageURL, publicID, caption string, hidden bool) (models.GaleriaItem, error) {
 	var item models.GaleriaItem
 	var pub, cap sql.NullString
 	err := r.db.QueryRow(`
-		INSERT INTO galeria (user_id, author, author_name, avatar_url, image_url, public_id, caption, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO galeria (user_id, author, author_name, avatar_url, image_url, public_id, caption, created_at, hidden_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $9 THEN NOW() END)
 		RETURNING id, user_id, author, author_name, avatar_url, image_url, public_id, caption, created_at
-	`, userID, author, authorName, avatarURL, imageURL, publicID, caption, time.Now()).
+	`, userID, author, authorName, avatarURL, imageURL, publicID, caption, time.Now(), hidden).
 		Scan(&item.ID, &item.UserID, &item.Author, &item.AuthorName, &item.AvatarURL,
 			&item.ImageURL, &pub, &cap, &item.CreatedAt)
 	item.PublicID = pub.String
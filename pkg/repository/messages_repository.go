@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+type MessagesRepository interface {
+	DMPolicy(userID int) string
+	SetDMPolicy(userID int, policy string) error
+
+	// CreateConversation adds creatorID and memberIDs to a new conversation.
+	// For one-to-one conversations (directKey != "") an existing one is
+	// returned instead, with created false.
+	CreateConversation(creatorID int, memberIDs []int, isGroup bool, title, directKey string) (id int, created bool, err error)
+	Conversation(id, userID int) (models.Conversation, error)
+	Conversations(userID, limit int, cursor *models.Cursor) ([]models.Conversation, error)
+	// MemberIDs lists the members of a conversation; empty when it doesn't
+	// exist.
+	MemberIDs(conversationID int) ([]int, error)
+	Leave(conversationID, userID int) (bool, error)
+
+	CreateMessage(conversationID, senderID int, texto string) (models.Message, error)
+	Messages(conversationID, limit int, cursor *models.Cursor) ([]models.Message, error)
+	// MarkRead moves userID's read marker up to messageID (0 = latest
+	// message) and returns the new marker; changed is false when it didn't
+	// move.
+	MarkRead(conversationID, userID, messageID int) (lastRead int, changed bool, err error)
+	UnreadTotal(userID int) int
+}
+
+type messagesRepository struct {
+	db *sql.DB
+}
+
+func NewMessagesRepository(db *sql.DB) MessagesRepository {
+	return &messagesRepository{db: db}
+}
+
+func (r *messagesRepository) DMPolicy(userID int) string {
+	policy := models.DMPolicyEveryone
+	r.db.QueryRow(`SELECT dm_policy FROM users WHERE id = $1`, userID).Scan(&policy)
+	return policy
+}
+
+func (r *messagesRepository) SetDMPolicy(userID int, policy string) error {
+	_, err := r.db.Exec(`UPDATE users SET dm_policy = $2 WHERE id = $1`, userID, policy)
+	return err
+}
+
+func (r *messagesRepository) CreateConversation(creatorID int, memberIDs []int, isGroup bool, title, directKey string) (int, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, false, err
+	}
+	defer tx.Rollback()
+
+	var key sql.NullString
+	if directKey != "" {
+		key = sql.NullString{String: directKey, Valid: true}
+	}
+
+	var id int
+	err = tx.QueryRow(`
+		INSERT INTO conversations (is_group, title, direct_key, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (direct_key) DO NOTHING
+		RETURNING id
+	`, isGroup, title, key, creatorID).Scan(&id)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`SELECT id FROM conversations WHERE direct_key = $1`, directKey).Scan(&id)
+		return id, false, err
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	for _, uid := range append([]int{creatorID}, memberIDs...) {
+		if _, err := tx.Exec(`
+			INSERT INTO conversation_members (conversation_id, user_id) VALUES ($1, $2)
+			ON CONFLICT DO NOTHING
+		`, id, uid); err != nil {
+			return 0, false, err
+		}
+	}
+	return id, true, tx.Commit()
+}
+
+const conversationColumns = `
+	c.id, c.is_group, c.title, c.created_at, c.last_message_at,
+	(SELECT COUNT(*) FROM direct_messages dm
+	 WHERE dm.conversation_id = c.id AND dm.id > cm.last_read_message_id
+	   AND dm.sender_id IS DISTINCT FROM cm.user_id) AS unread,
+	lm.id, COALESCE(lm.sender_id, 0), COALESCE(lu.username, ''), lm.texto, lm.created_at`
+
+const conversationJoins = `
+	FROM conversation_members cm
+	JOIN conversations c ON c.id = cm.conversation_id
+	LEFT JOIN LATERAL (
+		SELECT * FROM direct_messages WHERE conversation_id = c.id ORDER BY id DESC LIMIT 1
+	) lm ON TRUE
+	LEFT JOIN users lu ON lu.id = lm.sender_id`
+
+func scanConversation(row interface{ Scan(...interface{}) error }) (models.Conversation, error) {
+	var c models.Conversation
+	var lastID sql.NullInt64
+	var lastSender int
+	var lastSenderName string
+	var lastTexto sql.NullString
+	var lastAt sql.NullTime
+	err := row.Scan(&c.ID, &c.IsGroup, &c.Title, &c.CreatedAt, &c.LastMessageAt, &c.Unread,
+		&lastID, &lastSender, &lastSenderName, &lastTexto, &lastAt)
+	if err == nil && lastID.Valid {
+		c.LastMessage = &models.Message{
+			ID:             int(lastID.Int64),
+			ConversationID: c.ID,
+			SenderID:       lastSender,
+			Sender:         lastSenderName,
+			Texto:          lastTexto.String,
+			CreatedAt:      lastAt.Time,
+		}
+	}
+	return c, err
+}
+
+// Conversation loads one conversation as seen by userID, who must be a
+// member (sql.ErrNoRows otherwise).
+func (r *messagesRepository) Conversation(id, userID int) (models.Conversation, error) {
+	c, err := scanConversation(r.db.QueryRow(`
+		SELECT `+conversationColumns+conversationJoins+`
+		WHERE cm.conversation_id = $1 AND cm.user_id = $2
+	`, id, userID))
+	if err != nil {
+		return c, err
+	}
+	members, err := r.members([]int{c.ID})
+	c.Members = members[c.ID]
+	return c, err
+}
+
+// Conversations is userID's inbox, most recent activity first.
+func (r *messagesRepository) Conversations(userID, limit int, cursor *models.Cursor) ([]models.Conversation, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT `+conversationColumns+conversationJoins+`
+		WHERE cm.user_id = $1
+		  AND (c.last_message_at, c.id) < ($3, $4)
+		ORDER BY c.last_message_at DESC, c.id DESC
+		LIMIT $2
+	`, userID, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Conversation
+	var ids []int
+	for rows.Next() {
+		c, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, c)
+		ids = append(ids, c.ID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	members, err := r.members(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range list {
+		list[i].Members = members[list[i].ID]
+	}
+	return list, nil
+}
+
+func (r *messagesRepository) members(conversationIDs []int) (map[int][]models.ConversationMember, error) {
+	out := map[int][]models.ConversationMember{}
+	if len(conversationIDs) == 0 {
+		return out, nil
+	}
+
+	placeholders := make([]string, len(conversationIDs))
+	args := make([]interface{}, len(conversationIDs))
+	for i, id := range conversationIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(`
+		SELECT cm.conversation_id, u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), cm.last_read_message_id
+		FROM conversation_members cm
+		JOIN users u ON u.id = cm.user_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE cm.conversation_id IN (`+strings.Join(placeholders, ",")+`)
+		ORDER BY cm.joined_at, u.id
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var convID int
+		var m models.ConversationMember
+		if err := rows.Scan(&convID, &m.ID, &m.Username, &m.DisplayName, &m.AvatarURL, &m.LastReadID); err != nil {
+			return nil, err
+		}
+		out[convID] = append(out[convID], m)
+	}
+	return out, rows.Err()
+}
+
+func (r *messagesRepository) MemberIDs(conversationID int) ([]int, error) {
+	rows, err := r.db.Query(`SELECT user_id FROM conversation_members WHERE conversation_id = $1`, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Leave removes userID from a group; the conversation goes away with its
+// last member.
+func (r *messagesRepository) Leave(conversationID, userID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		DELETE FROM conversation_members WHERE conversation_id = $1 AND user_id = $2
+		RETURNING 1
+	`, conversationID, userID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = r.db.Exec(`
+		DELETE FROM conversations c
+		WHERE c.id = $1 AND NOT EXISTS (SELECT 1 FROM conversation_members WHERE conversation_id = c.id)
+	`, conversationID)
+	return true, err
+}
+
+// CreateMessage also bumps the conversation in the inbox and marks the
+// message as read by its sender.
+func (r *messagesRepository) CreateMessage(conversationID, senderID int, texto string) (models.Message, error) {
+	m := models.Message{ConversationID: conversationID, SenderID: senderID, Texto: texto}
+	err := r.db.QueryRow(`
+		WITH msg AS (
+			INSERT INTO direct_messages (conversation_id, sender_id, texto)
+			VALUES ($1, $2, $3)
+			RETURNING id, created_at
+		), bump AS (
+			UPDATE conversations SET last_message_at = (SELECT created_at FROM msg) WHERE id = $1
+		), seen AS (
+			UPDATE conversation_members SET last_read_message_id = (SELECT id FROM msg)
+			WHERE conversation_id = $1 AND user_id = $2
+		)
+		SELECT msg.id, msg.created_at, COALESCE(u.username, '')
+		FROM msg LEFT JOIN users u ON u.id = $2
+	`, conversationID, senderID, texto).Scan(&m.ID, &m.CreatedAt, &m.Sender)
+	return m, err
+}
+
+// Messages is a conversation's history, newest first.
+func (r *messagesRepository) Messages(conversationID, limit int, cursor *models.Cursor) ([]models.Message, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT dm.id, dm.conversation_id, COALESCE(dm.sender_id, 0), COALESCE(u.username, ''), dm.texto, dm.created_at
+		FROM direct_messages dm
+		LEFT JOIN users u ON u.id = dm.sender_id
+		WHERE dm.conversation_id = $1
+		  AND (dm.created_at, dm.id) < ($3, $4)
+		ORDER BY dm.created_at DESC, dm.id DESC
+		LIMIT $2
+	`, conversationID, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []models.Message
+	for rows.Next() {
+		var m models.Message
+		if err := rows.Scan(&m.ID, &m.ConversationID, &m.SenderID, &m.Sender, &m.Texto, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
+
+func (r *messagesRepository) MarkRead(conversationID, userID, messageID int) (int, bool, error) {
+	var lastRead int
+	err := r.db.QueryRow(`
+		UPDATE conversation_members cm
+		SET last_read_message_id = target.id
+		FROM (
+			SELECT COALESCE(MAX(id), 0) AS id FROM direct_messages
+			WHERE conversation_id = $1 AND ($3 = 0 OR id <= $3)
+		) target
+		WHERE cm.conversation_id = $1 AND cm.user_id = $2
+		  AND target.id > cm.last_read_message_id
+		RETURNING cm.last_read_message_id
+	`, conversationID, userID, messageID).Scan(&lastRead)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return lastRead, err == nil, err
+}
+
+// UnreadTotal counts unread messages across all of userID's conversations.
+func (r *messagesRepository) UnreadTotal(userID int) int {
+	var n int
+	r.db.QueryRow(`
+		SELECT COUNT(*)
+		FROM conversation_members cm
+		JOIN direct_messages dm ON dm.conversation_id = cm.conversation_id
+		WHERE cm.user_id = $1 AND dm.id > cm.last_read_message_id
+		  AND dm.sender_id IS DISTINCT FROM cm.user_id
+	`, userID).Scan(&n)
+	return n
+}
@@ -0,0 +1,333 @@
+package repository
+
+import (
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type ModerationRepository interface {
+	// ReportTarget finds who owns the reported content and a text snapshot of it.
+	ReportTarget(targetType string, targetID int) (ownerID int, snapshot string, err error)
+	CreateReport(r models.Report) (models.Report, bool, error)
+	Reports(status string, limit int, cursor *models.Cursor) ([]models.Report, error)
+	GetReport(id int) (models.Report, error)
+	ActionReports(targetType string, targetID, moderatorID int) (reporterIDs []int, err error)
+	DismissReport(id, moderatorID int) error
+
+	SetHidden(targetType string, targetID int, hidden bool) error
+	// Hold hides content flagged by the content filter and files an
+	// automatic report (no reporter) with the filter's reason.
+	Hold(targetType string, targetID, ownerID int, details string) error
+	DeleteTarget(targetType string, targetID int) error
+	Suspend(userID int, until time.Time) error
+
+	LogAction(a models.ModerationAction) (models.ModerationAction, error)
+	Actions(reportID int) ([]models.ModerationAction, error)
+	AuditLog(limit int, cursor *models.Cursor) ([]models.ModerationAction, error)
+}
+
+type moderationRepository struct {
+	db *sql.DB
+}
+
+func NewModerationRepository(db *sql.DB) ModerationRepository {
+	return &moderationRepository{db: db}
+}
+
+// contentTables maps the hideable/deletable targets to their table.
+var contentTables = map[string]string{
+	models.ReportPost:    "posts",
+	models.ReportGaleria: "galeria",
+}
+
+func (r *moderationRepository) ReportTarget(targetType string, targetID int) (int, string, error) {
+	var query string
+	switch targetType {
+	case models.ReportPost:
+		query = `SELECT COALESCE(user_id, 0), texto FROM posts WHERE id = $1`
+	case models.ReportGaleria:
+		query = `SELECT user_id, TRIM(image_url || ' ' || COALESCE(caption, '')) FROM galeria WHERE id = $1`
+	case models.ReportProfile:
+		query = `
+			SELECT u.id, TRIM(u.username || ' ' || COALESCE(sp.display_name, '') || ' ' || COALESCE(sp.bio, ''))
+			FROM users u LEFT JOIN social_profiles sp ON sp.user_id = u.id
+			WHERE u.id = $1`
+	default:
+		return 0, "", fmt.Errorf("alvo de denúncia desconhecido: %s", targetType)
+	}
+	var ownerID int
+	var snapshot string
+	err := r.db.QueryRow(query, targetID).Scan(&ownerID, &snapshot)
+	return ownerID, snapshot, err
+}
+
+// CreateReport returns false when the reporter already has an open report on
+// the same target (uq_reports_open).
+func (r *moderationRepository) CreateReport(rep models.Report) (models.Report, bool, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO reports (target_type, target_id, reported_user_id, reporter_id, reason, details, snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (reporter_id, target_type, target_id) WHERE status = 'open' DO NOTHING
+		RETURNING id, status, created_at
+	`, rep.TargetType, rep.TargetID, rep.ReportedUserID, rep.ReporterID, rep.Reason, rep.Details, rep.Snapshot).
+		Scan(&rep.ID, &rep.Status, &rep.CreatedAt)
+	if err == sql.ErrNoRows {
+		return rep, false, nil
+	}
+	return rep, err == nil, err
+}
+
+const reportColumns = `
+	r.id, r.target_type, r.target_id, r.reported_user_id, COALESCE(ru.username, ''),
+	COALESCE(r.reporter_id, 0), COALESCE(rp.username, ''), r.reason, r.details, r.snapshot,
+	r.status, r.created_at, r.resolved_at, r.resolved_by`
+
+const reportJoins = `
+	FROM reports r
+	LEFT JOIN users ru ON ru.id = r.reported_user_id
+	LEFT JOIN users rp ON rp.id = r.reporter_id`
+
+func scanReport(row interface{ Scan(...interface{}) error }) (models.Report, error) {
+	var rep models.Report
+	var resolvedAt sql.NullTime
+	var resolvedBy sql.NullInt64
+	err := row.Scan(
+		&rep.ID, &rep.TargetType, &rep.TargetID, &rep.ReportedUserID, &rep.ReportedUser,
+		&rep.ReporterID, &rep.Reporter, &rep.Reason, &rep.Details, &rep.Snapshot,
+		&rep.Status, &rep.CreatedAt, &resolvedAt, &resolvedBy,
+	)
+	if resolvedAt.Valid {
+		rep.ResolvedAt = &resolvedAt.Time
+	}
+	if resolvedBy.Valid {
+		by := int(resolvedBy.Int64)
+		rep.ResolvedBy = &by
+	}
+	return rep, err
+}
+
+// Reports is the moderation queue for one status, newest first.
+func (r *moderationRepository) Reports(status string, limit int, cursor *models.Cursor) ([]models.Report, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT `+reportColumns+reportJoins+`
+		WHERE r.status = $1
+		  AND (r.created_at, r.id) < ($3, $4)
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $2
+	`, status, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []models.Report
+	for rows.Next() {
+		if rep, err := scanReport(rows); err == nil {
+			reports = append(reports, rep)
+		}
+	}
+	return reports, nil
+}
+
+func (r *moderationRepository) GetReport(id int) (models.Report, error) {
+	return scanReport(r.db.QueryRow(`SELECT `+reportColumns+reportJoins+` WHERE r.id = $1`, id))
+}
+
+// ActionReports closes every open report on a target once a moderator acted
+// on it, returning the reporters to notify.
+func (r *moderationRepository) ActionReports(targetType string, targetID, moderatorID int) ([]int, error) {
+	rows, err := r.db.Query(`
+		UPDATE reports SET status = 'actioned', resolved_at = NOW(), resolved_by = $3
+		WHERE target_type = $1 AND target_id = $2 AND status = 'open'
+		RETURNING COALESCE(reporter_id, 0)
+	`, targetType, targetID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reporters []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err == nil && id != 0 {
+			reporters = append(reporters, id)
+		}
+	}
+	return reporters, nil
+}
+
+func (r *moderationRepository) DismissReport(id, moderatorID int) error {
+	_, err := r.db.Exec(`
+		UPDATE reports SET status = 'dismissed', resolved_at = NOW(), resolved_by = $2
+		WHERE id = $1 AND status = 'open'
+	`, id, moderatorID)
+	return err
+}
+
+func (r *moderationRepository) SetHidden(targetType string, targetID int, hidden bool) error {
+	table, ok := contentTables[targetType]
+	if !ok {
+		return fmt.Errorf("alvo não pode ser ocultado: %s", targetType)
+	}
+	if targetType == models.ReportPost {
+		return r.setPostHidden(targetID, hidden)
+	}
+	res, err := r.db.Exec(`
+		UPDATE `+table+` SET hidden_at = CASE WHEN $2 THEN NOW() ELSE NULL END WHERE id = $1
+	`, targetID, hidden)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// setPostHidden hides or shows a post. The parent's public reply_count only
+// counts visible replies, so it moves when a reply actually changes state;
+// replies held by the filter were inserted hidden and never counted.
+func (r *moderationRepository) setPostHidden(postID int, hidden bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var parentID sql.NullInt64
+	var wasHidden bool
+	err = tx.QueryRow(`
+		SELECT parent_id, hidden_at IS NOT NULL FROM posts WHERE id = $1 FOR UPDATE
+	`, postID).Scan(&parentID, &wasHidden)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		UPDATE posts SET hidden_at = CASE WHEN $2 THEN NOW() ELSE NULL END WHERE id = $1
+	`, postID, hidden); err != nil {
+		return err
+	}
+	if parentID.Valid && wasHidden != hidden {
+		delta := 1
+		if hidden {
+			delta = -1
+		}
+		if _, err := tx.Exec(`
+			UPDATE posts SET reply_count = GREATEST(reply_count + $2, 0) WHERE id = $1
+		`, parentID.Int64, delta); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *moderationRepository) Hold(targetType string, targetID, ownerID int, details string) error {
+	if err := r.SetHidden(targetType, targetID, true); err != nil {
+		return err
+	}
+	_, snapshot, err := r.ReportTarget(targetType, targetID)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(`
+		INSERT INTO reports (target_type, target_id, reported_user_id, reporter_id, reason, details, snapshot)
+		VALUES ($1, $2, $3, NULL, $4, $5, $6)
+	`, targetType, targetID, ownerID, models.ReportAutoFilter, details, snapshot)
+	return err
+}
+
+// DeleteTarget removes content regardless of its author (the author-only
+// deletes live in the social and galeria repositories). A post goes in one
+// transaction with its plain reposts, like SocialRepository.DeletePost.
+func (r *moderationRepository) DeleteTarget(targetType string, targetID int) error {
+	table, ok := contentTables[targetType]
+	if !ok {
+		return fmt.Errorf("alvo não pode ser apagado: %s", targetType)
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM `+table+` WHERE id = $1`, targetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if targetType == models.ReportPost {
+		if _, err := tx.Exec(`DELETE FROM posts WHERE repost_id = $1 AND texto = '' AND parent_id IS NULL`, targetID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *moderationRepository) Suspend(userID int, until time.Time) error {
+	_, err := r.db.Exec(`UPDATE users SET suspended_until = $2 WHERE id = $1`, userID, until)
+	return err
+}
+
+func (r *moderationRepository) LogAction(a models.ModerationAction) (models.ModerationAction, error) {
+	err := r.db.QueryRow(`
+		INSERT INTO moderation_actions (report_id, moderator_id, action, target_type, target_id, user_id, note, until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at
+	`, a.ReportID, a.ModeratorID, a.Action, a.TargetType, a.TargetID, a.UserID, a.Note, a.Until).Scan(&a.ID, &a.CreatedAt)
+	return a, err
+}
+
+const actionColumns = `
+	SELECT a.id, a.report_id, a.moderator_id, COALESCE(u.username, ''), a.action,
+	       a.target_type, a.target_id, a.user_id, a.note, a.until, a.created_at
+	FROM moderation_actions a
+	LEFT JOIN users u ON u.id = a.moderator_id`
+
+func (r *moderationRepository) Actions(reportID int) ([]models.ModerationAction, error) {
+	return r.queryActions(actionColumns+`
+		WHERE a.report_id = $1
+		ORDER BY a.created_at, a.id
+	`, reportID)
+}
+
+func (r *moderationRepository) AuditLog(limit int, cursor *models.Cursor) ([]models.ModerationAction, error) {
+	before, beforeID := keysetBounds(cursor)
+	return r.queryActions(actionColumns+`
+		WHERE (a.created_at, a.id) < ($2, $3)
+		ORDER BY a.created_at DESC, a.id DESC
+		LIMIT $1
+	`, limit, before, beforeID)
+}
+
+func (r *moderationRepository) queryActions(query string, args ...interface{}) ([]models.ModerationAction, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	actions := []models.ModerationAction{}
+	for rows.Next() {
+		var a models.ModerationAction
+		var reportID sql.NullInt64
+		var until sql.NullTime
+		if err := rows.Scan(&a.ID, &reportID, &a.ModeratorID, &a.Moderator, &a.Action,
+			&a.TargetType, &a.TargetID, &a.UserID, &a.Note, &until, &a.CreatedAt); err != nil {
+			continue
+		}
+		if reportID.Valid {
+			id := int(reportID.Int64)
+			a.ReportID = &id
+		}
+		if until.Valid {
+			a.Until = &until.Time
+		}
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
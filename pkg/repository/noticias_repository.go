@@ -13,8 +13,12 @@ type NoticiasRepository interface {
 	BuscarPorID(id int) (models.Noticia, error)
 	Destaques() ([]models.Noticia, error)
 	Criar(n models.Noticia) (models.Noticia, error)
-	Atualizar(id int, req models.AtualizarNoticiaRequest, conteudoStr string) (models.Noticia, error)
+	Atualizar(id int, req models.AtualizarNoticiaRequest, conteudoStr, textoBusca string) (models.Noticia, error)
 	Deletar(id int) (bool, error)
+
+	Buscar(query string, limit, offset int) ([]models.SearchHit, error)
+	SemTextoBusca(afterID, limit int) ([]models.Noticia, error)
+	AtualizarTextoBusca(id int, textoBusca string) error
 }
 
 type noticiasRepository struct {
@@ -99,9 +103,9 @@ func (r *noticiasRepository) Criar(n models.Noticia) (models.Noticia, error) {
 	tagsJSON, _ := json.Marshal(n.Tags)
 
 	_, err := r.db.Exec(`
-		INSERT INTO noticias (titulo, conteudo, resumo, author, categoria, image_url, destaque, tags)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
-	`, n.Titulo, n.Conteudo, n.Resumo, n.Author, n.Categoria, n.ImageURL, n.Destaque, string(tagsJSON))
+		INSERT INTO noticias (titulo, conteudo, resumo, author, categoria, image_url, destaque, tags, texto_busca)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, n.Titulo, n.Conteudo, n.Resumo, n.Author, n.Categoria, n.ImageURL, n.Destaque, string(tagsJSON), n.TextoBusca)
 	if err != nil {
 		return n, err
 	}
@@ -109,7 +113,7 @@ func (r *noticiasRepository) Criar(n models.Noticia) (models.Noticia, error) {
 	return n, nil
 }
 
-func (r *noticiasRepository) Atualizar(id int, req models.AtualizarNoticiaRequest, conteudoStr string) (models.Noticia, error) {
+func (r *noticiasRepository) Atualizar(id int, req models.AtualizarNoticiaRequest, conteudoStr, textoBusca string) (models.Noticia, error) {
 	sets := []string{}
 	args := []interface{}{}
 
@@ -118,8 +122,8 @@ func (r *noticiasRepository) Atualizar(id int, req models.AtualizarNoticiaReques
 		args = append(args, *req.Titulo)
 	}
 	if req.Conteudo != nil && conteudoStr != "" {
-		sets = append(sets, "conteudo = ?")
-		args = append(args, conteudoStr)
+		sets = append(sets, "conteudo = ?", "texto_busca = ?")
+		args = append(args, conteudoStr, textoBusca)
 	}
 	if req.Resumo != nil {
 		sets = append(sets, "resumo = ?")
@@ -173,6 +177,67 @@ func (r *noticiasRepository) Deletar(id int) (bool, error) {
 	return rowsAff > 0, nil
 }
 
+// Buscar usa os índices FULLTEXT de migrations/003_search_noticias.mariadb.sql.
+// Acertos no título contam em dobro; Snippet volta com o texto puro para destaque.
+func (r *noticiasRepository) Buscar(query string, limit, offset int) ([]models.SearchHit, error) {
+	rows, err := r.db.Query(`
+		SELECT id, titulo, resumo, author, categoria, COALESCE(image_url,''), destaque, created_at, updated_at,
+		       texto_busca,
+		       MATCH(titulo, resumo, texto_busca) AGAINST (? IN NATURAL LANGUAGE MODE)
+		         + 2 * MATCH(titulo) AGAINST (? IN NATURAL LANGUAGE MODE) AS relevancia
+		FROM noticias
+		WHERE MATCH(titulo, resumo, texto_busca) AGAINST (? IN NATURAL LANGUAGE MODE)
+		ORDER BY relevancia DESC, created_at DESC
+		LIMIT ? OFFSET ?
+	`, query, query, query, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var n models.Noticia
+		var texto string
+		var relevancia float64
+		if err := rows.Scan(
+			&n.ID, &n.Titulo, &n.Resumo, &n.Author, &n.Categoria, &n.ImageURL, &n.Destaque, &n.CreatedAt, &n.UpdatedAt,
+			&texto, &relevancia,
+		); err != nil {
+			continue
+		}
+		if texto == "" {
+			texto = n.Resumo
+		}
+		hits = append(hits, models.SearchHit{
+			Type: models.SearchNoticias, ID: n.ID, Title: n.Titulo, Snippet: texto, Rank: relevancia, CreatedAt: n.CreatedAt, Noticia: &n,
+		})
+	}
+	return hits, nil
+}
+
+// SemTextoBusca lista notícias ainda fora do índice de busca (anteriores à
+// coluna texto_busca), em lotes por id crescente a partir de afterID.
+func (r *noticiasRepository) SemTextoBusca(afterID, limit int) ([]models.Noticia, error) {
+	rows, err := r.db.Query(`
+		SELECT id, titulo, conteudo, resumo, author, categoria, COALESCE(image_url,''), destaque,
+		       COALESCE(tags, '[]'), created_at, updated_at
+		FROM noticias WHERE texto_busca = '' AND id > ?
+		ORDER BY id LIMIT ?
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanNoticias(rows)
+}
+
+func (r *noticiasRepository) AtualizarTextoBusca(id int, textoBusca string) error {
+	_, err := r.db.Exec(`UPDATE noticias SET texto_busca = ? WHERE id = ?`, textoBusca, id)
+	return err
+}
+
 func scanNoticias(rows *sql.Rows) ([]models.Noticia, error) {
 	var noticias []models.Noticia
 	for rows.Next() {
@@ -3,12 +3,35 @@ package repository
 import (
 	"cacc/pkg/models"
 	"database/sql"
+	"time"
 )
 
 type NotificationRepository interface {
+	// CreateNotification respects blocks, mutes and the user's in-app
+	// preference, and folds grouped types into the open (unread) group.
 	CreateNotification(userID int, actorID *int, notificationType string, postID *int) error
-	GetNotifications(userID, limit, offset int) ([]models.Notification, error)
+	GetNotifications(userID, limit int, cursor *models.Cursor) ([]models.Notification, error)
+	UnreadCount(userID int) (int, error)
 	MarkAsRead(userID int) error
+	MarkOneAsRead(userID, id int) (found bool, err error)
+	// MarkReadUpTo marks id and every notification listed after it (older)
+	// as read.
+	MarkReadUpTo(userID, id int) (found bool, err error)
+
+	Preferences(userID int) ([]models.NotificationPreference, error)
+	SetPreferences(userID int, prefs []models.NotificationPreference) error
+
+	DigestSettings(userID int) (models.DigestSettings, error)
+	SetDigestFrequency(userID int, frequency string) error
+	// DueDigests lists recipients of frequency that haven't had period yet,
+	// by user id after afterID.
+	DueDigests(frequency, period string, afterID, limit int) ([]models.DigestRecipient, error)
+	// ClaimDigest marks period as sent for userID and returns when the
+	// previous digest went out. Only one caller per period gets claimed.
+	ClaimDigest(userID int, period string) (lastSent *time.Time, claimed bool, err error)
+	// ReleaseDigest undoes a claim whose e-mail failed, so the next run retries.
+	ReleaseDigest(userID int, lastSent *time.Time) error
+	UnreadSince(userID int, since time.Time, limit int) ([]models.Notification, error)
 }
 
 type notificationRepository struct {
@@ -24,40 +47,309 @@ func (r *notificationRepository) CreateNotification(userID int, actorID *int, no
 		return nil
 	}
 
-	_, err := r.db.Exec(`
+	// Nothing reaches a user from someone they muted, or across a block in
+	// either direction, nor in a category they turned off.
+	var allowed bool
+	err := r.db.QueryRow(`
+		SELECT ($2::int IS NULL OR NOT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+			UNION ALL
+			SELECT 1 FROM user_mutes WHERE muter_id = $1 AND muted_id = $2
+		)) AND NOT EXISTS (
+			SELECT 1 FROM notification_preferences WHERE user_id = $1 AND category = $3 AND NOT in_app
+		)
+	`, userID, actorID, models.NotificationCategory(notificationType)).Scan(&allowed)
+	if err != nil || !allowed {
+		return err
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if actorID != nil && models.GroupedNotification(notificationType) {
+		var groupID int
+		err := tx.QueryRow(`
+			SELECT id FROM notifications
+			WHERE user_id = $1 AND type = $2 AND post_id IS NOT DISTINCT FROM $3 AND is_read = false
+			ORDER BY id DESC LIMIT 1
+			FOR UPDATE
+		`, userID, notificationType, postID).Scan(&groupID)
+		if err == nil {
+			res, err := tx.Exec(`
+				INSERT INTO notification_actors (notification_id, actor_id) VALUES ($1, $2)
+				ON CONFLICT DO NOTHING
+			`, groupID, *actorID)
+			if err != nil {
+				return err
+			}
+			// Someone already in the group (unlike + like again) doesn't
+			// count twice nor bump it.
+			if n, _ := res.RowsAffected(); n > 0 {
+				if _, err := tx.Exec(`
+					UPDATE notifications
+					SET actor_id = $2, actor_count = actor_count + 1, created_at = NOW()
+					WHERE id = $1
+				`, groupID, *actorID); err != nil {
+					return err
+				}
+			}
+			return tx.Commit()
+		}
+		if err != sql.ErrNoRows {
+			return err
+		}
+	}
+
+	var id int
+	if err := tx.QueryRow(`
 		INSERT INTO notifications (user_id, actor_id, type, post_id, is_read)
 		VALUES ($1, $2, $3, $4, false)
-	`, userID, actorID, notificationType, postID)
-	return err
+		RETURNING id
+	`, userID, actorID, notificationType, postID).Scan(&id); err != nil {
+		return err
+	}
+	if actorID != nil {
+		if _, err := tx.Exec(`
+			INSERT INTO notification_actors (notification_id, actor_id) VALUES ($1, $2)
+		`, id, *actorID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
-func (r *notificationRepository) GetNotifications(userID, limit, offset int) ([]models.Notification, error) {
+// visibleNotifications drops notifications from actors the user blocked or
+// muted after they were created.
+const visibleNotifications = `
+	NOT EXISTS (SELECT 1 FROM user_blocks ub WHERE ub.blocker_id = $1 AND ub.blocked_id = n.actor_id)
+	AND NOT EXISTS (SELECT 1 FROM user_mutes um WHERE um.muter_id = $1 AND um.muted_id = n.actor_id)`
+
+func (r *notificationRepository) GetNotifications(userID, limit int, cursor *models.Cursor) ([]models.Notification, error) {
+	before, beforeID := keysetBounds(cursor)
 	rows, err := r.db.Query(`
-		SELECT n.id, n.user_id, n.actor_id, n.type, n.post_id, n.is_read, n.created_at,
+		SELECT n.id, n.user_id, n.actor_id, n.actor_count, n.type, n.post_id, n.is_read, n.created_at,
 		       COALESCE(u.username, ''), COALESCE(sp.avatar_url, '')
 		FROM notifications n
 		LEFT JOIN users u ON n.actor_id = u.id
 		LEFT JOIN social_profiles sp ON n.actor_id = sp.user_id
 		WHERE n.user_id = $1
-		ORDER BY n.created_at DESC
-		LIMIT $2 OFFSET $3
-	`, userID, limit, offset)
+		  AND (n.created_at, n.id) < ($3, $4)
+		  AND `+visibleNotifications+`
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $2
+	`, userID, limit, before, beforeID)
 	if err != nil {
 		return nil, err
 	}
+	return scanNotifications(rows)
+}
+
+func scanNotifications(rows *sql.Rows) ([]models.Notification, error) {
 	defer rows.Close()
 
 	var notifs []models.Notification
 	for rows.Next() {
 		var n models.Notification
-		if err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Type, &n.PostID, &n.IsRead, &n.CreatedAt, &n.ActorName, &n.ActorAvatar); err == nil {
+		if err := rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.ActorCount, &n.Type, &n.PostID, &n.IsRead, &n.CreatedAt, &n.ActorName, &n.ActorAvatar); err == nil {
+			n.Category = models.NotificationCategory(n.Type)
 			notifs = append(notifs, n)
 		}
 	}
 	return notifs, nil
 }
 
+func (r *notificationRepository) UnreadCount(userID int) (int, error) {
+	var n int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM notifications n
+		WHERE n.user_id = $1 AND n.is_read = false
+		  AND `+visibleNotifications+`
+	`, userID).Scan(&n)
+	return n, err
+}
+
 func (r *notificationRepository) MarkAsRead(userID int) error {
 	_, err := r.db.Exec(`UPDATE notifications SET is_read = true WHERE user_id = $1 AND is_read = false`, userID)
 	return err
 }
+
+func (r *notificationRepository) MarkOneAsRead(userID, id int) (bool, error) {
+	res, err := r.db.Exec(`UPDATE notifications SET is_read = true WHERE id = $2 AND user_id = $1`, userID, id)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (r *notificationRepository) MarkReadUpTo(userID, id int) (bool, error) {
+	var pos models.Cursor
+	err := r.db.QueryRow(`SELECT created_at, id FROM notifications WHERE id = $2 AND user_id = $1`, userID, id).
+		Scan(&pos.CreatedAt, &pos.ID)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = r.db.Exec(`
+		UPDATE notifications SET is_read = true
+		WHERE user_id = $1 AND is_read = false AND (created_at, id) <= ($2, $3)
+	`, userID, pos.CreatedAt, pos.ID)
+	return err == nil, err
+}
+
+// Preferences returns every category, filling the ones the user never set
+// with the defaults.
+func (r *notificationRepository) Preferences(userID int) ([]models.NotificationPreference, error) {
+	prefs := models.DefaultNotificationPreferences()
+	rows, err := r.db.Query(`SELECT category, in_app, email FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.NotificationPreference
+		if err := rows.Scan(&p.Category, &p.InApp, &p.Email); err != nil {
+			return nil, err
+		}
+		if _, known := prefs[p.Category]; known {
+			prefs[p.Category] = p
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	list := make([]models.NotificationPreference, 0, len(prefs))
+	for _, c := range models.NotificationCategories {
+		list = append(list, prefs[c])
+	}
+	return list, nil
+}
+
+func (r *notificationRepository) SetPreferences(userID int, prefs []models.NotificationPreference) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, p := range prefs {
+		if _, err := tx.Exec(`
+			INSERT INTO notification_preferences (user_id, category, in_app, email)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (user_id, category) DO UPDATE SET in_app = EXCLUDED.in_app, email = EXCLUDED.email
+		`, userID, p.Category, p.InApp, p.Email); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *notificationRepository) DigestSettings(userID int) (models.DigestSettings, error) {
+	settings := models.DigestSettings{Frequency: models.DigestOff}
+	var lastSent sql.NullTime
+	err := r.db.QueryRow(`SELECT frequency, last_sent_at FROM notification_digests WHERE user_id = $1`, userID).
+		Scan(&settings.Frequency, &lastSent)
+	if err == sql.ErrNoRows {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if lastSent.Valid {
+		settings.LastSentAt = &lastSent.Time
+	}
+	return settings, nil
+}
+
+func (r *notificationRepository) SetDigestFrequency(userID int, frequency string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO notification_digests (user_id, frequency) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET frequency = EXCLUDED.frequency, updated_at = NOW()
+	`, userID, frequency)
+	return err
+}
+
+func (r *notificationRepository) DueDigests(frequency, period string, afterID, limit int) ([]models.DigestRecipient, error) {
+	rows, err := r.db.Query(`
+		SELECT u.id, u.username, u.email, d.frequency
+		FROM notification_digests d
+		JOIN users u ON u.id = d.user_id
+		WHERE d.frequency = $1 AND d.last_period IS DISTINCT FROM $2
+		  AND d.user_id > $3
+		  AND u.is_verified AND COALESCE(u.email, '') <> ''
+		  AND (u.suspended_until IS NULL OR u.suspended_until <= NOW())
+		ORDER BY d.user_id
+		LIMIT $4
+	`, frequency, period, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.DigestRecipient
+	for rows.Next() {
+		var d models.DigestRecipient
+		if err := rows.Scan(&d.UserID, &d.Username, &d.Email, &d.Frequency); err != nil {
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+func (r *notificationRepository) ClaimDigest(userID int, period string) (*time.Time, bool, error) {
+	// A replica that loses the race waits on the row lock and then sees the
+	// period already set, so neither the CTE nor the UPDATE match.
+	var lastSent sql.NullTime
+	err := r.db.QueryRow(`
+		WITH prev AS (
+			SELECT last_sent_at FROM notification_digests
+			WHERE user_id = $1 AND last_period IS DISTINCT FROM $2
+			FOR UPDATE
+		)
+		UPDATE notification_digests SET last_period = $2, last_sent_at = NOW()
+		WHERE user_id = $1 AND last_period IS DISTINCT FROM $2
+		RETURNING (SELECT last_sent_at FROM prev)
+	`, userID, period).Scan(&lastSent)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if lastSent.Valid {
+		return &lastSent.Time, true, nil
+	}
+	return nil, true, nil
+}
+
+func (r *notificationRepository) ReleaseDigest(userID int, lastSent *time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE notification_digests SET last_period = NULL, last_sent_at = $2 WHERE user_id = $1
+	`, userID, lastSent)
+	return err
+}
+
+func (r *notificationRepository) UnreadSince(userID int, since time.Time, limit int) ([]models.Notification, error) {
+	rows, err := r.db.Query(`
+		SELECT n.id, n.user_id, n.actor_id, n.actor_count, n.type, n.post_id, n.is_read, n.created_at,
+		       COALESCE(u.username, ''), COALESCE(sp.avatar_url, '')
+		FROM notifications n
+		LEFT JOIN users u ON n.actor_id = u.id
+		LEFT JOIN social_profiles sp ON n.actor_id = sp.user_id
+		WHERE n.user_id = $1 AND n.is_read = false AND n.created_at > $2
+		  AND `+visibleNotifications+`
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT $3
+	`, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanNotifications(rows)
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"math"
+	"time"
+
+	"cacc/pkg/models"
+)
+
+// keysetBounds turns an optional cursor into the (created_at, id) pair used in
+// `WHERE (created_at, id) < ($x, $y)`. A nil cursor (first page) yields bounds
+// past every row so the same query serves all pages.
+func keysetBounds(c *models.Cursor) (time.Time, int) {
+	if c == nil {
+		return time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC), math.MaxInt32
+	}
+	return c.CreatedAt, c.ID
+}
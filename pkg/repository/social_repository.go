@@ -5,27 +5,93 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type SocialRepository interface {
-	Feed(userID, limit, offset int) ([]models.Post, error)
+	Feed(userID, limit int, cursor *models.Cursor, followingOnly bool) ([]models.Post, error)
 	Thread(postID, userID int) (models.Post, error)
-	Replies(parentID, userID, limit int) ([]models.Post, error)
-	ProfilePosts(profileUserID, requestingUserID, limit int) ([]models.Post, error)
+	// Replies pages through the direct replies of parentID, oldest first,
+	// after cursor.
+	Replies(parentID, userID, limit int, cursor *models.Cursor) ([]models.Post, error)
+	// ThreadReplies loads the reply tree under rootID in one recursive query,
+	// breadth first: up to maxDepth levels, perNode+1 replies per post (the
+	// extra one tells there are more) and maxRows rows in total.
+	ThreadReplies(rootID, userID, maxDepth, perNode, maxRows int) ([]models.ThreadReply, error)
+	// AncestorIDs lists the posts above postID, nearest first.
+	AncestorIDs(postID int) ([]int, error)
+	ProfilePosts(profileUserID, requestingUserID, limit int, cursor *models.Cursor) ([]models.Post, error)
 	ProfileStats(userID int) (totalPosts, totalLikes int)
 	ProfileInfo(userID int) (username, displayName, bio, avatar string, err error)
 	UpdateProfile(userID int, displayName, bio, avatarURL string) error
-	CreatePost(texto, author string, userID int) (models.Post, error)
-	CreateReply(texto, author string, userID, parentID int) (models.Post, error)
-	CreateRepost(userID, repostID int) (models.Post, error)
+	// CreatePost, CreateReply and CreateRepost insert the post already
+	// hidden when the content filter holds it, so it is never visible.
+	CreatePost(texto, author string, userID int, hidden bool) (models.Post, error)
+	CreateReply(texto, author string, userID, parentID int, hidden bool) (models.Post, error)
+	// CreateRepost returns false when texto is empty and userID already has a
+	// plain repost of repostID (uq_posts_plain_repost).
+	CreateRepost(userID, repostID int, texto string, hidden bool) (models.Post, bool, error)
+	UndoRepost(userID, repostID int) (deletedID int, err error)
+	RepostCount(postID int) int
+	// PostsByID loads the posts userID can see among ids (reposted originals).
+	PostsByID(ids []int, userID int) (map[int]models.Post, error)
 	IncrementReplyCount(parentID int) error
-	LikePost(userID, postID int) (success bool, err error)
-	UnlikePost(userID, postID int) (success bool, err error)
+	AddReaction(userID, postID int, emoji string) (success bool, err error)
+	RemoveReaction(userID, postID int, emoji string) (success bool, err error)
+	// Reactions aggregates the reactions of each post, in the order of
+	// emojis, flagging the ones userID used.
+	Reactions(postIDs []int, userID int, emojis []string) (map[int][]models.ReactionCount, error)
+	// Reactors lists who reacted to a post (only with emoji, when set),
+	// newest first, leaving out users blocked with viewerID.
+	Reactors(postID, viewerID int, emoji string, limit int, cursor *models.Cursor) ([]models.Reactor, error)
 	IncLikeCount(postID int) (int, error)
 	DecLikeCount(postID int) (int, error)
 	GetLikeCount(postID int) (int, error)
 	DeletePost(postID, userID int) (int, error)
+	EditPost(postID, userID int, texto string) (time.Time, error)
+
+	AddAttachments(postID int, attachments []models.Attachment) ([]models.Attachment, error)
+	Attachments(postIDs []int) (map[int][]models.Attachment, error)
+	ThreadAttachments(postID int) ([]models.Attachment, error)
+	CreatePoll(postID int, poll models.PollRequest) error
+	Polls(postIDs []int, userID int) (map[int]*models.Poll, error)
+	VotePoll(postID, userID int, optionIDs []int) (bool, error)
+	PostRevisions(postID int) ([]models.PostRevision, error)
 	BatchLoadReplies(parentIDs []int, userID int) (map[int][]models.Post, error)
+
+	Bookmark(userID, postID int) (success bool, err error)
+	Unbookmark(userID, postID int) (success bool, err error)
+	Bookmarks(userID, limit int, cursor *models.Cursor) ([]models.Post, error)
+
+	// Follow graph
+	Follow(followerID, followeeID int) (success bool, err error)
+	Unfollow(followerID, followeeID int) (success bool, err error)
+	IsFollowing(followerID, followeeID int) bool
+	FollowCounts(userID int) (followers, following int)
+	Followers(userID, limit, offset int) ([]models.UserSummary, error)
+	Following(userID, limit, offset int) ([]models.UserSummary, error)
+
+	// Blocks & mutes
+	Block(blockerID, blockedID int) (success bool, err error)
+	Unblock(blockerID, blockedID int) (success bool, err error)
+	Mute(muterID, mutedID int) (success bool, err error)
+	Unmute(muterID, mutedID int) (success bool, err error)
+	IsBlocked(userA, userB int) bool
+	Blocks(userID, limit, offset int) ([]models.UserSummary, error)
+	Mutes(userID, limit, offset int) ([]models.UserSummary, error)
+
+	// Pins
+	SetPinnedPost(userID int, postID *int) error
+	PinnedPostID(userID int) int
+	PinAnnouncement(postID, adminID int, until time.Time) error
+	UnpinAnnouncement(postID int) (success bool, err error)
+	Announcements(userID int) ([]models.Post, error)
+
+	SetTags(postID int, tags []string) error
+	TagPosts(tag string, userID, limit int, cursor *models.Cursor) ([]models.Post, error)
+
+	SearchPosts(query string, userID, limit, offset int) ([]models.SearchHit, error)
+	SearchUsers(query string, limit, offset int) ([]models.SearchHit, error)
 }
 
 type socialRepository struct {
@@ -36,17 +102,27 @@ func NewSocialRepository(db *sql.DB) SocialRepository {
 	return &socialRepository{db: db}
 }
 
-func (r *socialRepository) Feed(userID, limit, offset int) ([]models.Post, error) {
+// Feed returns root posts older than cursor. With followingOnly it is
+// restricted to the authors userID follows plus userID's own posts.
+func (r *socialRepository) Feed(userID, limit int, cursor *models.Cursor, followingOnly bool) ([]models.Post, error) {
+	before, beforeID := keysetBounds(cursor)
 	rows, err := r.db.Query(`
-		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.repost_id, p.likes, p.reply_count, p.created_at,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $3) AS liked
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $3 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $3) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $3 AND rq.texto = '') AS reposted
 		FROM posts p
 		LEFT JOIN users u ON p.user_id = u.id
 		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
 		WHERE p.parent_id IS NULL
-		ORDER BY p.created_at DESC
-		LIMIT $1 OFFSET $2
-	`, limit, offset, userID)
+		  AND `+visibleTo("$3")+`
+		  AND `+notMuted("$3")+`
+		  AND (p.created_at, p.id) < ($2, $5)
+		  AND (NOT $4 OR p.user_id = $3 OR p.user_id IN (SELECT f.followee_id FROM follows f WHERE f.follower_id = $3))
+		ORDER BY p.created_at DESC, p.id DESC
+		LIMIT $1
+	`, limit, before, userID, followingOnly, beforeID)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +131,7 @@ func (r *socialRepository) Feed(userID, limit, offset int) ([]models.Post, error
 	var posts []models.Post
 	for rows.Next() {
 		var p models.Post
-		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.Liked); err == nil {
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted); err == nil {
 			p.Replies = []models.Post{}
 			posts = append(posts, p)
 		}
@@ -66,29 +142,54 @@ func (r *socialRepository) Feed(userID, limit, offset int) ([]models.Post, error
 func (r *socialRepository) Thread(postID, userID int) (models.Post, error) {
 	var p models.Post
 	err := r.db.QueryRow(`
-		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $2) AS liked
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $2 AND rq.texto = '') AS reposted
 		FROM posts p
 		LEFT JOIN users u ON p.user_id = u.id
 		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
 		WHERE p.id = $1
+		  AND (p.hidden_at IS NULL OR p.user_id = $2)
 	`, postID, userID).Scan(
-		&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.Liked,
+		&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted,
 	)
 	return p, err
 }
 
-func (r *socialRepository) Replies(parentID, userID, limit int) ([]models.Post, error) {
+// hasVisibleReplies is a column telling whether the post aliased t has
+// replies the viewer can see. The inner p shadows any outer one so
+// visibleTo applies to the reply.
+func hasVisibleReplies(viewer string) string {
+	return `EXISTS (SELECT 1 FROM posts p WHERE p.parent_id = t.id AND ` + visibleTo(viewer) + `)`
+}
+
+func (r *socialRepository) Replies(parentID, userID, limit int, cursor *models.Cursor) ([]models.Post, error) {
+	// Replies read oldest first, so the cursor is a lower bound here.
+	after, afterID := time.Unix(0, 0), 0
+	if cursor != nil {
+		after, afterID = cursor.CreatedAt, cursor.ID
+	}
 	rows, err := r.db.Query(`
-		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.repost_id, p.likes, p.reply_count, p.created_at,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $2) AS liked
-		FROM posts p
-		LEFT JOIN users u ON p.user_id = u.id
-		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
-		WHERE p.parent_id = $1
-		ORDER BY p.created_at ASC
-		LIMIT $3
-	`, parentID, userID, limit)
+		SELECT t.id, t.texto, COALESCE(u.username, t.author), COALESCE(sp.display_name, u.username, t.author), COALESCE(sp.avatar_url, ''), COALESCE(t.user_id, 0), t.repost_id, t.likes, t.reply_count, t.created_at, t.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = t.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = t.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = t.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = t.id AND rq.user_id = $2 AND rq.texto = '') AS reposted,
+		       `+hasVisibleReplies("$2")+` AS has_replies
+		FROM (
+			SELECT p.* FROM posts p
+			WHERE p.parent_id = $1
+			  AND `+visibleTo("$2")+`
+			  AND (p.created_at, p.id) > ($4, $5)
+			ORDER BY p.created_at ASC, p.id ASC
+			LIMIT $3
+		) t
+		LEFT JOIN users u ON t.user_id = u.id
+		LEFT JOIN social_profiles sp ON t.user_id = sp.user_id
+		ORDER BY t.created_at ASC, t.id ASC
+	`, parentID, userID, limit, after, afterID)
 	if err != nil {
 		return nil, err
 	}
@@ -97,24 +198,116 @@ func (r *socialRepository) Replies(parentID, userID, limit int) ([]models.Post,
 	var posts []models.Post
 	for rows.Next() {
 		var p models.Post
-		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.Liked); err == nil {
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted, &p.HasMoreReplies); err == nil {
+			pid := parentID
+			p.ParentID = &pid
+			p.Replies = []models.Post{}
 			posts = append(posts, p)
 		}
 	}
-	return posts, nil
+	return posts, rows.Err()
+}
+
+func (r *socialRepository) ThreadReplies(rootID, userID, maxDepth, perNode, maxRows int) ([]models.ThreadReply, error) {
+	// Each level takes perNode+1 replies per post through a LATERAL LIMIT;
+	// the extra one is returned but not descended into. The LIMIT on tree
+	// stops the recursion itself once maxRows rows are out.
+	rows, err := r.db.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT c.id, c.parent_id, 1 AS depth, c.rn
+			FROM (
+				SELECT p.id, p.parent_id, ROW_NUMBER() OVER (ORDER BY p.created_at, p.id) AS rn
+				FROM posts p
+				WHERE p.parent_id = $1 AND `+visibleTo("$2")+`
+				ORDER BY p.created_at, p.id
+				LIMIT $4 + 1
+			) c
+			UNION ALL
+			SELECT c.id, c.parent_id, tr.depth + 1, c.rn
+			FROM tree tr
+			CROSS JOIN LATERAL (
+				SELECT p.id, p.parent_id, ROW_NUMBER() OVER (ORDER BY p.created_at, p.id) AS rn
+				FROM posts p
+				WHERE p.parent_id = tr.id AND `+visibleTo("$2")+`
+				ORDER BY p.created_at, p.id
+				LIMIT $4 + 1
+			) c
+			WHERE tr.depth < $3 AND tr.rn <= $4
+		)
+		SELECT tr.depth, t.id, t.texto, COALESCE(u.username, t.author), COALESCE(sp.display_name, u.username, t.author), COALESCE(sp.avatar_url, ''), COALESCE(t.user_id, 0), t.parent_id, t.repost_id, t.likes, t.reply_count, t.created_at, t.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = t.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = t.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = t.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = t.id AND rq.user_id = $2 AND rq.texto = '') AS reposted,
+		       `+hasVisibleReplies("$2")+` AS has_replies
+		FROM (SELECT * FROM tree LIMIT $5) tr
+		JOIN posts t ON t.id = tr.id
+		LEFT JOIN users u ON t.user_id = u.id
+		LEFT JOIN social_profiles sp ON t.user_id = sp.user_id
+		ORDER BY tr.depth, t.created_at, t.id
+	`, rootID, userID, maxDepth, perNode, maxRows)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.ThreadReply
+	for rows.Next() {
+		var tr models.ThreadReply
+		p := &tr.Post
+		var parentID int
+		if err := rows.Scan(&tr.Depth, &p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &parentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted, &tr.HasReplies); err != nil {
+			return nil, err
+		}
+		p.ParentID = &parentID
+		list = append(list, tr)
+	}
+	return list, rows.Err()
 }
 
-func (r *socialRepository) ProfilePosts(profileUserID, requestingUserID, limit int) ([]models.Post, error) {
+func (r *socialRepository) AncestorIDs(postID int) ([]int, error) {
 	rows, err := r.db.Query(`
-		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $2) AS liked
+		WITH RECURSIVE up AS (
+			SELECT parent_id AS id, 1 AS depth FROM posts WHERE id = $1 AND parent_id IS NOT NULL
+			UNION ALL
+			SELECT p.parent_id, up.depth + 1 FROM posts p JOIN up ON p.id = up.id
+			WHERE p.parent_id IS NOT NULL
+		)
+		SELECT id FROM up ORDER BY depth
+	`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *socialRepository) ProfilePosts(profileUserID, requestingUserID, limit int, cursor *models.Cursor) ([]models.Post, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $2 AND rq.texto = '') AS reposted
 		FROM posts p
 		LEFT JOIN users u ON p.user_id = u.id
 		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
 		WHERE p.user_id = $1
-		ORDER BY p.created_at DESC
+		  AND `+visibleTo("$2")+`
+		  AND (p.created_at, p.id) < ($4, $5)
+		ORDER BY p.created_at DESC, p.id DESC
 		LIMIT $3
-	`, profileUserID, requestingUserID, limit)
+	`, profileUserID, requestingUserID, limit, before, beforeID)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +316,7 @@ func (r *socialRepository) ProfilePosts(profileUserID, requestingUserID, limit i
 	var posts []models.Post
 	for rows.Next() {
 		var p models.Post
-		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.Liked); err == nil {
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted); err == nil {
 			p.Replies = []models.Post{}
 			posts = append(posts, p)
 		}
@@ -164,37 +357,98 @@ func (r *socialRepository) UpdateProfile(userID int, displayName, bio, avatarURL
 	return err
 }
 
-func (r *socialRepository) CreatePost(texto, author string, userID int) (models.Post, error) {
+func (r *socialRepository) CreatePost(texto, author string, userID int, hidden bool) (models.Post, error) {
 	var p models.Post
 	err := r.db.QueryRow(`
-		INSERT INTO posts (texto, author, user_id, parent_id, likes, reply_count)
-		VALUES ($1, $2, $3, NULL, 0, 0)
+		INSERT INTO posts (texto, author, user_id, parent_id, likes, reply_count, hidden_at)
+		VALUES ($1, $2, $3, NULL, 0, 0, CASE WHEN $4 THEN NOW() END)
 		RETURNING id, created_at
-	`, texto, author, userID).Scan(&p.ID, &p.CreatedAt)
+	`, texto, author, userID, hidden).Scan(&p.ID, &p.CreatedAt)
 	return p, err
 }
 
-func (r *socialRepository) CreateReply(texto, author string, userID, parentID int) (models.Post, error) {
+func (r *socialRepository) CreateReply(texto, author string, userID, parentID int, hidden bool) (models.Post, error) {
 	var p models.Post
 	err := r.db.QueryRow(`
 		WITH new_reply AS (
-			INSERT INTO posts (texto, author, user_id, parent_id, likes, reply_count)
-			VALUES ($1, $2, $3, $4, 0, 0)
+			INSERT INTO posts (texto, author, user_id, parent_id, likes, reply_count, hidden_at)
+			VALUES ($1, $2, $3, $4, 0, 0, CASE WHEN $5 THEN NOW() END)
 			RETURNING id, created_at
 		)
 		SELECT nr.id, nr.created_at FROM new_reply nr
-	`, texto, author, userID, parentID).Scan(&p.ID, &p.CreatedAt)
+	`, texto, author, userID, parentID, hidden).Scan(&p.ID, &p.CreatedAt)
 	return p, err
 }
 
-func (r *socialRepository) CreateRepost(userID, repostID int) (models.Post, error) {
+func (r *socialRepository) CreateRepost(userID, repostID int, texto string, hidden bool) (models.Post, bool, error) {
 	var p models.Post
 	err := r.db.QueryRow(`
-		INSERT INTO posts (texto, author, user_id, repost_id, likes, reply_count)
-		VALUES ('', (SELECT username FROM users WHERE id = $1), $1, $2, 0, 0)
+		INSERT INTO posts (texto, author, user_id, repost_id, likes, reply_count, hidden_at)
+		VALUES ($3, (SELECT username FROM users WHERE id = $1), $1, $2, 0, 0, CASE WHEN $4 THEN NOW() END)
+		ON CONFLICT (user_id, repost_id) WHERE texto = '' AND parent_id IS NULL DO NOTHING
 		RETURNING id, created_at
-	`, userID, repostID).Scan(&p.ID, &p.CreatedAt)
-	return p, err
+	`, userID, repostID, texto, hidden).Scan(&p.ID, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return p, false, nil
+	}
+	return p, err == nil, err
+}
+
+// UndoRepost deletes userID's plain repost of repostID. Quotes are removed
+// like any other post, through DeletePost.
+func (r *socialRepository) UndoRepost(userID, repostID int) (int, error) {
+	var deletedID int
+	err := r.db.QueryRow(`
+		DELETE FROM posts
+		WHERE user_id = $1 AND repost_id = $2 AND texto = '' AND parent_id IS NULL
+		RETURNING id
+	`, userID, repostID).Scan(&deletedID)
+	return deletedID, err
+}
+
+func (r *socialRepository) RepostCount(postID int) int {
+	var n int
+	r.db.QueryRow(`SELECT COUNT(*) FROM posts WHERE repost_id = $1`, postID).Scan(&n)
+	return n
+}
+
+func (r *socialRepository) PostsByID(ids []int, userID int) (map[int]models.Post, error) {
+	posts := map[int]models.Post{}
+	if len(ids) == 0 {
+		return posts, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	args := []interface{}{userID}
+	for i, id := range ids {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $1 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $1) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $1 AND rq.texto = '') AS reposted
+		FROM posts p
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
+		WHERE p.id IN (`+strings.Join(placeholders, ",")+`)
+		  AND `+visibleTo("$1")+`
+	`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p models.Post
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted); err == nil {
+			posts[p.ID] = p
+		}
+	}
+	return posts, nil
 }
 
 func (r *socialRepository) IncrementReplyCount(parentID int) error {
@@ -202,31 +456,114 @@ func (r *socialRepository) IncrementReplyCount(parentID int) error {
 	return err
 }
 
-func (r *socialRepository) LikePost(userID, postID int) (bool, error) {
+func (r *socialRepository) AddReaction(userID, postID int, emoji string) (bool, error) {
 	var dummy int
 	err := r.db.QueryRow(`
-		INSERT INTO post_likes (user_id, post_id) VALUES ($1, $2)
-		ON CONFLICT (user_id, post_id) DO NOTHING
+		INSERT INTO post_reactions (user_id, post_id, emoji) VALUES ($1, $2, $3)
+		ON CONFLICT (post_id, user_id, emoji) DO NOTHING
 		RETURNING 1
-	`, userID, postID).Scan(&dummy)
+	`, userID, postID, emoji).Scan(&dummy)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
 	return err == nil, err
 }
 
-func (r *socialRepository) UnlikePost(userID, postID int) (bool, error) {
+func (r *socialRepository) RemoveReaction(userID, postID int, emoji string) (bool, error) {
 	var dummy int
 	err := r.db.QueryRow(`
-		DELETE FROM post_likes WHERE user_id = $1 AND post_id = $2
+		DELETE FROM post_reactions WHERE user_id = $1 AND post_id = $2 AND emoji = $3
 		RETURNING 1
-	`, userID, postID).Scan(&dummy)
+	`, userID, postID, emoji).Scan(&dummy)
 	if err == sql.ErrNoRows {
 		return false, nil
 	}
 	return err == nil, err
 }
 
+func (r *socialRepository) Reactions(postIDs []int, userID int, emojis []string) (map[int][]models.ReactionCount, error) {
+	result := make(map[int][]models.ReactionCount)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := []interface{}{userID}
+	for i, id := range postIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
+	}
+
+	rows, err := r.db.Query(`
+		SELECT post_id, emoji, COUNT(*), BOOL_OR(user_id = $1)
+		FROM post_reactions
+		WHERE post_id IN (`+strings.Join(placeholders, ",")+`)
+		GROUP BY post_id, emoji
+	`, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]map[string]models.ReactionCount)
+	for rows.Next() {
+		var postID int
+		var rc models.ReactionCount
+		if err := rows.Scan(&postID, &rc.Emoji, &rc.Count, &rc.Reacted); err != nil {
+			return result, err
+		}
+		if counts[postID] == nil {
+			counts[postID] = make(map[string]models.ReactionCount)
+		}
+		counts[postID][rc.Emoji] = rc
+	}
+	if err := rows.Err(); err != nil {
+		return result, err
+	}
+	for postID, byEmoji := range counts {
+		for _, e := range emojis {
+			if rc, ok := byEmoji[e]; ok {
+				result[postID] = append(result[postID], rc)
+			}
+		}
+	}
+	return result, rows.Err()
+}
+
+func (r *socialRepository) Reactors(postID, viewerID int, emoji string, limit int, cursor *models.Cursor) ([]models.Reactor, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT pr.id, pr.emoji, u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), pr.created_at
+		FROM post_reactions pr
+		JOIN users u ON u.id = pr.user_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE pr.post_id = $1
+		  AND ($3 = '' OR pr.emoji = $3)
+		  AND (pr.created_at, pr.id) < ($5, $6)
+		  AND NOT EXISTS (
+			SELECT 1 FROM user_blocks ub
+			WHERE (ub.blocker_id = $2 AND ub.blocked_id = pr.user_id)
+			   OR (ub.blocker_id = pr.user_id AND ub.blocked_id = $2)
+		  )
+		ORDER BY pr.created_at DESC, pr.id DESC
+		LIMIT $4
+	`, postID, viewerID, emoji, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []models.Reactor
+	for rows.Next() {
+		var rc models.Reactor
+		if err := rows.Scan(&rc.ReactionID, &rc.Emoji, &rc.ID, &rc.Username, &rc.DisplayName, &rc.AvatarURL, &rc.Since); err != nil {
+			return nil, err
+		}
+		list = append(list, rc)
+	}
+	return list, rows.Err()
+}
+
 func (r *socialRepository) IncLikeCount(postID int) (int, error) {
 	var likes int
 	err := r.db.QueryRow(`
@@ -251,15 +588,280 @@ func (r *socialRepository) GetLikeCount(postID int) (int, error) {
 	return likes, err
 }
 
+// DeletePost also removes the plain reposts of the post; quotes stay and
+// show the original as unavailable.
 func (r *socialRepository) DeletePost(postID, userID int) (int, error) {
 	var deletedID int
 	err := r.db.QueryRow(`
-		DELETE FROM posts WHERE id = $1 AND user_id = $2
-		RETURNING id
+		WITH deleted AS (
+			DELETE FROM posts WHERE id = $1 AND user_id = $2
+			RETURNING id
+		), plain_reposts AS (
+			DELETE FROM posts
+			WHERE repost_id IN (SELECT id FROM deleted) AND texto = '' AND parent_id IS NULL
+		)
+		SELECT id FROM deleted
 	`, postID, userID).Scan(&deletedID)
 	return deletedID, err
 }
 
+// EditPost archives the current text in post_revisions and replaces it, in a
+// single statement so concurrent edits cannot lose a revision.
+func (r *socialRepository) EditPost(postID, userID int, texto string) (time.Time, error) {
+	var editedAt time.Time
+	err := r.db.QueryRow(`
+		WITH old AS (
+			SELECT id, texto, COALESCE(edited_at, created_at) AS since
+			FROM posts WHERE id = $1 AND user_id = $2
+			FOR UPDATE
+		), archived AS (
+			INSERT INTO post_revisions (post_id, texto, created_at)
+			SELECT id, texto, since FROM old
+		)
+		UPDATE posts p SET texto = $3, edited_at = NOW()
+		FROM old WHERE p.id = old.id
+		RETURNING p.edited_at
+	`, postID, userID, texto).Scan(&editedAt)
+	return editedAt, err
+}
+
+// PostRevisions returns the replaced texts of a post, newest first.
+func (r *socialRepository) PostRevisions(postID int) ([]models.PostRevision, error) {
+	rows, err := r.db.Query(`
+		SELECT texto, created_at FROM post_revisions
+		WHERE post_id = $1
+		ORDER BY id DESC
+	`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []models.PostRevision{}
+	for rows.Next() {
+		var rev models.PostRevision
+		if err := rows.Scan(&rev.Texto, &rev.CreatedAt); err == nil {
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+func (r *socialRepository) AddAttachments(postID int, attachments []models.Attachment) ([]models.Attachment, error) {
+	saved := make([]models.Attachment, 0, len(attachments))
+	for i, a := range attachments {
+		err := r.db.QueryRow(`
+			INSERT INTO post_attachments (post_id, position, kind, url, public_id, resource_type, alt_text, file_name, width, height, size)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			RETURNING id
+		`, postID, i, a.Kind, a.URL, a.PublicID, a.ResourceType, a.AltText, a.FileName, a.Width, a.Height, a.Size).Scan(&a.ID)
+		if err != nil {
+			return saved, err
+		}
+		saved = append(saved, a)
+	}
+	return saved, nil
+}
+
+// Attachments batch-loads the attachments of several posts, keyed by post ID.
+func (r *socialRepository) Attachments(postIDs []int) (map[int][]models.Attachment, error) {
+	result := make(map[int][]models.Attachment, len(postIDs))
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := make([]interface{}, len(postIDs))
+	for i, id := range postIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = id
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+		SELECT id, post_id, kind, url, public_id, resource_type, alt_text, file_name, width, height, size
+		FROM post_attachments
+		WHERE post_id IN (%s)
+		ORDER BY post_id, position
+	`, strings.Join(placeholders, ",")), args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a models.Attachment
+		var postID int
+		if err := rows.Scan(&a.ID, &postID, &a.Kind, &a.URL, &a.PublicID, &a.ResourceType, &a.AltText, &a.FileName, &a.Width, &a.Height, &a.Size); err == nil {
+			result[postID] = append(result[postID], a)
+		}
+	}
+	return result, nil
+}
+
+// ThreadAttachments lists the attachments of a post and of every reply below
+// it, so their files can be removed when the post is deleted.
+func (r *socialRepository) ThreadAttachments(postID int) ([]models.Attachment, error) {
+	rows, err := r.db.Query(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM posts WHERE id = $1
+			UNION ALL
+			SELECT p.id FROM posts p JOIN tree t ON p.parent_id = t.id
+		)
+		SELECT a.id, a.kind, a.url, a.public_id, a.resource_type
+		FROM post_attachments a
+		JOIN tree t ON a.post_id = t.id
+	`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var attachments []models.Attachment
+	for rows.Next() {
+		var a models.Attachment
+		if err := rows.Scan(&a.ID, &a.Kind, &a.URL, &a.PublicID, &a.ResourceType); err == nil {
+			attachments = append(attachments, a)
+		}
+	}
+	return attachments, nil
+}
+
+func (r *socialRepository) CreatePoll(postID int, poll models.PollRequest) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		INSERT INTO polls (post_id, multiple, closes_at) VALUES ($1, $2, $3)
+	`, postID, poll.Multiple, poll.ClosesAt); err != nil {
+		return err
+	}
+	for i, texto := range poll.Options {
+		if _, err := tx.Exec(`
+			INSERT INTO poll_options (post_id, position, texto) VALUES ($1, $2, $3)
+		`, postID, i, texto); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Polls batch-loads the polls of several posts, keyed by post ID, with the
+// options userID picked (Voted/MyChoices). Closed is computed here; hiding
+// results is left to the service.
+func (r *socialRepository) Polls(postIDs []int, userID int) (map[int]*models.Poll, error) {
+	result := make(map[int]*models.Poll)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := make([]string, len(postIDs))
+	args := []interface{}{userID}
+	for i, id := range postIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args = append(args, id)
+	}
+	in := strings.Join(placeholders, ",")
+
+	rows, err := r.db.Query(`
+		SELECT post_id, multiple, closes_at, voters, COALESCE(closes_at <= NOW(), FALSE),
+		       EXISTS (SELECT 1 FROM poll_ballots b WHERE b.post_id = polls.post_id AND b.user_id = $1)
+		FROM polls
+		WHERE post_id IN (`+in+`)
+	`, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p models.Poll
+		var closesAt sql.NullTime
+		if err := rows.Scan(&p.PostID, &p.Multiple, &closesAt, &p.Voters, &p.Closed, &p.Voted); err != nil {
+			continue
+		}
+		if closesAt.Valid {
+			p.ClosesAt = &closesAt.Time
+		}
+		p.Options = []models.PollOption{}
+		result[p.PostID] = &p
+	}
+	if len(result) == 0 {
+		return result, nil
+	}
+
+	optRows, err := r.db.Query(`
+		SELECT o.id, o.post_id, o.texto, o.votes,
+		       EXISTS (SELECT 1 FROM poll_votes v WHERE v.option_id = o.id AND v.user_id = $1)
+		FROM poll_options o
+		WHERE o.post_id IN (`+in+`)
+		ORDER BY o.post_id, o.position
+	`, args...)
+	if err != nil {
+		return result, err
+	}
+	defer optRows.Close()
+	for optRows.Next() {
+		var o models.PollOption
+		var postID int
+		var mine bool
+		if err := optRows.Scan(&o.ID, &postID, &o.Texto, &o.Votes, &mine); err != nil {
+			continue
+		}
+		if p, ok := result[postID]; ok {
+			p.Options = append(p.Options, o)
+			if mine {
+				p.MyChoices = append(p.MyChoices, o.ID)
+			}
+		}
+	}
+	return result, nil
+}
+
+// VotePoll records userID's ballot. It returns false when the poll is closed
+// or the user already voted; the poll_ballots primary key makes that check
+// race-free.
+func (r *socialRepository) VotePoll(postID, userID int, optionIDs []int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var dummy int
+	err = tx.QueryRow(`
+		INSERT INTO poll_ballots (post_id, user_id)
+		SELECT post_id, $2 FROM polls
+		WHERE post_id = $1 AND (closes_at IS NULL OR closes_at > NOW())
+		ON CONFLICT (post_id, user_id) DO NOTHING
+		RETURNING 1
+	`, postID, userID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	for _, optionID := range optionIDs {
+		if _, err := tx.Exec(`
+			INSERT INTO poll_votes (post_id, user_id, option_id) VALUES ($1, $2, $3)
+		`, postID, userID, optionID); err != nil {
+			return false, err
+		}
+		if _, err := tx.Exec(`
+			UPDATE poll_options SET votes = votes + 1 WHERE post_id = $1 AND id = $2
+		`, postID, optionID); err != nil {
+			return false, err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE polls SET voters = voters + 1 WHERE post_id = $1`, postID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
 func (r *socialRepository) BatchLoadReplies(parentIDs []int, userID int) (map[int][]models.Post, error) {
 	result := make(map[int][]models.Post, len(parentIDs))
 	if len(parentIDs) == 0 {
@@ -269,17 +871,21 @@ func (r *socialRepository) BatchLoadReplies(parentIDs []int, userID int) (map[in
 	placeholders := make([]string, len(parentIDs))
 	args := make([]interface{}, len(parentIDs))
 	for i, id := range parentIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		placeholders[i] = fmt.Sprintf("$%d", i+2) // $1 is userID
 		args[i] = id
 	}
 
 	query := fmt.Sprintf(`
-		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at,
-		       EXISTS(SELECT 1 FROM post_likes pl WHERE pl.post_id = p.id AND pl.user_id = $1) AS liked
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $1 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $1) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $1 AND rq.texto = '') AS reposted
 		FROM posts p
 		LEFT JOIN users u ON p.user_id = u.id
 		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
 		WHERE p.parent_id IN (%s)
+		  AND `+visibleTo("$1")+`
 		ORDER BY p.created_at ASC
 	`, strings.Join(placeholders, ","))
 
@@ -298,7 +904,7 @@ func (r *socialRepository) BatchLoadReplies(parentIDs []int, userID int) (map[in
 	for rows.Next() {
 		var r models.Post
 		var parentID int
-		if err := rows.Scan(&r.ID, &r.Texto, &r.Author, &r.AuthorName, &r.AvatarURL, &r.UserID, &parentID, &r.RepostID, &r.Likes, &r.ReplyCount, &r.CreatedAt, &r.Liked); err == nil {
+		if err := rows.Scan(&r.ID, &r.Texto, &r.Author, &r.AuthorName, &r.AvatarURL, &r.UserID, &parentID, &r.RepostID, &r.Likes, &r.ReplyCount, &r.CreatedAt, &r.EditedAt, &r.Liked, &r.Bookmarked, &r.RepostCount, &r.Reposted); err == nil {
 			r.ParentID = &parentID
 			r.Replies = []models.Post{}
 			result[parentID] = append(result[parentID], r)
@@ -307,3 +913,481 @@ func (r *socialRepository) BatchLoadReplies(parentIDs []int, userID int) (map[in
 
 	return result, nil
 }
+
+// ─── Bookmarks ───────────────────────────────────────────────────────────────
+
+func (r *socialRepository) Bookmark(userID, postID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		INSERT INTO post_bookmarks (user_id, post_id) VALUES ($1, $2)
+		ON CONFLICT (user_id, post_id) DO NOTHING
+		RETURNING 1
+	`, userID, postID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *socialRepository) Unbookmark(userID, postID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		DELETE FROM post_bookmarks WHERE user_id = $1 AND post_id = $2
+		RETURNING 1
+	`, userID, postID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Bookmarks lists the posts userID saved, most recently saved first. The
+// cursor is keyed on the bookmark time, carried in Post.BookmarkedAt.
+func (r *socialRepository) Bookmarks(userID, limit int, cursor *models.Cursor) ([]models.Post, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $1 AND pl.emoji = '👍') AS liked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $1 AND rq.texto = '') AS reposted,
+		       b.created_at
+		FROM post_bookmarks b
+		JOIN posts p ON p.id = b.post_id
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
+		WHERE b.user_id = $1
+		  AND `+visibleTo("$1")+`
+		  AND (b.created_at, p.id) < ($3, $4)
+		ORDER BY b.created_at DESC, p.id DESC
+		LIMIT $2
+	`, userID, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		var p models.Post
+		var savedAt time.Time
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.RepostCount, &p.Reposted, &savedAt); err == nil {
+			p.Bookmarked = true
+			p.BookmarkedAt = &savedAt
+			p.Replies = []models.Post{}
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+// ─── Follow graph ────────────────────────────────────────────────────────────
+
+// Follow does nothing when a block stands between the two users, so a block
+// made while the follow request was in flight still wins.
+func (r *socialRepository) Follow(followerID, followeeID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		INSERT INTO follows (follower_id, followee_id)
+		SELECT $1, $2
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+		ON CONFLICT (follower_id, followee_id) DO NOTHING
+		RETURNING 1
+	`, followerID, followeeID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *socialRepository) Unfollow(followerID, followeeID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2
+		RETURNING 1
+	`, followerID, followeeID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *socialRepository) IsFollowing(followerID, followeeID int) bool {
+	if followerID <= 0 {
+		return false
+	}
+	var exists bool
+	r.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)
+	`, followerID, followeeID).Scan(&exists)
+	return exists
+}
+
+func (r *socialRepository) FollowCounts(userID int) (int, int) {
+	var followers, following int
+	r.db.QueryRow(`
+		SELECT (SELECT COUNT(*) FROM follows WHERE followee_id = $1),
+		       (SELECT COUNT(*) FROM follows WHERE follower_id = $1)
+	`, userID).Scan(&followers, &following)
+	return followers, following
+}
+
+func (r *socialRepository) Followers(userID, limit, offset int) ([]models.UserSummary, error) {
+	return r.queryFollowList(`
+		SELECT u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.follower_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE f.followee_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+}
+
+func (r *socialRepository) Following(userID, limit, offset int) ([]models.UserSummary, error) {
+	return r.queryFollowList(`
+		SELECT u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE f.follower_id = $1
+		ORDER BY f.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+}
+
+// ─── Blocks & mutes ──────────────────────────────────────────────────────────
+
+// Block also drops any follow between the two users, in both directions.
+func (r *socialRepository) Block(blockerID, blockedID int) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var dummy int
+	err = tx.QueryRow(`
+		INSERT INTO user_blocks (blocker_id, blocked_id) VALUES ($1, $2)
+		ON CONFLICT (blocker_id, blocked_id) DO NOTHING
+		RETURNING 1
+	`, blockerID, blockedID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND followee_id = $2) OR (follower_id = $2 AND followee_id = $1)
+	`, blockerID, blockedID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *socialRepository) Unblock(blockerID, blockedID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2
+		RETURNING 1
+	`, blockerID, blockedID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *socialRepository) Mute(muterID, mutedID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		INSERT INTO user_mutes (muter_id, muted_id) VALUES ($1, $2)
+		ON CONFLICT (muter_id, muted_id) DO NOTHING
+		RETURNING 1
+	`, muterID, mutedID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *socialRepository) Unmute(muterID, mutedID int) (bool, error) {
+	var dummy int
+	err := r.db.QueryRow(`
+		DELETE FROM user_mutes WHERE muter_id = $1 AND muted_id = $2
+		RETURNING 1
+	`, muterID, mutedID).Scan(&dummy)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// IsBlocked reports whether either user blocked the other.
+func (r *socialRepository) IsBlocked(userA, userB int) bool {
+	var exists bool
+	r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM user_blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`, userA, userB).Scan(&exists)
+	return exists
+}
+
+func (r *socialRepository) Blocks(userID, limit, offset int) ([]models.UserSummary, error) {
+	return r.queryFollowList(`
+		SELECT u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), b.created_at
+		FROM user_blocks b
+		JOIN users u ON u.id = b.blocked_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+}
+
+func (r *socialRepository) Mutes(userID, limit, offset int) ([]models.UserSummary, error) {
+	return r.queryFollowList(`
+		SELECT u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), m.created_at
+		FROM user_mutes m
+		JOIN users u ON u.id = m.muted_id
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE m.muter_id = $1
+		ORDER BY m.created_at DESC
+		LIMIT $2 OFFSET $3
+	`, userID, limit, offset)
+}
+
+func (r *socialRepository) queryFollowList(query string, args ...interface{}) ([]models.UserSummary, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+	for rows.Next() {
+		var u models.UserSummary
+		if err := rows.Scan(&u.ID, &u.Username, &u.DisplayName, &u.AvatarURL, &u.Since); err == nil {
+			users = append(users, u)
+		}
+	}
+	return users, nil
+}
+
+// SetTags replaces the hashtags a post is indexed under with tags (already
+// normalized). Edits that drop a tag remove the post from that timeline.
+func (r *socialRepository) SetTags(postID int, tags []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM post_tags WHERE post_id = $1`, postID); err != nil {
+		return err
+	}
+	if len(tags) > 0 {
+		values := make([]string, len(tags))
+		args := []interface{}{postID}
+		for i, t := range tags {
+			values[i] = fmt.Sprintf("($1, $%d, (SELECT created_at FROM posts WHERE id = $1))", i+2)
+			args = append(args, t)
+		}
+		if _, err := tx.Exec(`
+			INSERT INTO post_tags (post_id, tag, created_at)
+			VALUES `+strings.Join(values, ", ")+`
+			ON CONFLICT DO NOTHING
+		`, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// TagPosts is the timeline of a hashtag: posts and replies, newest first.
+func (r *socialRepository) TagPosts(tag string, userID, limit int, cursor *models.Cursor) ([]models.Post, error) {
+	before, beforeID := keysetBounds(cursor)
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $2 AND rq.texto = '') AS reposted
+		FROM post_tags pt
+		JOIN posts p ON p.id = pt.post_id
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
+		WHERE pt.tag = $1
+		  AND `+visibleTo("$2")+`
+		  AND `+notMuted("$2")+`
+		  AND (pt.created_at, pt.post_id) < ($4, $5)
+		ORDER BY pt.created_at DESC, pt.post_id DESC
+		LIMIT $3
+	`, tag, userID, limit, before, beforeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		var p models.Post
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted); err == nil {
+			p.Replies = []models.Post{}
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
+
+// SearchPosts matches posts and replies against posts.search_vector (see
+// migrations/003_search.sql). Rank is ts_rank_cd with a small boost for liked
+// posts; Snippet carries the raw texto for the service to highlight.
+func (r *socialRepository) SearchPosts(query string, userID, limit, offset int) ([]models.SearchHit, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $2 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $2) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $2 AND rq.texto = '') AS reposted,
+		       ts_rank_cd(p.search_vector, q, 32) * (1 + ln(1 + p.likes) / 10) AS rank
+		FROM posts p
+		CROSS JOIN websearch_to_tsquery('portuguese', $1) q
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
+		WHERE p.search_vector @@ q
+		  AND `+visibleTo("$2")+`
+		ORDER BY rank DESC, p.created_at DESC, p.id DESC
+		LIMIT $3 OFFSET $4
+	`, query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var p models.Post
+		var rank float64
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted, &rank); err == nil {
+			hits = append(hits, models.SearchHit{
+				Type: models.SearchPosts, ID: p.ID, Snippet: p.Texto, Rank: rank, CreatedAt: p.CreatedAt, Post: &p,
+			})
+		}
+	}
+	return hits, nil
+}
+
+// SearchUsers ranks an exact @username first, then username prefixes, then
+// full-text matches on display name and bio.
+func (r *socialRepository) SearchUsers(query string, limit, offset int) ([]models.SearchHit, error) {
+	handle := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(query), "@"))
+	prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(handle) + "%"
+
+	rows, err := r.db.Query(`
+		SELECT u.id, u.username, COALESCE(sp.display_name, u.username), COALESCE(sp.avatar_url, ''), COALESCE(sp.bio, ''), u.created_at,
+		       CASE WHEN u.username = $2 THEN 3
+		            WHEN u.username LIKE $3 THEN 2
+		            ELSE ts_rank(to_tsvector('simple', COALESCE(sp.display_name, '') || ' ' || COALESCE(sp.bio, '')), q)
+		       END AS rank
+		FROM users u
+		CROSS JOIN plainto_tsquery('simple', $1) q
+		LEFT JOIN social_profiles sp ON sp.user_id = u.id
+		WHERE u.username LIKE $3
+		   OR to_tsvector('simple', COALESCE(sp.display_name, '') || ' ' || COALESCE(sp.bio, '')) @@ q
+		ORDER BY rank DESC, u.username
+		LIMIT $4 OFFSET $5
+	`, query, handle, prefix, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := []models.SearchHit{}
+	for rows.Next() {
+		var u models.UserSummary
+		var bio string
+		var rank float64
+		if err := rows.Scan(&u.ID, &u.Username, &u.DisplayName, &u.AvatarURL, &bio, &u.Since, &rank); err == nil {
+			hits = append(hits, models.SearchHit{
+				Type: models.SearchUsers, ID: u.ID, Title: u.DisplayName, Snippet: bio, Rank: rank, CreatedAt: u.Since, User: &u,
+			})
+		}
+	}
+	return hits, nil
+}
+
+// ─── Pins ────────────────────────────────────────────────────────────────────
+
+// SetPinnedPost pins postID to userID's profile; nil unpins.
+func (r *socialRepository) SetPinnedPost(userID int, postID *int) error {
+	_, err := r.db.Exec(`UPDATE users SET pinned_post_id = $2 WHERE id = $1`, userID, postID)
+	return err
+}
+
+func (r *socialRepository) PinnedPostID(userID int) int {
+	var id sql.NullInt64
+	r.db.QueryRow(`SELECT pinned_post_id FROM users WHERE id = $1`, userID).Scan(&id)
+	return int(id.Int64)
+}
+
+func (r *socialRepository) PinAnnouncement(postID, adminID int, until time.Time) error {
+	_, err := r.db.Exec(`
+		INSERT INTO feed_announcements (post_id, pinned_by, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (post_id) DO UPDATE
+		SET pinned_by = EXCLUDED.pinned_by, pinned_at = NOW(), expires_at = EXCLUDED.expires_at
+	`, postID, adminID, until)
+	return err
+}
+
+func (r *socialRepository) UnpinAnnouncement(postID int) (bool, error) {
+	res, err := r.db.Exec(`DELETE FROM feed_announcements WHERE post_id = $1`, postID)
+	if err != nil {
+		return false, err
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// Announcements returns the unexpired announcements userID can see, most
+// recently pinned first. Expired rows are simply ignored.
+func (r *socialRepository) Announcements(userID int) ([]models.Post, error) {
+	rows, err := r.db.Query(`
+		SELECT p.id, p.texto, COALESCE(u.username, p.author), COALESCE(sp.display_name, u.username, p.author), COALESCE(sp.avatar_url, ''), COALESCE(p.user_id, 0), p.parent_id, p.repost_id, p.likes, p.reply_count, p.created_at, p.edited_at,
+		       EXISTS(SELECT 1 FROM post_reactions pl WHERE pl.post_id = p.id AND pl.user_id = $1 AND pl.emoji = '👍') AS liked,
+		       EXISTS(SELECT 1 FROM post_bookmarks pb WHERE pb.post_id = p.id AND pb.user_id = $1) AS bookmarked,
+		       (SELECT COUNT(*) FROM posts rq WHERE rq.repost_id = p.id) AS repost_count,
+		       EXISTS(SELECT 1 FROM posts rq WHERE rq.repost_id = p.id AND rq.user_id = $1 AND rq.texto = '') AS reposted,
+		       a.expires_at
+		FROM feed_announcements a
+		JOIN posts p ON p.id = a.post_id
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN social_profiles sp ON p.user_id = sp.user_id
+		WHERE a.expires_at > NOW()
+		  AND `+visibleTo("$1")+`
+		ORDER BY a.pinned_at DESC
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []models.Post
+	for rows.Next() {
+		var p models.Post
+		var until time.Time
+		if err := rows.Scan(&p.ID, &p.Texto, &p.Author, &p.AuthorName, &p.AvatarURL, &p.UserID, &p.ParentID, &p.RepostID, &p.Likes, &p.ReplyCount, &p.CreatedAt, &p.EditedAt, &p.Liked, &p.Bookmarked, &p.RepostCount, &p.Reposted, &until); err == nil {
+			p.Pinned = true
+			p.PinnedUntil = &until
+			p.Replies = []models.Post{}
+			posts = append(posts, p)
+		}
+	}
+	return posts, nil
+}
@@ -8,9 +8,11 @@ import (
 
 type SugestoesRepository interface {
 	Listar() ([]models.Sugestao, error)
-	Criar(texto, author, categoria string) (models.Sugestao, error)
+	Criar(texto, author, categoria, motivoPendente string) (models.Sugestao, error)
 	Deletar(id int) error
 	Atualizar(id int, texto, categoria string) error
+	Pendentes() ([]models.Sugestao, error)
+	Aprovar(id int) error
 }
 
 type sugestoesRepository struct {
@@ -22,10 +24,22 @@ func NewSugestoesRepository(db *sql.DB) SugestoesRepository {
 }
 
 func (r *sugestoesRepository) Listar() ([]models.Sugestao, error) {
-	rows, err := r.db.Query(`
-		SELECT id, texto, data_criacao, COALESCE(author, 'Anônimo'), COALESCE(categoria, 'Geral')
-		FROM sugestoes ORDER BY id DESC LIMIT 200
+	return r.query(`
+		SELECT id, texto, data_criacao, COALESCE(author, 'Anônimo'), COALESCE(categoria, 'Geral'), pendente, motivo_pendente
+		FROM sugestoes WHERE pendente = FALSE ORDER BY id DESC LIMIT 200
 	`)
+}
+
+// Pendentes lista as sugestões retidas pelo filtro, mais antigas primeiro.
+func (r *sugestoesRepository) Pendentes() ([]models.Sugestao, error) {
+	return r.query(`
+		SELECT id, texto, data_criacao, COALESCE(author, 'Anônimo'), COALESCE(categoria, 'Geral'), pendente, motivo_pendente
+		FROM sugestoes WHERE pendente = TRUE ORDER BY id LIMIT 200
+	`)
+}
+
+func (r *sugestoesRepository) query(query string) ([]models.Sugestao, error) {
+	rows, err := r.db.Query(query)
 	if err != nil {
 		return nil, err
 	}
@@ -34,7 +48,7 @@ func (r *sugestoesRepository) Listar() ([]models.Sugestao, error) {
 	var lista []models.Sugestao
 	for rows.Next() {
 		var s models.Sugestao
-		if err := rows.Scan(&s.ID, &s.Texto, &s.CreatedAt, &s.Author, &s.Categoria); err != nil {
+		if err := rows.Scan(&s.ID, &s.Texto, &s.CreatedAt, &s.Author, &s.Categoria, &s.Pendente, &s.MotivoPendente); err != nil {
 			continue
 		}
 		lista = append(lista, s)
@@ -42,12 +56,13 @@ func (r *sugestoesRepository) Listar() ([]models.Sugestao, error) {
 	return lista, nil
 }
 
-func (r *sugestoesRepository) Criar(texto, author, categoria string) (models.Sugestao, error) {
+// Criar grava a sugestão; motivoPendente não vazio a deixa retida.
+func (r *sugestoesRepository) Criar(texto, author, categoria, motivoPendente string) (models.Sugestao, error) {
 	var s models.Sugestao
 	res, err := r.db.Exec(`
-		INSERT INTO sugestoes (texto, author, categoria)
-		VALUES (?, ?, ?)
-	`, texto, author, categoria)
+		INSERT INTO sugestoes (texto, author, categoria, pendente, motivo_pendente)
+		VALUES (?, ?, ?, ?, ?)
+	`, texto, author, categoria, motivoPendente != "", motivoPendente)
 	if err != nil {
 		return s, err
 	}
@@ -68,8 +83,11 @@ func (r *sugestoesRepository) Criar(texto, author, categoria string) (models.Sug
 	s.Texto = texto
 	s.Author = author
 	s.Categoria = categoria
+	s.Pendente = motivoPendente != ""
+	s.MotivoPendente = motivoPendente
 	return s, nil
 }
+
 func (r *sugestoesRepository) Deletar(id int) error {
 	_, err := r.db.Exec(`DELETE FROM sugestoes WHERE id = ?`, id)
 	return err
@@ -79,3 +97,14 @@ func (r *sugestoesRepository) Atualizar(id int, texto, categoria string) error {
 	_, err := r.db.Exec(`UPDATE sugestoes SET texto = ?, categoria = ? WHERE id = ?`, texto, categoria, id)
 	return err
 }
+
+func (r *sugestoesRepository) Aprovar(id int) error {
+	res, err := r.db.Exec(`UPDATE sugestoes SET pendente = FALSE, motivo_pendente = '' WHERE id = ? AND pendente = TRUE`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+// visibleTo is the WHERE condition every post list applies for a viewer:
+// no moderator-hidden posts (except the viewer's own) and no posts across a
+// block.
+func visibleTo(viewer string) string {
+	return `(p.hidden_at IS NULL OR p.user_id = ` + viewer + `)
+		  AND ` + notBlocked(viewer)
+}
+
+// notBlocked is a WHERE condition (on posts aliased p) that drops posts whose
+// author blocked the viewer or was blocked by them. viewer is the viewer's
+// placeholder, e.g. "$2"; anonymous viewers (0) match no block.
+func notBlocked(viewer string) string {
+	return `NOT EXISTS (
+			SELECT 1 FROM user_blocks ub
+			WHERE (ub.blocker_id = ` + viewer + ` AND ub.blocked_id = p.user_id)
+			   OR (ub.blocker_id = p.user_id AND ub.blocked_id = ` + viewer + `)
+		)`
+}
+
+// notMuted drops posts by authors the viewer muted.
+func notMuted(viewer string) string {
+	return `NOT EXISTS (
+			SELECT 1 FROM user_mutes um WHERE um.muter_id = ` + viewer + ` AND um.muted_id = p.user_id
+		)`
+}
@@ -8,12 +8,23 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func NewApp(name string) *fiber.App {
+// MaxUploadBody is how large a request may be on the routes upload(c)
+// selects: posts carry several attachments of up to 15 MB each. Every other
+// route keeps Fiber's default of 4 MB.
+const MaxUploadBody = 64 * 1024 * 1024
+
+// NewApp streams request bodies instead of buffering them, so
+// middleware.BodyLimit can refuse an oversized one before it is read.
+func NewApp(name string, upload func(*fiber.Ctx) bool) *fiber.App {
 	app := fiber.New(fiber.Config{
-		AppName:           name,
-		ReduceMemoryUsage: true,
+		AppName:                      name,
+		ReduceMemoryUsage:            true,
+		BodyLimit:                    fiber.DefaultBodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	})
 
+	app.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, MaxUploadBody, upload))
 	app.Use(compress.New(compress.Config{Level: compress.LevelBestSpeed}))
 	app.Use(cors.New(middleware.CORSConfig()))
 
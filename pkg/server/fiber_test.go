@@ -0,0 +1,50 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNewAppBodyLimit(t *testing.T) {
+	app := NewApp("teste", func(c *fiber.Ctx) bool { return c.Path() == "/upload" })
+	read := func(c *fiber.Ctx) error {
+		if len(c.Body()) == 0 {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+	app.Post("/upload", read)
+	app.Post("/json", read)
+
+	big := bytes.Repeat([]byte("a"), 5*1024*1024)
+	cases := []struct {
+		path   string
+		body   io.Reader
+		length int64
+		status int
+	}{
+		{"/json", bytes.NewReader(big), int64(len(big)), fiber.StatusRequestEntityTooLarge},
+		{"/json", bytes.NewReader(big), -1, fiber.StatusLengthRequired},
+		{"/upload", bytes.NewReader(big), int64(len(big)), fiber.StatusNoContent},
+		{"/json", bytes.NewReader([]byte("{}")), 2, fiber.StatusNoContent},
+		{"/upload", bytes.NewReader(nil), MaxUploadBody + 1, fiber.StatusRequestEntityTooLarge},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", tc.path, tc.body)
+		req.ContentLength = tc.length
+		if tc.length == -1 {
+			req.TransferEncoding = []string{"chunked"}
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("%s (%d bytes): %v", tc.path, tc.length, err)
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s (%d bytes): status %d, esperado %d", tc.path, tc.length, resp.StatusCode, tc.status)
+		}
+	}
+}
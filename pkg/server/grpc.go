@@ -0,0 +1,16 @@
+package server
+
+import (
+	"cacc/pkg/middleware"
+
+	"google.golang.org/grpc"
+)
+
+// NewGRPC is the gRPC counterpart of NewApp: every call goes through the JWT
+// interceptors before reaching a service.
+func NewGRPC() *grpc.Server {
+	return grpc.NewServer(
+		grpc.ChainUnaryInterceptor(middleware.GRPCAuthUnary),
+		grpc.ChainStreamInterceptor(middleware.GRPCAuthStream),
+	)
+}
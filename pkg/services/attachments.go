@@ -0,0 +1,121 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+const maxAltTextLen = 1000
+
+// PostMaxAttachments reads POST_MAX_ATTACHMENTS (default 4). Handlers use it
+// to refuse extra files before reading any of them.
+func PostMaxAttachments() int {
+	if n, err := strconv.Atoi(os.Getenv("POST_MAX_ATTACHMENTS")); err == nil && n >= 0 {
+		return n
+	}
+	return 4
+}
+
+// uploadAttachments validates every file before uploading any of them, then
+// sends them to Cloudinary in order. If one upload fails the ones already
+// sent are removed, so a rejected post leaves nothing behind.
+func (s *socialService) uploadAttachments(files []MediaFile) ([]models.Attachment, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	if len(files) > s.maxAttachments {
+		return nil, apperror.Validation(fmt.Sprintf("no máximo %d anexos por post", s.maxAttachments))
+	}
+
+	kinds := make([]string, len(files))
+	resourceTypes := make([]string, len(files))
+	for i, f := range files {
+		kind, resourceType, err := detectMediaKind(f.Data, f.FileName)
+		if err != nil {
+			return nil, err
+		}
+		if utf8.RuneCountInString(f.AltText) > maxAltTextLen {
+			return nil, apperror.Validation(fmt.Sprintf("texto alternativo muito longo (máx %d caracteres)", maxAltTextLen))
+		}
+		kinds[i], resourceTypes[i] = kind, resourceType
+	}
+
+	uploaded := make([]models.Attachment, 0, len(files))
+	for i, f := range files {
+		a, err := s.media.Upload(f.Data, f.FileName, "posts", resourceTypes[i])
+		if err != nil {
+			s.destroyAttachments(uploaded)
+			return nil, err
+		}
+		a.Kind = kinds[i]
+		a.AltText = f.AltText
+		uploaded = append(uploaded, a)
+	}
+	return uploaded, nil
+}
+
+// saveAttachments links uploaded files to a post that was just created. If
+// it fails the post is removed again, as in savePoll, so the client never
+// gets an error for a post that is live without its media; when even that
+// removal fails the post is left behind, logged, and both errors are
+// returned.
+func (s *socialService) saveAttachments(p *models.Post, userID int, uploaded []models.Attachment) error {
+	if len(uploaded) == 0 {
+		return nil
+	}
+	saved, err := s.repo.AddAttachments(p.ID, uploaded)
+	if err != nil {
+		s.destroyAttachments(uploaded[len(saved):])
+		if _, delErr := s.repo.DeletePost(p.ID, userID); delErr != nil {
+			log.Printf("[SOCIAL] anexos falharam e o post %d não pôde ser removido: %v", p.ID, delErr)
+			return errors.Join(err, delErr)
+		}
+		s.destroyAttachments(saved)
+		return err
+	}
+	p.Attachments = saved
+	return nil
+}
+
+func (s *socialService) destroyAttachments(attachments []models.Attachment) {
+	for _, a := range attachments {
+		s.media.Destroy(a.PublicID, a.ResourceType)
+	}
+}
+
+// attachMedia fills Attachments for posts and their loaded replies with a
+// single query.
+func (s *socialService) attachMedia(posts []models.Post) {
+	var ids []int
+	var collect func([]models.Post)
+	collect = func(ps []models.Post) {
+		for i := range ps {
+			ids = append(ids, ps[i].ID)
+			collect(ps[i].Replies)
+		}
+	}
+	collect(posts)
+	if len(ids) == 0 {
+		return
+	}
+
+	byPost, err := s.repo.Attachments(ids)
+	if err != nil || len(byPost) == 0 {
+		return
+	}
+
+	var fill func([]models.Post)
+	fill = func(ps []models.Post) {
+		for i := range ps {
+			ps[i].Attachments = byPost[ps[i].ID]
+			fill(ps[i].Replies)
+		}
+	}
+	fill(posts)
+}
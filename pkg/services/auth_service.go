@@ -18,7 +18,6 @@ import (
 	"cacc/pkg/repository"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
@@ -32,7 +31,6 @@ const (
 	userCacheTTL    = 15 * time.Minute
 	cacheCleanup    = 10 * time.Minute
 	maxSessions     = 10
-	bcryptCost      = bcrypt.DefaultCost
 )
 
 // ─── Interface ──────────────────────────────────────────────────────────────
@@ -55,7 +53,8 @@ type AuthService interface {
 	Me(userID int) (models.User, error)
 	Logout(refreshToken string, userID int) error
 	LogoutAll(userID int) error
-	Sessions(userID int) ([]models.Session, error)
+	Sessions(userID int, refreshToken string) ([]models.Session, error)
+	RevokeSession(userID, sessionID int) error
 	GetUserByUUID(uuid string) (models.User, error)
 	GetUserByIDObj(userID int) (models.User, bool)
 	GetJwtSecret() string
@@ -73,16 +72,18 @@ type cachedUser struct {
 type authService struct {
 	repo        repository.AuthRepository
 	emailSvc    EmailService
+	hasher      PasswordHasher
 	jwtSecret   string
 	oauthConfig *oauth2.Config
 	frontendURL string
+	geo         *geoLocator
 
 	mu     sync.RWMutex
 	byID   map[int]*cachedUser
 	byUUID map[string]*cachedUser
 }
 
-func NewAuthService(repo repository.AuthRepository, emailSvc EmailService, jwtSecret string) AuthService {
+func NewAuthService(repo repository.AuthRepository, emailSvc EmailService, hasher PasswordHasher, jwtSecret string) AuthService {
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:3000"
@@ -103,9 +104,11 @@ func NewAuthService(repo repository.AuthRepository, emailSvc EmailService, jwtSe
 	s := &authService{
 		repo:        repo,
 		emailSvc:    emailSvc,
+		hasher:      hasher,
 		jwtSecret:   jwtSecret,
 		oauthConfig: oauthCfg,
 		frontendURL: frontendURL,
+		geo:         newGeoLocator(),
 		byID:        make(map[int]*cachedUser),
 		byUUID:      make(map[string]*cachedUser),
 	}
@@ -129,12 +132,12 @@ func (s *authService) Register(req models.RegisterRequest, userAgent, ip string)
 		return models.AuthResponse{}, err
 	}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcryptCost)
+	hashed, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		return models.AuthResponse{}, apperror.Internal("erro interno")
 	}
 
-	user, err := s.repo.CreateUser(req.Username, string(hashed), req.Email)
+	user, err := s.repo.CreateUser(req.Username, hashed, req.Email)
 	if err != nil {
 		msg := err.Error()
 		if strings.Contains(msg, "duplicate key") || strings.Contains(msg, "users_username_key") {
@@ -185,10 +188,20 @@ func (s *authService) Login(req models.LoginRequest, userAgent, ip string) (mode
 		return models.AuthResponse{}, apperror.Unauthorized("esta conta usa login com Google")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(hashedPw), []byte(req.Password)); err != nil {
+	if ok, err := s.hasher.Verify(req.Password, hashedPw); err != nil || !ok {
 		return models.AuthResponse{}, apperror.Unauthorized("username ou senha incorretos")
 	}
 
+	// Transparent upgrade: legacy bcrypt (or outdated Argon2 params) is
+	// re-hashed now that we have the plaintext in hand.
+	if s.hasher.NeedsRehash(hashedPw) {
+		if rehashed, err := s.hasher.Hash(req.Password); err == nil {
+			if err := s.repo.UpdatePassword(user.ID, rehashed); err != nil {
+				fmt.Printf("[AUTH] rehash error for user %d: %v\n", user.ID, err)
+			}
+		}
+	}
+
 	if !user.IsVerified {
 		return models.AuthResponse{}, apperror.Unauthorized("Por favor, verifique seu e-mail antes de fazer login!")
 	}
@@ -264,12 +277,12 @@ func (s *authService) ResetPassword(req models.ResetPasswordRequest) error {
 		return apperror.Validation("token expirado, solicite um novo")
 	}
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcryptCost)
+	hashed, err := s.hasher.Hash(req.NewPassword)
 	if err != nil {
 		return apperror.Internal("erro interno")
 	}
 
-	if err := s.repo.UpdatePassword(userID, string(hashed)); err != nil {
+	if err := s.repo.UpdatePassword(userID, hashed); err != nil {
 		return apperror.Internal("erro ao redefinir a senha")
 	}
 
@@ -455,8 +468,39 @@ func (s *authService) LogoutAll(userID int) error {
 	return err
 }
 
-func (s *authService) Sessions(userID int) ([]models.Session, error) {
-	return s.repo.GetActiveSessionsByUserID(userID)
+// Sessions lists the active sessions with parsed device info. refreshToken is
+// the caller's own cookie, used only to flag which entry is the current one.
+func (s *authService) Sessions(userID int, refreshToken string) ([]models.Session, error) {
+	sessions, err := s.repo.GetActiveSessionsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	currentHash := ""
+	if refreshToken != "" {
+		currentHash = hashToken(refreshToken)
+	}
+
+	for i := range sessions {
+		info := parseUserAgent(sessions[i].UserAgent)
+		sessions[i].Device = info.Device
+		sessions[i].Browser = info.Browser
+		sessions[i].OS = info.OS
+		sessions[i].Current = currentHash != "" &&
+			subtle.ConstantTimeCompare([]byte(sessions[i].RefreshToken), []byte(currentHash)) == 1
+	}
+	return sessions, nil
+}
+
+func (s *authService) RevokeSession(userID, sessionID int) error {
+	deleted, err := s.repo.DeleteSessionForUser(sessionID, userID)
+	if err != nil {
+		return apperror.Internal("erro ao encerrar sessão")
+	}
+	if !deleted {
+		return apperror.NotFound("sessão não encontrada")
+	}
+	return nil
 }
 
 func (s *authService) GetUserByUUID(uuid string) (models.User, error) {
@@ -533,6 +577,16 @@ func (s *authService) createSessionAndRespond(user models.User, userAgent, ip st
 		return models.AuthResponse{}, apperror.Internal("erro ao criar sessão")
 	}
 
+	go func() {
+		location := s.geo.Locate(ip)
+		if location != "" {
+			if err := s.repo.SetSessionLocation(tokenHash, location); err != nil {
+				fmt.Printf("[AUTH] SetSessionLocation error for user %d: %v\n", user.ID, err)
+			}
+		}
+		s.checkNewDevice(user, userAgent, ip, location)
+	}()
+
 	return models.AuthResponse{
 		AccessToken:  s.generateAccessToken(user),
 		RefreshToken: rawRefresh,
@@ -541,6 +595,30 @@ func (s *authService) createSessionAndRespond(user models.User, userAgent, ip st
 	}, nil
 }
 
+// checkNewDevice e-mails the user when a login comes from a device class never
+// seen before. The very first device of an account is recorded silently.
+func (s *authService) checkNewDevice(user models.User, userAgent, ip, location string) {
+	info := parseUserAgent(userAgent)
+
+	known, err := s.repo.CountKnownDevices(user.ID)
+	if err != nil {
+		return
+	}
+	isNew, err := s.repo.RememberDevice(user.ID, info.Fingerprint())
+	if err != nil || !isNew || known == 0 || user.Email == "" {
+		return
+	}
+
+	if location == "" {
+		location = "Localização desconhecida"
+	}
+	when := time.Now().Format("02/01/2006 15:04")
+
+	if err := s.emailSvc.SendNewLoginAlert(user.Email, user.Username, info.Label(), location+" ("+ip+")", when); err != nil {
+		fmt.Printf("[AUTH] SendNewLoginAlert error for %s: %v\n", user.Email, err)
+	}
+}
+
 func (s *authService) generateAccessToken(user models.User) string {
 	now := time.Now()
 	claims := jwt.MapClaims{
@@ -597,6 +675,9 @@ func validatePassword(p string) error {
 	if len(p) > 128 {
 		return apperror.Validation("senha muito longa")
 	}
+	if isCommonPassword(p) {
+		return apperror.Validation("senha muito comum ou já vazada, escolha outra")
+	}
 	return nil
 }
 
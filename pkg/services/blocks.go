@@ -0,0 +1,113 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Relation kinds handled by toggleRelation.
+const (
+	relationBlock = "block"
+	relationMute  = "mute"
+)
+
+// blockedWith reports whether a block (in either direction) stands between
+// the author of a post and the viewer. Anonymous viewers are never blocked.
+func (s *socialService) blockedWith(authorID, userID int) bool {
+	return userID > 0 && authorID > 0 && authorID != userID && s.repo.IsBlocked(authorID, userID)
+}
+
+func (s *socialService) BlockedWith(authorID, userID int) bool {
+	return s.blockedWith(authorID, userID)
+}
+
+// interactable loads the post userID wants to reply to, like, repost or
+// vote on, refusing it when a block stands between them.
+func (s *socialService) interactable(postID, userID int) (models.Post, error) {
+	p, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows {
+		return models.Post{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Post{}, err
+	}
+	if s.blockedWith(p.UserID, userID) {
+		return models.Post{}, apperror.Forbidden("você não pode interagir com este usuário")
+	}
+	return p, nil
+}
+
+func (s *socialService) Block(userID int, username string) (map[string]interface{}, error) {
+	return s.toggleRelation(userID, username, relationBlock, true)
+}
+
+func (s *socialService) Unblock(userID int, username string) (map[string]interface{}, error) {
+	return s.toggleRelation(userID, username, relationBlock, false)
+}
+
+func (s *socialService) Mute(userID int, username string) (map[string]interface{}, error) {
+	return s.toggleRelation(userID, username, relationMute, true)
+}
+
+func (s *socialService) Unmute(userID int, username string) (map[string]interface{}, error) {
+	return s.toggleRelation(userID, username, relationMute, false)
+}
+
+func (s *socialService) toggleRelation(userID int, username, kind string, on bool) (map[string]interface{}, error) {
+	target, _, err := s.auth.GetUserByUsername(strings.ToLower(username))
+	if err != nil || target.ID == 0 {
+		return nil, apperror.NotFound("usuário não encontrado")
+	}
+	if target.ID == userID {
+		return nil, apperror.Validation("você não pode bloquear ou silenciar a si mesmo")
+	}
+
+	var changed bool
+	switch {
+	case kind == relationBlock && on:
+		changed, err = s.repo.Block(userID, target.ID)
+	case kind == relationBlock:
+		changed, err = s.repo.Unblock(userID, target.ID)
+	case on:
+		changed, err = s.repo.Mute(userID, target.ID)
+	default:
+		changed, err = s.repo.Unmute(userID, target.ID)
+	}
+	if err != nil {
+		return nil, apperror.Internal("erro interno na db")
+	}
+
+	if changed {
+		// Everything this user sees is cached per viewer (lid suffix). A block
+		// also hides the blocker from the other side and may drop follows.
+		s.redis.DelPattern(fmt.Sprintf("social:*:lid%d", userID))
+		if kind == relationBlock {
+			s.redis.DelPattern(fmt.Sprintf("social:*:lid%d", target.ID))
+			s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+			s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", target.ID))
+		}
+	}
+
+	key := "blocked"
+	if kind == relationMute {
+		key = "muted"
+	}
+	return map[string]interface{}{"username": target.Username, key: on}, nil
+}
+
+func (s *socialService) Blocks(userID, limit, offset int) ([]models.UserSummary, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.repo.Blocks(userID, limit, max(offset, 0))
+}
+
+func (s *socialService) Mutes(userID, limit, offset int) ([]models.UserSummary, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	return s.repo.Mutes(userID, limit, max(offset, 0))
+}
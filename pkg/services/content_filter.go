@@ -0,0 +1,438 @@
+package services
+
+import (
+	"bufio"
+	"cacc/pkg/apperror"
+	"cacc/pkg/cache"
+	"cacc/pkg/repository"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FilterAction is what a rule wants done with a text. Values are ordered by
+// severity: the pipeline keeps the most severe verdict.
+type FilterAction int
+
+const (
+	FilterAllow FilterAction = iota
+	FilterMask
+	FilterHold
+	FilterReject
+)
+
+func (a FilterAction) String() string {
+	switch a {
+	case FilterMask:
+		return "mask"
+	case FilterHold:
+		return "hold"
+	case FilterReject:
+		return "reject"
+	}
+	return "allow"
+}
+
+func parseFilterAction(v string, def FilterAction) FilterAction {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "mask":
+		return FilterMask
+	case "hold":
+		return FilterHold
+	case "reject":
+		return FilterReject
+	case "allow", "off":
+		return FilterAllow
+	}
+	return def
+}
+
+// FilterInput is one piece of text going through the pipeline. Source
+// identifies who is posting ("u:12", or "ip:1.2.3.4" for anonymous
+// sugestões) and is what velocity and duplicate checks count against.
+type FilterInput struct {
+	Kind   string // post, reply, galeria, sugestao
+	Source string
+	Text   string
+}
+
+func UserSource(userID int) string { return fmt.Sprintf("u:%d", userID) }
+func IPSource(ip string) string    { return "ip:" + ip }
+
+// FilterVerdict is a single rule's opinion. Text is only read for FilterMask.
+// Code overrides the error returned on FilterReject (default: Validation).
+type FilterVerdict struct {
+	Action FilterAction
+	Text   string
+	Reason string
+	Code   apperror.Code
+}
+
+type FilterRule interface {
+	Name() string
+	Check(in FilterInput) FilterVerdict
+}
+
+// FilterResult is the pipeline outcome: the (possibly masked) text, and when
+// Action is FilterHold, which rule asked for it and why.
+type FilterResult struct {
+	Action FilterAction
+	Text   string
+	Rule   string
+	Reason string
+}
+
+type ContentFilter interface {
+	// Check runs every rule; a rejection comes back as an *apperror.AppError.
+	Check(in FilterInput) (FilterResult, error)
+	// Hold hides freshly created content and files an automatic report so it
+	// shows up in the moderation queue.
+	Hold(targetType string, targetID, ownerID int, res FilterResult)
+}
+
+type contentFilter struct {
+	rules []FilterRule
+	mod   repository.ModerationRepository
+}
+
+func newContentFilter(mod repository.ModerationRepository, rules ...FilterRule) *contentFilter {
+	return &contentFilter{rules: rules, mod: mod}
+}
+
+// NewContentFilter builds the pipeline from the CONTENT_FILTER_* env vars.
+// Rules without configuration are left out.
+func NewContentFilter(redis *cache.Redis, mod repository.ModerationRepository) ContentFilter {
+	var rules []FilterRule
+
+	if n, window := filterRate(); n > 0 {
+		rules = append(rules, &velocityRule{
+			counter: redis,
+			limit:   n,
+			window:  window,
+			action:  parseFilterAction(os.Getenv("CONTENT_FILTER_RATE_ACTION"), FilterReject),
+		})
+	}
+
+	if window := envDuration("CONTENT_FILTER_DUPLICATE_WINDOW", 10*time.Minute); window > 0 {
+		rules = append(rules, &duplicateRule{
+			counter: redis,
+			window:  window,
+			action:  parseFilterAction(os.Getenv("CONTENT_FILTER_DUPLICATE_ACTION"), FilterReject),
+			spread:  envInt("CONTENT_FILTER_DUPLICATE_SPREAD", 5),
+		})
+	}
+
+	if n := envInt("CONTENT_FILTER_MAX_LINKS", 3); n >= 0 {
+		rules = append(rules, &linkRule{
+			max:    n,
+			action: parseFilterAction(os.Getenv("CONTENT_FILTER_LINKS_ACTION"), FilterHold),
+		})
+	}
+
+	if words := loadWordlist(); len(words) > 0 {
+		rules = append(rules, newWordlistRule(words,
+			parseFilterAction(os.Getenv("CONTENT_FILTER_WORDS_ACTION"), FilterMask)))
+	}
+
+	return newContentFilter(mod, rules...)
+}
+
+func (f *contentFilter) Check(in FilterInput) (FilterResult, error) {
+	res := FilterResult{Action: FilterAllow, Text: in.Text}
+	for _, rule := range f.rules {
+		in.Text = res.Text
+		v := rule.Check(in)
+		switch v.Action {
+		case FilterReject:
+			if v.Code == apperror.ErrTooMany {
+				return res, apperror.TooMany(v.Reason)
+			}
+			return res, apperror.Validation(v.Reason)
+		case FilterMask:
+			res.Text = v.Text
+		}
+		if v.Action > res.Action {
+			res.Action = v.Action
+		}
+		if v.Action == FilterHold && res.Rule == "" {
+			res.Rule, res.Reason = rule.Name(), v.Reason
+		}
+	}
+	return res, nil
+}
+
+func (f *contentFilter) Hold(targetType string, targetID, ownerID int, res FilterResult) {
+	if err := f.mod.Hold(targetType, targetID, ownerID, fmt.Sprintf("%s: %s", res.Rule, res.Reason)); err != nil {
+		log.Printf("[FILTER] falha ao reter %s %d: %v", targetType, targetID, err)
+	}
+}
+
+// ── Configuração ────────────────────────────────────────────────────────
+
+// filterRate parses CONTENT_FILTER_RATE as "N" (per minute) or "N/duração",
+// e.g. "5/1m" or "20/1h". "0" turns the check off.
+func filterRate() (int, time.Duration) {
+	v := strings.TrimSpace(os.Getenv("CONTENT_FILTER_RATE"))
+	if v == "" {
+		return 8, time.Minute
+	}
+	count, per, _ := strings.Cut(v, "/")
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil {
+		log.Printf("[FILTER] CONTENT_FILTER_RATE inválido (%q), usando 8/1m", v)
+		return 8, time.Minute
+	}
+	window := time.Minute
+	if per != "" {
+		d, err := time.ParseDuration(strings.TrimSpace(per))
+		if err != nil || d <= 0 {
+			log.Printf("[FILTER] CONTENT_FILTER_RATE inválido (%q), usando janela de 1m", v)
+		} else {
+			window = d
+		}
+	}
+	return n, window
+}
+
+func envInt(name string, def int) int {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("[FILTER] %s inválido (%q), usando %d", name, v, def)
+		return def
+	}
+	return n
+}
+
+func envDuration(name string, def time.Duration) time.Duration {
+	v := strings.TrimSpace(os.Getenv(name))
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("[FILTER] %s inválido (%q), usando %s", name, v, def)
+		return def
+	}
+	return d
+}
+
+// loadWordlist merges CONTENT_FILTER_WORDS (comma separated) with the file at
+// CONTENT_FILTER_WORDLIST (one term per line, # for comments).
+func loadWordlist() []string {
+	var words []string
+	for _, w := range strings.Split(os.Getenv("CONTENT_FILTER_WORDS"), ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			words = append(words, w)
+		}
+	}
+
+	path := os.Getenv("CONTENT_FILTER_WORDLIST")
+	if path == "" {
+		return words
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("[FILTER] não foi possível abrir CONTENT_FILTER_WORDLIST: %v", err)
+		return words
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			words = append(words, line)
+		}
+	}
+	return words
+}
+
+// ── Regras ──────────────────────────────────────────────────────────────
+
+// counter is the slice of cache.Redis the stateful rules need.
+type counter interface {
+	Incr(key string, ttl time.Duration) int64
+}
+
+// velocityRule limits how many texts one source can send per window.
+type velocityRule struct {
+	counter counter
+	limit   int
+	window  time.Duration
+	action  FilterAction
+}
+
+func (r *velocityRule) Name() string { return "velocity" }
+
+func (r *velocityRule) Check(in FilterInput) FilterVerdict {
+	if in.Source == "" {
+		return FilterVerdict{}
+	}
+	n := r.counter.Incr("filter:rate:"+in.Source, r.window)
+	if n <= int64(r.limit) {
+		return FilterVerdict{}
+	}
+	return FilterVerdict{
+		Action: r.action,
+		Reason: fmt.Sprintf("muitas publicações em pouco tempo (máx %d a cada %s)", r.limit, formatWindow(r.window)),
+		Code:   apperror.ErrTooMany,
+	}
+}
+
+// minDuplicateLen keeps short replies ("valeu!", "bom dia") out of the
+// duplicate check.
+const minDuplicateLen = 20
+
+// duplicateRule catches the same text sent twice by one source within the
+// window, and the same text sent by more than spread sources (copy-paste
+// campaigns), which is always held rather than rejected.
+type duplicateRule struct {
+	counter counter
+	window  time.Duration
+	action  FilterAction
+	spread  int
+}
+
+func (r *duplicateRule) Name() string { return "duplicate" }
+
+func (r *duplicateRule) Check(in FilterInput) FilterVerdict {
+	norm := normalizeForDuplicate(in.Text)
+	if utf8.RuneCountInString(norm) < minDuplicateLen {
+		return FilterVerdict{}
+	}
+	sum := sha1.Sum([]byte(norm))
+	hash := hex.EncodeToString(sum[:])
+
+	if in.Source != "" && r.counter.Incr("filter:dup:"+in.Source+":"+hash, r.window) > 1 {
+		return FilterVerdict{Action: r.action, Reason: "você já enviou este mesmo texto há pouco"}
+	}
+	if r.spread > 0 && r.counter.Incr("filter:dupall:"+hash, r.window) > int64(r.spread) {
+		return FilterVerdict{Action: FilterHold, Reason: "texto repetido por várias contas"}
+	}
+	return FilterVerdict{}
+}
+
+// normalizeForDuplicate folds case and accents and collapses everything that
+// is not a letter or digit, so trivial variations hash the same.
+func normalizeForDuplicate(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range text {
+		if !isWordRune(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+var linkRe = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s]+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|br|io|co|me|ly|gg|xyz|info|app|dev|site|online|top|link|click)\b(?:/[^\s]*)?`)
+
+func countLinks(text string) int {
+	return len(linkRe.FindAllStringIndex(text, -1))
+}
+
+// linkRule caps the number of URLs (with or without scheme) in one text.
+type linkRule struct {
+	max    int
+	action FilterAction
+}
+
+func (r *linkRule) Name() string { return "links" }
+
+func (r *linkRule) Check(in FilterInput) FilterVerdict {
+	if countLinks(in.Text) <= r.max {
+		return FilterVerdict{}
+	}
+	return FilterVerdict{Action: r.action, Reason: fmt.Sprintf("links demais (máx %d)", r.max)}
+}
+
+// wordlistRule matches whole words, ignoring case and accents. A term ending
+// in * matches any word starting with it.
+type wordlistRule struct {
+	exact    map[string]bool
+	prefixes []string
+	action   FilterAction
+}
+
+func newWordlistRule(words []string, action FilterAction) *wordlistRule {
+	r := &wordlistRule{exact: map[string]bool{}, action: action}
+	for _, w := range words {
+		prefix := strings.HasSuffix(w, "*")
+		w = foldString(strings.TrimSuffix(w, "*"))
+		if w == "" {
+			continue
+		}
+		if prefix {
+			r.prefixes = append(r.prefixes, w)
+		} else {
+			r.exact[w] = true
+		}
+	}
+	return r
+}
+
+func (r *wordlistRule) Name() string { return "wordlist" }
+
+func (r *wordlistRule) blocked(word string) bool {
+	if r.exact[word] {
+		return true
+	}
+	for _, p := range r.prefixes {
+		if strings.HasPrefix(word, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *wordlistRule) Check(in FilterInput) FilterVerdict {
+	rs := []rune(in.Text)
+	found := false
+	for i := 0; i < len(rs); {
+		if !isWordRune(rs[i]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(rs) && isWordRune(rs[j]) {
+			j++
+		}
+		if r.blocked(foldString(string(rs[i:j]))) {
+			found = true
+			// Keep the first letter so the text stays readable.
+			for k := i + 1; k < j; k++ {
+				rs[k] = '*'
+			}
+		}
+		i = j
+	}
+	if !found {
+		return FilterVerdict{}
+	}
+	return FilterVerdict{Action: r.action, Text: string(rs), Reason: "o texto contém termos não permitidos"}
+}
+
+func foldString(s string) string {
+	rs := []rune(s)
+	for i := range rs {
+		rs[i] = foldRune(rs[i])
+	}
+	return string(rs)
+}
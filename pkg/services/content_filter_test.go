@@ -0,0 +1,136 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memCounter is an in-memory stand-in for the Redis counters.
+type memCounter map[string]int64
+
+func (m memCounter) Incr(key string, _ time.Duration) int64 {
+	m[key]++
+	return m[key]
+}
+
+type stubRule struct {
+	name    string
+	verdict FilterVerdict
+	seen    string
+}
+
+func (r *stubRule) Name() string { return r.name }
+
+func (r *stubRule) Check(in FilterInput) FilterVerdict {
+	r.seen = in.Text
+	return r.verdict
+}
+
+func TestContentFilterKeepsMostSevereVerdict(t *testing.T) {
+	mask := &stubRule{name: "mask", verdict: FilterVerdict{Action: FilterMask, Text: "m****"}}
+	hold := &stubRule{name: "hold", verdict: FilterVerdict{Action: FilterHold, Reason: "suspeito"}}
+	after := &stubRule{name: "after"}
+	f := newContentFilter(nil, mask, hold, after)
+
+	res, err := f.Check(FilterInput{Text: "merda"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Action != FilterHold || res.Rule != "hold" || res.Reason != "suspeito" {
+		t.Fatalf("got %+v, want hold by rule hold", res)
+	}
+	if res.Text != "m****" || after.seen != "m****" {
+		t.Fatalf("masked text not passed along: res=%q next rule saw %q", res.Text, after.seen)
+	}
+}
+
+func TestContentFilterRejectStopsPipeline(t *testing.T) {
+	reject := &stubRule{name: "velocity", verdict: FilterVerdict{Action: FilterReject, Reason: "calma", Code: apperror.ErrTooMany}}
+	after := &stubRule{name: "after"}
+	f := newContentFilter(nil, reject, after)
+
+	_, err := f.Check(FilterInput{Text: "oi"})
+	ae, ok := err.(*apperror.AppError)
+	if !ok || ae.Code != apperror.ErrTooMany {
+		t.Fatalf("got %v, want a 429 AppError", err)
+	}
+	if after.seen != "" {
+		t.Fatal("rules after a rejection must not run")
+	}
+}
+
+func TestWordlistRuleMasksWholeWords(t *testing.T) {
+	r := newWordlistRule([]string{"Idiota", "burr*"}, FilterMask)
+
+	v := r.Check(FilterInput{Text: "Seu IDIÓTA, que burrice! idiotas não"})
+	if v.Action != FilterMask {
+		t.Fatalf("action = %v, want mask", v.Action)
+	}
+	if want := "Seu I*****, que b******! idiotas não"; v.Text != want {
+		t.Fatalf("got %q, want %q", v.Text, want)
+	}
+	if v := r.Check(FilterInput{Text: "tudo certo por aqui"}); v.Action != FilterAllow {
+		t.Fatalf("clean text got %v", v.Action)
+	}
+}
+
+func TestCountLinks(t *testing.T) {
+	cases := map[string]int{
+		"sem links aqui, só #tag e fim.":                     0,
+		"veja https://exemplo.com/a?b=1 e www.site.org":      2,
+		"promo em bit.ly/abc, loja.com.br e ganhe.xyz/agora": 3,
+		"e-mail: fulano@ufsc.br":                             1,
+	}
+	for text, want := range cases {
+		if got := countLinks(text); got != want {
+			t.Errorf("countLinks(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
+
+func TestDuplicateRule(t *testing.T) {
+	c := memCounter{}
+	r := &duplicateRule{counter: c, window: time.Minute, action: FilterReject, spread: 2}
+	text := "Compre seguidores baratos agora mesmo!!!"
+
+	if v := r.Check(FilterInput{Source: "u:1", Text: text}); v.Action != FilterAllow {
+		t.Fatalf("first send got %v", v.Action)
+	}
+	if v := r.Check(FilterInput{Source: "u:1", Text: "compre  SEGUIDORES baratos, agora mesmo"}); v.Action != FilterReject {
+		t.Fatalf("repeat from same source got %v, want reject", v.Action)
+	}
+	r.Check(FilterInput{Source: "u:2", Text: text})
+	if v := r.Check(FilterInput{Source: "u:3", Text: text}); v.Action != FilterHold {
+		t.Fatalf("same text from many sources got %v, want hold", v.Action)
+	}
+	if v := r.Check(FilterInput{Source: "u:1", Text: "valeu!"}); v.Action != FilterAllow {
+		t.Fatal("short texts must be ignored")
+	}
+	if v := r.Check(FilterInput{Source: "u:1", Text: "valeu!"}); v.Action != FilterAllow {
+		t.Fatal("short texts must be ignored")
+	}
+}
+
+func TestVelocityRule(t *testing.T) {
+	r := &velocityRule{counter: memCounter{}, limit: 3, window: time.Minute, action: FilterReject}
+	for i := range 3 {
+		if v := r.Check(FilterInput{Source: "ip:10.0.0.1"}); v.Action != FilterAllow {
+			t.Fatalf("send %d got %v", i+1, v.Action)
+		}
+	}
+	v := r.Check(FilterInput{Source: "ip:10.0.0.1"})
+	if v.Action != FilterReject || v.Code != apperror.ErrTooMany || !strings.Contains(v.Reason, "1 min") {
+		t.Fatalf("got %+v, want 429 reject", v)
+	}
+}
+
+func TestParseFilterAction(t *testing.T) {
+	if parseFilterAction(" HOLD ", FilterMask) != FilterHold {
+		t.Fatal("hold")
+	}
+	if parseFilterAction("", FilterMask) != FilterMask || parseFilterAction("banir", FilterReject) != FilterReject {
+		t.Fatal("unknown values must fall back to the default")
+	}
+}
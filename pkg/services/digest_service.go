@@ -0,0 +1,299 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	digestMaxItems  = 15  // notificações listadas no e-mail
+	digestScanLimit = 100 // lidas do banco para contar o "e mais N"
+	digestBatch     = 100
+)
+
+// DigestService e-mails a daily or weekly summary of unread notifications
+// and highlighted noticias to users who opted in.
+type DigestService interface {
+	Settings(userID int) (models.DigestSettings, error)
+	UpdateSettings(userID int, frequency string) (models.DigestSettings, error)
+	// CheckUnsubscribe tells whether token is a valid unsubscribe link,
+	// without changing anything.
+	CheckUnsubscribe(token string) error
+	// Unsubscribe turns the digest off from the signed link in the e-mail.
+	Unsubscribe(token string) error
+	// Run sends every digest due at now. Replicas may run it at the same
+	// time; each period is claimed in the database before sending.
+	Run(now time.Time) int
+	Watch(interval time.Duration)
+}
+
+type digestService struct {
+	repo     repository.NotificationRepository
+	noticias repository.NoticiasRepository
+	email    EmailService
+	secret   []byte
+	hour     int            // hora local a partir da qual o resumo sai
+	loc      *time.Location // fuso de hour e dos períodos
+	portal   string
+	api      string
+}
+
+func NewDigestService(repo repository.NotificationRepository, noticias repository.NoticiasRepository, email EmailService, secret string) DigestService {
+	hour, err := strconv.Atoi(os.Getenv("DIGEST_HOUR"))
+	if err != nil || hour < 0 || hour > 23 {
+		hour = 8
+	}
+	loc, err := time.LoadLocation(envOrDefault("DIGEST_TZ", "America/Sao_Paulo"))
+	if err != nil {
+		log.Printf("[Digest] DIGEST_TZ inválido (%v); usando o fuso do servidor", err)
+		loc = time.Local
+	}
+	return &digestService{
+		repo:     repo,
+		noticias: noticias,
+		email:    email,
+		secret:   []byte(secret),
+		hour:     hour,
+		loc:      loc,
+		portal:   strings.TrimRight(envOrDefault("FRONTEND_URL", "http://localhost:3000"), "/"),
+		api:      strings.TrimRight(envOrDefault("API_URL", "http://localhost:8082"), "/"),
+	}
+}
+
+func (s *digestService) Settings(userID int) (models.DigestSettings, error) {
+	return s.repo.DigestSettings(userID)
+}
+
+func (s *digestService) UpdateSettings(userID int, frequency string) (models.DigestSettings, error) {
+	switch frequency {
+	case models.DigestOff, models.DigestDaily, models.DigestWeekly:
+	default:
+		return models.DigestSettings{}, apperror.Validation("frequência inválida; use off, daily ou weekly")
+	}
+	if err := s.repo.SetDigestFrequency(userID, frequency); err != nil {
+		return models.DigestSettings{}, err
+	}
+	return s.repo.DigestSettings(userID)
+}
+
+func (s *digestService) CheckUnsubscribe(token string) error {
+	if _, ok := parseDigestUnsubscribeToken(s.secret, token); !ok {
+		return apperror.Validation("link de cancelamento inválido")
+	}
+	return nil
+}
+
+func (s *digestService) Unsubscribe(token string) error {
+	userID, ok := parseDigestUnsubscribeToken(s.secret, token)
+	if !ok {
+		return apperror.Validation("link de cancelamento inválido")
+	}
+	return s.repo.SetDigestFrequency(userID, models.DigestOff)
+}
+
+func (s *digestService) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if sent := s.Run(now); sent > 0 {
+			log.Printf("[Digest] %d resumos enviados", sent)
+		}
+	}
+}
+
+func (s *digestService) Run(now time.Time) int {
+	// Destaques são os mesmos para todo mundo; cada um recebe só os que
+	// saíram desde o seu último resumo.
+	destaques, err := s.noticias.Destaques()
+	if err != nil {
+		log.Printf("[Digest] erro ao carregar destaques: %v", err)
+	}
+
+	sent := 0
+	for _, frequency := range []string{models.DigestDaily, models.DigestWeekly} {
+		period, due := digestPeriod(frequency, now.In(s.loc), s.hour)
+		if !due {
+			continue
+		}
+		afterID := 0
+		for {
+			batch, err := s.repo.DueDigests(frequency, period, afterID, digestBatch)
+			if err != nil {
+				log.Printf("[Digest] erro ao listar destinatários: %v", err)
+				break
+			}
+			for _, r := range batch {
+				afterID = r.UserID
+				if s.send(r, period, now, destaques) {
+					sent++
+				}
+			}
+			if len(batch) < digestBatch {
+				break
+			}
+		}
+	}
+	return sent
+}
+
+// send claims period for r and e-mails the digest, if there is anything to
+// tell. A failed e-mail releases the claim so the next run tries again.
+func (s *digestService) send(r models.DigestRecipient, period string, now time.Time, destaques []models.Noticia) bool {
+	lastSent, claimed, err := s.repo.ClaimDigest(r.UserID, period)
+	if err != nil || !claimed {
+		return false
+	}
+
+	since := digestSince(r.Frequency, now, lastSent)
+	digest := models.Digest{
+		Frequency:      r.Frequency,
+		PortalURL:      s.portal,
+		UnsubscribeURL: s.api + "/notifications/digest/unsubscribe?token=" + digestUnsubscribeToken(s.secret, r.UserID),
+	}
+
+	if prefs, err := s.repo.Preferences(r.UserID); err == nil {
+		byEmail := make(map[string]bool, len(prefs))
+		for _, p := range prefs {
+			byEmail[p.Category] = p.Email
+		}
+		if notifs, err := s.repo.UnreadSince(r.UserID, since, digestScanLimit); err == nil {
+			for _, n := range notifs {
+				if !byEmail[n.Category] {
+					continue
+				}
+				if len(digest.Notifications) < digestMaxItems {
+					digest.Notifications = append(digest.Notifications, n)
+				} else {
+					digest.More++
+				}
+			}
+		}
+	}
+	for _, n := range destaques {
+		if n.CreatedAt.After(since) {
+			digest.Noticias = append(digest.Noticias, n)
+		}
+	}
+
+	if len(digest.Notifications) == 0 && len(digest.Noticias) == 0 {
+		return false
+	}
+	if err := s.email.SendDigest(r.Email, r.Username, digest); err != nil {
+		log.Printf("[Digest] erro ao enviar para o usuário %d: %v", r.UserID, err)
+		s.repo.ReleaseDigest(r.UserID, lastSent)
+		return false
+	}
+	return true
+}
+
+// digestPeriod names the period a digest of frequency covers at now and tells
+// whether it may go out yet: daily ones from hour on each day, weekly ones
+// from hour on Monday for the whole ISO week. Days and hours are read in
+// now's location, so callers pass it already in DIGEST_TZ.
+func digestPeriod(frequency string, now time.Time, hour int) (string, bool) {
+	switch frequency {
+	case models.DigestDaily:
+		return now.Format("2006-01-02"), now.Hour() >= hour
+	case models.DigestWeekly:
+		year, week := now.ISOWeek()
+		early := now.Weekday() == time.Monday && now.Hour() < hour
+		return fmt.Sprintf("%d-W%02d", year, week), !early
+	}
+	return "", false
+}
+
+// digestSince is where a digest starts: the previous one, but never further
+// back than one period.
+func digestSince(frequency string, now time.Time, lastSent *time.Time) time.Time {
+	window := 24 * time.Hour
+	if frequency == models.DigestWeekly {
+		window = 7 * 24 * time.Hour
+	}
+	since := now.Add(-window)
+	if lastSent != nil && lastSent.After(since) {
+		since = *lastSent
+	}
+	return since
+}
+
+// digestUnsubscribeToken is "{userID}.{HMAC}", so the link works without a
+// login and can't be forged for someone else.
+func digestUnsubscribeToken(secret []byte, userID int) string {
+	id := strconv.Itoa(userID)
+	return id + "." + digestSignature(secret, id)
+}
+
+func parseDigestUnsubscribeToken(secret []byte, token string) (int, bool) {
+	id, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return 0, false
+	}
+	userID, err := strconv.Atoi(id)
+	if err != nil || userID <= 0 {
+		return 0, false
+	}
+	if !hmac.Equal([]byte(sig), []byte(digestSignature(secret, id))) {
+		return 0, false
+	}
+	return userID, true
+}
+
+func digestSignature(secret []byte, id string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte("digest-unsubscribe:" + id))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// digestVerbs are the singular and plural forms for notifications with an
+// actor ("ana curtiu", "ana e mais 2 curtiram").
+var digestVerbs = map[string][2]string{
+	"like":     {"curtiu seu post", "curtiram seu post"},
+	"reaction": {"reagiu ao seu post", "reagiram ao seu post"},
+	"reply":    {"respondeu seu post", "responderam seu post"},
+	"mention":  {"mencionou você", "mencionaram você"},
+	"repost":   {"repostou seu post", "repostaram seu post"},
+	"quote":    {"citou seu post", "citaram seu post"},
+	"follow":   {"começou a seguir você", "começaram a seguir você"},
+}
+
+// digestSystemTexts describe notifications without an actor.
+var digestSystemTexts = map[string]string{
+	"content_hidden":     "Um post seu foi ocultado pela moderação",
+	"content_removed":    "Um conteúdo seu foi removido pela moderação",
+	"moderation_warning": "Você recebeu um aviso da moderação",
+	"account_suspended":  "Sua conta foi suspensa temporariamente",
+	"report_dismissed":   "Uma denúncia sua foi analisada",
+	"report_actioned":    "Uma denúncia sua foi analisada e gerou uma ação",
+}
+
+// digestNotificationText is the one-line description of a notification in
+// the e-mail.
+func digestNotificationText(n models.Notification) string {
+	verbs, ok := digestVerbs[n.Type]
+	if !ok {
+		if text, ok := digestSystemTexts[n.Type]; ok {
+			return text
+		}
+		return "Você tem um novo aviso do portal"
+	}
+
+	actor := n.ActorName
+	if actor == "" {
+		actor = "Alguém"
+	}
+	if n.ActorCount > 1 {
+		return fmt.Sprintf("%s e mais %d %s", actor, n.ActorCount-1, verbs[1])
+	}
+	return actor + " " + verbs[0]
+}
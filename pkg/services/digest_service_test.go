@@ -0,0 +1,82 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"testing"
+	"time"
+)
+
+func TestDigestPeriod(t *testing.T) {
+	loc := time.FixedZone("BRT", -3*3600)
+	cases := []struct {
+		frequency string
+		now       time.Time
+		period    string
+		due       bool
+	}{
+		{models.DigestDaily, time.Date(2026, 10, 18, 7, 59, 0, 0, loc), "2026-10-18", false},
+		{models.DigestDaily, time.Date(2026, 10, 18, 8, 0, 0, 0, loc), "2026-10-18", true},
+		{models.DigestWeekly, time.Date(2026, 10, 19, 7, 0, 0, 0, loc), "2026-W43", false}, // segunda cedo
+		{models.DigestWeekly, time.Date(2026, 10, 19, 9, 0, 0, 0, loc), "2026-W43", true},
+		{models.DigestWeekly, time.Date(2026, 10, 21, 3, 0, 0, 0, loc), "2026-W43", true}, // atrasado, mesma semana
+		{models.DigestOff, time.Date(2026, 10, 18, 9, 0, 0, 0, loc), "", false},
+	}
+	for _, c := range cases {
+		period, due := digestPeriod(c.frequency, c.now, 8)
+		if period != c.period || due != c.due {
+			t.Errorf("digestPeriod(%s, %v) = %q, %v; want %q, %v", c.frequency, c.now, period, due, c.period, c.due)
+		}
+	}
+}
+
+func TestDigestSince(t *testing.T) {
+	now := time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC)
+
+	if got := digestSince(models.DigestDaily, now, nil); !got.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("sem envio anterior: got %v", got)
+	}
+	recent := now.Add(-3 * time.Hour)
+	if got := digestSince(models.DigestDaily, now, &recent); !got.Equal(recent) {
+		t.Errorf("envio recente: got %v, want %v", got, recent)
+	}
+	old := now.Add(-30 * 24 * time.Hour)
+	if got := digestSince(models.DigestWeekly, now, &old); !got.Equal(now.Add(-7 * 24 * time.Hour)) {
+		t.Errorf("envio antigo deve ficar limitado a uma semana: got %v", got)
+	}
+}
+
+func TestDigestUnsubscribeToken(t *testing.T) {
+	secret := []byte("segredo")
+	token := digestUnsubscribeToken(secret, 42)
+
+	if id, ok := parseDigestUnsubscribeToken(secret, token); !ok || id != 42 {
+		t.Fatalf("token válido recusado: %d, %v", id, ok)
+	}
+	sig := token[len("42."):]
+	for _, bad := range []string{"", "42", "43." + sig, token + "x", "0." + sig, "abc." + sig} {
+		if _, ok := parseDigestUnsubscribeToken(secret, bad); ok {
+			t.Errorf("token %q deveria ser recusado", bad)
+		}
+	}
+	if _, ok := parseDigestUnsubscribeToken([]byte("outro"), token); ok {
+		t.Error("token assinado com outro segredo foi aceito")
+	}
+}
+
+func TestDigestNotificationText(t *testing.T) {
+	cases := []struct {
+		n    models.Notification
+		want string
+	}{
+		{models.Notification{Type: "like", ActorName: "ana", ActorCount: 1}, "ana curtiu seu post"},
+		{models.Notification{Type: "like", ActorName: "ana", ActorCount: 13}, "ana e mais 12 curtiram seu post"},
+		{models.Notification{Type: "mention", ActorCount: 1}, "Alguém mencionou você"},
+		{models.Notification{Type: "moderation_warning"}, "Você recebeu um aviso da moderação"},
+		{models.Notification{Type: "novidade"}, "Você tem um novo aviso do portal"},
+	}
+	for _, c := range cases {
+		if got := digestNotificationText(c.n); got != c.want {
+			t.Errorf("digestNotificationText(%s) = %q, want %q", c.n.Type, got, c.want)
+		}
+	}
+}
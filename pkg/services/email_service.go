@@ -1,10 +1,13 @@
 package services
 
 import (
+	"cacc/pkg/models"
 	"crypto/tls"
 	"fmt"
+	"html"
 	"net/smtp"
 	"os"
+	"strings"
 )
 
 // EmailService sends transactional emails via SMTP.
@@ -13,6 +16,8 @@ import (
 type EmailService interface {
 	SendPasswordReset(toEmail, username, resetURL string) error
 	SendEmailVerification(toEmail, username, verifyURL string) error
+	SendNewLoginAlert(toEmail, username, device, location, when string) error
+	SendDigest(toEmail, username string, digest models.Digest) error
 }
 
 // ---------------------------------------------------------------------------
@@ -71,18 +76,43 @@ func (e *emailService) SendEmailVerification(toEmail, username, verifyURL string
 	return e.sendSMTP(toEmail, subject, body)
 }
 
+func (e *emailService) SendNewLoginAlert(toEmail, username, device, location, when string) error {
+	subject := fmt.Sprintf("Novo login detectado – %s", e.appName)
+	body := e.buildNewLoginEmail(username, device, location, when)
+
+	return e.sendSMTP(toEmail, subject, body)
+}
+
+func (e *emailService) SendDigest(toEmail, username string, digest models.Digest) error {
+	subject := fmt.Sprintf("Seu resumo diário – %s", e.appName)
+	if digest.Frequency == models.DigestWeekly {
+		subject = fmt.Sprintf("Seu resumo semanal – %s", e.appName)
+	}
+	body := e.buildDigestEmail(username, digest)
+
+	// One-click unsubscribe (RFC 8058): clientes de e-mail mostram o botão
+	// "cancelar inscrição" e fazem o POST sozinhos.
+	return e.sendSMTP(toEmail, subject, body,
+		fmt.Sprintf("List-Unsubscribe: <%s>", digest.UnsubscribeURL),
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click",
+	)
+}
+
 // ---------------------------------------------------------------------------
 // SMTP implementation
 // ---------------------------------------------------------------------------
 
-func (e *emailService) sendSMTP(to, subject, htmlBody string) error {
+func (e *emailService) sendSMTP(to, subject, htmlBody string, extraHeaders ...string) error {
 	if e.host == "" || e.port == "" {
 		return fmt.Errorf("SMTP não configurado (SMTP_HOST/SMTP_PORT ausentes no ambiente)")
 	}
 
 	// Prepare message headers
-	headers := fmt.Sprintf("From: <%s>\r\nTo: <%s>\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=utf-8\r\n\r\n", e.from, to, subject)
-	body := headers + htmlBody
+	headers := fmt.Sprintf("From: <%s>\r\nTo: <%s>\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/html; charset=utf-8\r\n", e.from, to, subject)
+	for _, h := range extraHeaders {
+		headers += h + "\r\n"
+	}
+	body := headers + "\r\n" + htmlBody
 
 	// Connect to SMTP server with STARTTLS
 	addr := fmt.Sprintf("%s:%s", e.host, e.port)
@@ -346,6 +376,221 @@ func (e *emailService) buildVerificationEmail(username, verifyURL string) string
 </html>`, e.appName, username, verifyURL)
 }
 
+func (e *emailService) buildNewLoginEmail(username, device, location, when string) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Novo Login - %[1]s</title>
+    <style type="text/css">
+        body, table, td, a { -webkit-text-size-adjust: 100%%; -ms-text-size-adjust: 100%%; }
+        table, td { mso-table-lspace: 0pt; mso-table-rspace: 0pt; }
+        table { border-collapse: collapse !important; }
+        body { height: 100%% !important; margin: 0 !important; padding: 0 !important; width: 100%% !important; }
+    </style>
+</head>
+<body style="margin: 0; padding: 0; background-color: #008080; font-family: 'MS Sans Serif', Tahoma, Geneva, sans-serif;">
+
+    <table border="0" cellpadding="0" cellspacing="0" width="100%%" style="background-color: #008080; padding: 40px 20px;">
+        <tr>
+            <td align="center">
+                
+                <table border="0" cellpadding="2" cellspacing="0" width="100%%" style="max-width: 450px; background-color: #c0c0c0; border-top: 2px solid #ffffff; border-left: 2px solid #ffffff; border-bottom: 2px solid #000000; border-right: 2px solid #000000;">
+                    <tr>
+                        <td>
+                            
+                            <table border="0" cellpadding="4" cellspacing="0" width="100%%" style="background-color: #000080; border: 1px solid #c0c0c0;">
+                                <tr>
+                                    <td style="color: #ffffff; font-weight: bold; font-size: 13px; font-family: 'MS Sans Serif', Tahoma, sans-serif; letter-spacing: 0.5px;">
+                                        Alerta_de_Seguranca.exe
+                                    </td>
+                                    <td align="right" width="20">
+                                        <table border="0" cellpadding="0" cellspacing="0" style="background-color: #c0c0c0; border-top: 1px solid #ffffff; border-left: 1px solid #ffffff; border-bottom: 1px solid #000000; border-right: 1px solid #000000; height: 16px; width: 16px;">
+                                            <tr>
+                                                <td align="center" valign="middle" style="color: #000000; font-size: 10px; font-weight: bold; font-family: Arial, sans-serif; line-height: 1;">
+                                                    X
+                                                </td>
+                                            </tr>
+                                        </table>
+                                    </td>
+                                </tr>
+                            </table>
+
+                            <table border="0" cellpadding="15" cellspacing="0" width="100%%">
+                                <tr>
+                                    <td style="color: #000000; font-size: 13px; font-family: 'MS Sans Serif', Tahoma, sans-serif; line-height: 1.5;">
+                                        <p style="margin-top: 0;"><b>Aviso do Sistema para %[2]s:</b></p>
+                                        <p>Detectamos um login na sua conta do <b>%[1]s</b> a partir de um dispositivo que ainda não conhecíamos.</p>
+
+                                        <table border="0" cellpadding="4" cellspacing="0" width="100%%" style="background-color: #ffffff; border-top: 2px solid #808080; border-left: 2px solid #808080; border-bottom: 2px solid #ffffff; border-right: 2px solid #ffffff; margin: 15px 0; font-size: 12px;">
+                                            <tr><td width="90"><b>Dispositivo:</b></td><td>%[3]s</td></tr>
+                                            <tr><td><b>Local:</b></td><td>%[4]s</td></tr>
+                                            <tr><td><b>Quando:</b></td><td>%[5]s</td></tr>
+                                        </table>
+
+                                        <p>Se foi você, nenhuma ação é necessária.</p>
+                                        <p>Se você não reconhece este acesso, encerre a sessão em <b>Configurações &gt; Sessões</b> e redefina sua senha imediatamente.</p>
+
+                                        <hr style="border: none; border-top: 1px solid #808080; border-bottom: 1px solid #ffffff; margin: 20px 0;">
+
+                                        <p style="margin: 0; font-size: 11px; text-align: center; color: #555555;">
+                                            Este é um aviso automático de segurança. Não responda este e-mail.
+                                        </p>
+                                    </td>
+                                </tr>
+                            </table>
+
+                        </td>
+                    </tr>
+                </table>
+                <p style="color: #ffffff; font-size: 11px; font-family: 'MS Sans Serif', Tahoma, sans-serif; text-align: center; margin-top: 20px;">
+                    © 2006-2026 %[1]s. Todos os direitos reservados.
+                </p>
+
+            </td>
+        </tr>
+    </table>
+
+</body>
+</html>`, e.appName, username, device, location, when)
+}
+
+func (e *emailService) buildDigestEmail(username string, digest models.Digest) string {
+	windowTitle, period := "Resumo_Diario.exe", "desde ontem"
+	if digest.Frequency == models.DigestWeekly {
+		windowTitle, period = "Resumo_Semanal.exe", "nesta semana"
+	}
+
+	var notifs strings.Builder
+	if len(digest.Notifications) > 0 {
+		notifs.WriteString(`<p><b>Notificações não lidas:</b></p>
+                                        <table border="0" cellpadding="4" cellspacing="0" width="100%" style="background-color: #ffffff; border-top: 2px solid #808080; border-left: 2px solid #808080; border-bottom: 2px solid #ffffff; border-right: 2px solid #ffffff; margin: 10px 0 15px 0; font-size: 12px;">`)
+		for _, n := range digest.Notifications {
+			fmt.Fprintf(&notifs, `
+                                            <tr><td>&#9658; %s</td></tr>`, html.EscapeString(digestNotificationText(n)))
+		}
+		if digest.More > 0 {
+			fmt.Fprintf(&notifs, `
+                                            <tr><td style="color: #555555;"><i>... e mais %d</i></td></tr>`, digest.More)
+		}
+		notifs.WriteString(`
+                                        </table>`)
+	}
+
+	var noticias strings.Builder
+	if len(digest.Noticias) > 0 {
+		noticias.WriteString(`<p><b>Notícias em destaque:</b></p>
+                                        <table border="0" cellpadding="4" cellspacing="0" width="100%" style="background-color: #ffffff; border-top: 2px solid #808080; border-left: 2px solid #808080; border-bottom: 2px solid #ffffff; border-right: 2px solid #ffffff; margin: 10px 0 15px 0; font-size: 12px;">`)
+		for _, n := range digest.Noticias {
+			fmt.Fprintf(&noticias, `
+                                            <tr><td><a href="%s/noticias/%d" target="_blank" style="color: #000080; text-decoration: underline;">%s</a></td></tr>`,
+				digest.PortalURL, n.ID, html.EscapeString(n.Titulo))
+		}
+		noticias.WriteString(`
+                                        </table>`)
+	}
+
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html lang="pt-BR">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Resumo - %[1]s</title>
+    <style type="text/css">
+        body, table, td, a { -webkit-text-size-adjust: 100%%; -ms-text-size-adjust: 100%%; }
+        table, td { mso-table-lspace: 0pt; mso-table-rspace: 0pt; }
+        img { -ms-interpolation-mode: bicubic; }
+        img { border: 0; height: auto; line-height: 100%%; outline: none; text-decoration: none; }
+        table { border-collapse: collapse !important; }
+        body { height: 100%% !important; margin: 0 !important; padding: 0 !important; width: 100%% !important; }
+        
+        .win-button:hover {
+            border-top: 2px solid #000000 !important;
+            border-left: 2px solid #000000 !important;
+            border-bottom: 2px solid #ffffff !important;
+            border-right: 2px solid #ffffff !important;
+            padding: 6px 14px 4px 16px !important;
+        }
+    </style>
+</head>
+<body style="margin: 0; padding: 0; background-color: #008080; font-family: 'MS Sans Serif', Tahoma, Geneva, sans-serif;">
+
+    <table border="0" cellpadding="0" cellspacing="0" width="100%%" style="background-color: #008080; padding: 40px 20px;">
+        <tr>
+            <td align="center">
+                
+                <table border="0" cellpadding="2" cellspacing="0" width="100%%" style="max-width: 450px; background-color: #c0c0c0; border-top: 2px solid #ffffff; border-left: 2px solid #ffffff; border-bottom: 2px solid #000000; border-right: 2px solid #000000;">
+                    <tr>
+                        <td>
+                            
+                            <table border="0" cellpadding="4" cellspacing="0" width="100%%" style="background-color: #000080; border: 1px solid #c0c0c0;">
+                                <tr>
+                                    <td style="color: #ffffff; font-weight: bold; font-size: 13px; font-family: 'MS Sans Serif', Tahoma, sans-serif; letter-spacing: 0.5px;">
+                                        %[3]s
+                                    </td>
+                                    <td align="right" width="20">
+                                        <table border="0" cellpadding="0" cellspacing="0" style="background-color: #c0c0c0; border-top: 1px solid #ffffff; border-left: 1px solid #ffffff; border-bottom: 1px solid #000000; border-right: 1px solid #000000; height: 16px; width: 16px;">
+                                            <tr>
+                                                <td align="center" valign="middle" style="color: #000000; font-size: 10px; font-weight: bold; font-family: Arial, sans-serif; line-height: 1;">
+                                                    X
+                                                </td>
+                                            </tr>
+                                        </table>
+                                    </td>
+                                </tr>
+                            </table>
+
+                            <table border="0" cellpadding="15" cellspacing="0" width="100%%">
+                                <tr>
+                                    <td style="color: #000000; font-size: 13px; font-family: 'MS Sans Serif', Tahoma, sans-serif; line-height: 1.5;">
+                                        <p style="margin-top: 0;"><b>Olá, %[2]s!</b></p>
+                                        <p>Veja o que aconteceu no <b>%[1]s</b> %[4]s:</p>
+
+                                        %[5]s
+                                        %[6]s
+
+                                        <table border="0" cellpadding="0" cellspacing="0" width="100%%" style="margin: 25px 0;">
+                                            <tr>
+                                                <td align="center">
+                                                    <table border="0" cellpadding="0" cellspacing="0" class="win-button" style="background-color: #c0c0c0; border-top: 2px solid #ffffff; border-left: 2px solid #ffffff; border-bottom: 2px solid #000000; border-right: 2px solid #000000;">
+                                                        <tr>
+                                                            <td align="center" style="padding: 5px 15px;">
+                                                                <a href="%[7]s/notifications" target="_blank" style="text-decoration: none; color: #000000; font-weight: bold; font-size: 13px; font-family: 'MS Sans Serif', Tahoma, sans-serif; display: block;">
+                                                                    &nbsp;ABRIR O PORTAL&nbsp;
+                                                                </a>
+                                                            </td>
+                                                        </tr>
+                                                    </table>
+                                                </td>
+                                            </tr>
+                                        </table>
+
+                                        <hr style="border: none; border-top: 1px solid #808080; border-bottom: 1px solid #ffffff; margin: 20px 0;">
+
+                                        <p style="margin: 0; font-size: 11px; text-align: center; color: #555555;">
+                                            Você recebe este resumo porque ativou os e-mails de notificação.<br>
+                                            <a href="%[8]s" style="color: #000080; text-decoration: underline;">Cancelar inscrição</a> com um clique.
+                                        </p>
+                                    </td>
+                                </tr>
+                            </table>
+
+                        </td>
+                    </tr>
+                </table>
+                <p style="color: #ffffff; font-size: 11px; font-family: 'MS Sans Serif', Tahoma, sans-serif; text-align: center; margin-top: 20px;">
+                    © 2006-2026 %[1]s. Todos os direitos reservados.
+                </p>
+
+            </td>
+        </tr>
+    </table>
+
+</body>
+</html>`, e.appName, html.EscapeString(username), windowTitle, period, notifs.String(), noticias.String(), digest.PortalURL, digest.UnsubscribeURL)
+}
+
 // ---------------------------------------------------------------------------
 // Helpers
 // ---------------------------------------------------------------------------
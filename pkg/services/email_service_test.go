@@ -1,6 +1,7 @@
 package services
 
 import (
+	"cacc/pkg/models"
 	"net"
 	"net/smtp"
 	"os"
@@ -282,3 +283,52 @@ func TestSendPasswordReset_Integration(t *testing.T) {
 		}
 	}
 }
+
+// TestBuildNewLoginEmail verifica se o alerta de novo login contém os detalhes do acesso.
+func TestBuildNewLoginEmail(t *testing.T) {
+	svc := newTestEmailService("", "", "", "", "", "Portal Teste")
+
+	html := svc.buildNewLoginEmail("Maria", "Chrome em Windows (Desktop)", "Campinas, São Paulo, Brasil (200.1.2.3)", "18/10/2026 14:30")
+
+	for _, term := range []string{"Portal Teste", "Maria", "Chrome em Windows (Desktop)", "Campinas, São Paulo, Brasil (200.1.2.3)", "18/10/2026 14:30"} {
+		if !strings.Contains(html, term) {
+			t.Errorf("HTML gerado não contém '%s'", term)
+		}
+	}
+}
+
+// TestBuildDigestEmail verifica o resumo: itens, notícias, link de
+// cancelamento e escape do que vem de usuários.
+func TestBuildDigestEmail(t *testing.T) {
+	svc := newTestEmailService("", "", "", "", "", "Portal Teste")
+
+	html := svc.buildDigestEmail("Maria", models.Digest{
+		Frequency: models.DigestWeekly,
+		Notifications: []models.Notification{
+			{Type: "reply", ActorName: "<script>", ActorCount: 1},
+		},
+		More:           3,
+		Noticias:       []models.Noticia{{ID: 7, Titulo: "Semana de Computação"}},
+		PortalURL:      "https://portal.example.com",
+		UnsubscribeURL: "https://api.example.com/notifications/digest/unsubscribe?token=1.abc",
+	})
+
+	for _, term := range []string{
+		"Portal Teste",
+		"Maria",
+		"Resumo_Semanal.exe",
+		"&lt;script&gt; respondeu seu post",
+		"e mais 3",
+		"https://portal.example.com/noticias/7",
+		"Semana de Computação",
+		"https://api.example.com/notifications/digest/unsubscribe?token=1.abc",
+		`width="100%"`,
+	} {
+		if !strings.Contains(html, term) {
+			t.Errorf("HTML gerado não contém '%s'", term)
+		}
+	}
+	if strings.Contains(html, "<script>") || strings.Contains(html, "%!") || strings.Contains(html, "100%%") {
+		t.Error("HTML com conteúdo não escapado ou formatação quebrada")
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"cacc/pkg/cache"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+// testRedis points cache.New at an in-memory Redis that lives for the test.
+func testRedis(t *testing.T) *cache.Redis {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	t.Setenv("REDIS_URL", "redis://"+mr.Addr())
+	return cache.New()
+}
+
+// fakeSocialRepo keeps posts in memory. Methods a test doesn't set up fall
+// through to the nil embedded interface and panic, which flags unexpected
+// calls.
+type fakeSocialRepo struct {
+	repository.SocialRepository
+
+	posts     map[int]models.Post
+	revisions map[int][]models.PostRevision
+	blocks    map[[2]int]bool
+	follows   map[[2]int]bool
+	edits     []string
+}
+
+func newFakeSocialRepo() *fakeSocialRepo {
+	return &fakeSocialRepo{
+		posts:     map[int]models.Post{},
+		revisions: map[int][]models.PostRevision{},
+		blocks:    map[[2]int]bool{},
+		follows:   map[[2]int]bool{},
+	}
+}
+
+func (f *fakeSocialRepo) Thread(postID, userID int) (models.Post, error) {
+	p, ok := f.posts[postID]
+	if !ok {
+		return models.Post{}, sql.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakeSocialRepo) IsBlocked(userA, userB int) bool {
+	return f.blocks[[2]int{userA, userB}] || f.blocks[[2]int{userB, userA}]
+}
+
+func (f *fakeSocialRepo) Follow(followerID, followeeID int) (bool, error) {
+	key := [2]int{followerID, followeeID}
+	changed := !f.follows[key]
+	f.follows[key] = true
+	return changed, nil
+}
+
+func (f *fakeSocialRepo) IsFollowing(followerID, followeeID int) bool {
+	return f.follows[[2]int{followerID, followeeID}]
+}
+
+func (f *fakeSocialRepo) Unfollow(followerID, followeeID int) (bool, error) {
+	key := [2]int{followerID, followeeID}
+	changed := f.follows[key]
+	delete(f.follows, key)
+	return changed, nil
+}
+
+func (f *fakeSocialRepo) FollowCounts(userID int) (int, int) {
+	followers := 0
+	for key := range f.follows {
+		if key[1] == userID {
+			followers++
+		}
+	}
+	return followers, 0
+}
+
+func (f *fakeSocialRepo) EditPost(postID, userID int, texto string) (time.Time, error) {
+	f.edits = append(f.edits, texto)
+	return time.Now(), nil
+}
+
+func (f *fakeSocialRepo) PostRevisions(postID int) ([]models.PostRevision, error) {
+	return f.revisions[postID], nil
+}
+
+// passFilter allows everything unchanged.
+type passFilter struct{}
+
+func (passFilter) Check(in FilterInput) (FilterResult, error) {
+	return FilterResult{Action: FilterAllow, Text: in.Text}, nil
+}
+
+func (passFilter) Hold(string, int, int, FilterResult) {}
+
+// fakeAuthRepo resolves usernames from users and knows who is suspended.
+type fakeAuthRepo struct {
+	repository.AuthRepository
+
+	users     map[string]models.User
+	suspended map[int]time.Time
+}
+
+func (f *fakeAuthRepo) SuspendedUntil(userID int) *time.Time {
+	if until, ok := f.suspended[userID]; ok {
+		return &until
+	}
+	return nil
+}
+
+func (f *fakeAuthRepo) GetUserByUsername(username string) (models.User, string, error) {
+	u, ok := f.users[username]
+	if !ok {
+		return models.User{}, "", sql.ErrNoRows
+	}
+	return u, "", nil
+}
+
+// fakeNotifRepo records the notifications created, as "kind:userID".
+type fakeNotifRepo struct {
+	repository.NotificationRepository
+
+	created []string
+}
+
+func (f *fakeNotifRepo) CreateNotification(userID int, actorID *int, kind string, postID *int) error {
+	f.created = append(f.created, fmt.Sprintf("%s:%d", kind, userID))
+	return nil
+}
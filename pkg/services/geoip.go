@@ -0,0 +1,109 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	geoCacheTTL      = 24 * time.Hour
+	geoLookupTimeout = 2 * time.Second
+)
+
+type geoEntry struct {
+	Location  string
+	ExpiresAt time.Time
+}
+
+// geoLocator resolves an approximate "Cidade, Estado, País" for an IP.
+// It is opt-in: without GEOIP_URL (an https:// URL with %s for the IP, in the
+// ip-api.com response format) no IP leaves the server. Lookups happen at
+// login, are stored on the session and kept in memory for repeat logins.
+type geoLocator struct {
+	endpoint string
+	client   *http.Client
+
+	mu    sync.RWMutex
+	cache map[string]geoEntry
+}
+
+func newGeoLocator() *geoLocator {
+	endpoint := strings.TrimSpace(os.Getenv("GEOIP_URL"))
+	if strings.EqualFold(endpoint, "off") {
+		endpoint = ""
+	}
+	if endpoint != "" && !strings.HasPrefix(endpoint, "https://") {
+		log.Println("[AUTH] ⚠  GEOIP_URL precisa ser https:// – localização desativada")
+		endpoint = ""
+	}
+	return &geoLocator{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: geoLookupTimeout},
+		cache:    make(map[string]geoEntry),
+	}
+}
+
+// Locate never fails: unknown or unresolvable IPs return "".
+func (g *geoLocator) Locate(ip string) string {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return ""
+	}
+	if parsed.IsLoopback() || parsed.IsPrivate() || parsed.IsLinkLocalUnicast() {
+		return "Rede local"
+	}
+	if g.endpoint == "" {
+		return ""
+	}
+
+	g.mu.RLock()
+	entry, ok := g.cache[ip]
+	g.mu.RUnlock()
+	if ok && time.Now().Before(entry.ExpiresAt) {
+		return entry.Location
+	}
+
+	location := g.lookup(ip)
+
+	g.mu.Lock()
+	if len(g.cache) > 5000 {
+		g.cache = make(map[string]geoEntry)
+	}
+	g.cache[ip] = geoEntry{Location: location, ExpiresAt: time.Now().Add(geoCacheTTL)}
+	g.mu.Unlock()
+
+	return location
+}
+
+func (g *geoLocator) lookup(ip string) string {
+	resp, err := g.client.Get(fmt.Sprintf(g.endpoint, ip))
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status     string `json:"status"`
+		City       string `json:"city"`
+		RegionName string `json:"regionName"`
+		Country    string `json:"country"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Status != "success" {
+		return ""
+	}
+
+	parts := []string{}
+	for _, p := range []string{body.City, body.RegionName, body.Country} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,30 @@
+package services
+
+import "testing"
+
+func TestGeoLocatorIsOptInAndHTTPSOnly(t *testing.T) {
+	cases := map[string]string{
+		"":                                "",
+		"off":                             "",
+		"http://ip-api.com/json/%s":       "",
+		"https://geo.example/json/%s":     "https://geo.example/json/%s",
+		"  https://geo.example/json/%s  ": "https://geo.example/json/%s",
+	}
+	for env, want := range cases {
+		t.Setenv("GEOIP_URL", env)
+		if got := newGeoLocator().endpoint; got != want {
+			t.Errorf("GEOIP_URL=%q: endpoint %q, esperado %q", env, got, want)
+		}
+	}
+}
+
+func TestGeoLocatorOffNeverLooksUp(t *testing.T) {
+	t.Setenv("GEOIP_URL", "")
+	g := newGeoLocator()
+	if got := g.Locate("8.8.8.8"); got != "" {
+		t.Errorf("sem GEOIP_URL não deveria resolver, obteve %q", got)
+	}
+	if got := g.Locate("192.168.0.10"); got != "Rede local" {
+		t.Errorf("IP privado: obteve %q", got)
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	maxTagsPerPost = 10
+	maxTagLen      = 50
+
+	// Trending counts live in hourly sorted sets (social:tags:h:{unix hour})
+	// and are summed over the last trendingWindow with exponential decay.
+	trendingBucket   = time.Hour
+	trendingWindow   = 24
+	trendingHalfLife = 6 * time.Hour
+)
+
+// A hashtag starts after whitespace/punctuation (so "a#b" and "&#39;" are not
+// tags) and runs over letters, digits and underscores.
+var hashtagRe = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&/])#([\p{L}\p{N}_]+)`)
+
+// extractHashtags returns the distinct, lowercased hashtags of a post in
+// order of appearance. Purely numeric tags ("#1") and over-long ones are ignored.
+func extractHashtags(texto string) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, m := range hashtagRe.FindAllStringSubmatch(texto, -1) {
+		tag := strings.ToLower(m[1])
+		if seen[tag] || len([]rune(tag)) > maxTagLen || !strings.ContainsFunc(tag, unicode.IsLetter) {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+		if len(tags) == maxTagsPerPost {
+			break
+		}
+	}
+	return tags
+}
+
+// normalizeTag accepts "#Calouros" or "calouros" from the URL.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func trendingBucketKey(t time.Time) string {
+	return fmt.Sprintf("social:tags:h:%d", t.Truncate(trendingBucket).Unix())
+}
+
+// trendingKeys returns the bucket keys of the window ending at now with the
+// decay weight of each: 0.5^(age/halfLife), age measured from the bucket middle.
+func trendingKeys(now time.Time) ([]string, []float64) {
+	keys := make([]string, trendingWindow)
+	weights := make([]float64, trendingWindow)
+	for i := range trendingWindow {
+		start := now.Truncate(trendingBucket).Add(-time.Duration(i) * trendingBucket)
+		age := now.Sub(start.Add(trendingBucket / 2))
+		if age < 0 {
+			age = 0
+		}
+		keys[i] = trendingBucketKey(start)
+		weights[i] = math.Pow(0.5, float64(age)/float64(trendingHalfLife))
+	}
+	return keys, weights
+}
+
+// indexTags records the hashtags of a post in post_tags and counts them in
+// the current trending bucket. On edits, previous is the old text: its tags
+// are re-indexed but only newly added ones count towards trending.
+func (s *socialService) indexTags(postID int, texto, previous string) {
+	tags := extractHashtags(texto)
+	old := extractHashtags(previous)
+	if len(tags) == 0 && len(old) == 0 {
+		return
+	}
+	if err := s.repo.SetTags(postID, tags); err != nil {
+		return
+	}
+
+	counted := map[string]bool{}
+	for _, t := range old {
+		counted[t] = true
+		s.redis.DelPattern(fmt.Sprintf("social:tag:%s:*", t))
+	}
+	bucket := trendingBucketKey(time.Now())
+	for _, t := range tags {
+		if !counted[t] {
+			s.redis.ZIncrBy(bucket, t, 1, trendingWindow*trendingBucket+trendingBucket)
+		}
+		s.redis.DelPattern(fmt.Sprintf("social:tag:%s:*", t))
+	}
+	s.redis.DelPattern("social:trending:*")
+}
+
+func (s *socialService) TagTimeline(tag string, limit int, cursor string, userID int) (models.Page[models.Post], error) {
+	tag = normalizeTag(tag)
+	if tag == "" || len([]rune(tag)) > maxTagLen {
+		return models.Page[models.Post]{}, apperror.Validation("hashtag inválida")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 30
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.Validation("cursor inválido")
+	}
+
+	pageKey := cursor
+	if pageKey == "" {
+		pageKey = "head"
+	}
+	cacheKey := fmt.Sprintf("social:tag:%s:%d:%s:lid%d", tag, limit, pageKey, userID)
+	var cached models.Page[models.Post]
+	if s.redis.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	posts, err := s.repo.TagPosts(tag, userID, limit+1, after)
+	if err != nil {
+		return models.Page[models.Post]{}, err
+	}
+	page := models.NewPage(posts, limit, models.PostCursor)
+	s.hydratePosts(page.Items, userID)
+
+	s.redis.Set(cacheKey, page, 15*time.Second)
+	return page, nil
+}
+
+func (s *socialService) Trending(limit int) []models.TrendingTag {
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	cacheKey := fmt.Sprintf("social:trending:%d", limit)
+	var cached []models.TrendingTag
+	if s.redis.Get(cacheKey, &cached) {
+		return cached
+	}
+
+	keys, weights := trendingKeys(time.Now())
+	trending := []models.TrendingTag{}
+	for _, m := range s.redis.ZUnionTop(keys, weights, limit) {
+		trending = append(trending, models.TrendingTag{Tag: m.Member, Score: math.Round(m.Score*100) / 100})
+	}
+
+	s.redis.Set(cacheKey, trending, 30*time.Second)
+	return trending
+}
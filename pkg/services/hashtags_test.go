@@ -0,0 +1,60 @@
+package services
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExtractHashtags(t *testing.T) {
+	cases := []struct {
+		texto string
+		want  []string
+	}{
+		{"Bora pra #Calourada2025! #calourada2025 de novo", []string{"calourada2025"}},
+		{"#Reunião do #CA, (#festa)", []string{"reunião", "ca", "festa"}},
+		{"nada#aqui, nem &#39; nem https://x.com/a#b nem #1", nil},
+		{"#a_b #" + strings.Repeat("x", 51), []string{"a_b"}},
+	}
+	for _, tc := range cases {
+		got := extractHashtags(tc.texto)
+		if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("extractHashtags(%q) = %v, want %v", tc.texto, got, tc.want)
+		}
+	}
+}
+
+func TestExtractHashtagsCapsPerPost(t *testing.T) {
+	var b strings.Builder
+	for i := range 15 {
+		b.WriteString(" #tag" + string(rune('a'+i)))
+	}
+	if got := extractHashtags(b.String()); len(got) != maxTagsPerPost {
+		t.Fatalf("got %d tags, want %d", len(got), maxTagsPerPost)
+	}
+}
+
+func TestTrendingKeysDecay(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 30, 0, 0, time.UTC)
+	keys, weights := trendingKeys(now)
+
+	if len(keys) != trendingWindow || len(weights) != trendingWindow {
+		t.Fatalf("got %d keys / %d weights", len(keys), len(weights))
+	}
+	if keys[0] != trendingBucketKey(now) {
+		t.Fatalf("first key %q should be the current bucket", keys[0])
+	}
+	if weights[0] != 1 {
+		t.Fatalf("current bucket weight = %v, want 1", weights[0])
+	}
+	// 14:00 bucket middle is 14:30 (age 0); the 8:00 bucket is 6h older.
+	if math.Abs(weights[6]-0.5) > 1e-9 {
+		t.Fatalf("weight after one half-life = %v, want 0.5", weights[6])
+	}
+	for i := 1; i < len(weights); i++ {
+		if weights[i] >= weights[i-1] {
+			t.Fatalf("weights must decrease: %v", weights)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"cacc/pkg/cache"
+	"cacc/pkg/models"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	previewTimeout     = 5 * time.Second
+	previewMaxBody     = 512 * 1024 // só o <head> interessa
+	previewMaxRedirect = 3
+	previewTTL         = 7 * 24 * time.Hour
+	previewMissTTL     = time.Hour // páginas sem metadados ou que falharam
+	previewUserAgent   = "CACCBot/1.0 (+link preview)"
+)
+
+// LinkPreviewService unfurls URLs into OpenGraph/Twitter-card previews,
+// cached in Redis per URL.
+type LinkPreviewService interface {
+	// Cached returns the stored preview for rawURL. known is true for URLs
+	// already fetched, even when they had no preview (nil).
+	Cached(rawURL string) (preview *models.LinkPreview, known bool)
+	// Fetch downloads and caches the preview of rawURL.
+	Fetch(rawURL string) (*models.LinkPreview, error)
+}
+
+type linkPreviewService struct {
+	client *http.Client
+	redis  *cache.Redis
+}
+
+func NewLinkPreviewService(redis *cache.Redis) LinkPreviewService {
+	return &linkPreviewService{client: newPreviewClient(), redis: redis}
+}
+
+// newPreviewClient only dials public addresses. The check runs on the IP
+// actually being connected to, so DNS rebinding and redirects to internal
+// hosts are refused too.
+func newPreviewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: previewTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if port != "80" && port != "443" {
+				return fmt.Errorf("porta não permitida: %s", port)
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("endereço não permitido: %s", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: previewTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   previewTimeout,
+			ResponseHeaderTimeout: previewTimeout,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= previewMaxRedirect {
+				return errors.New("redirecionamentos demais")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return errors.New("redirecionamento para esquema não permitido")
+			}
+			return nil
+		},
+	}
+}
+
+var blockedNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{
+		"0.0.0.0/8",     // "esta" rede
+		"100.64.0.0/10", // CGNAT
+		"192.0.0.0/24",  // IETF
+		"198.18.0.0/15", // benchmarks
+		"240.0.0.0/4",   // reservado
+		"64:ff9b::/96",  // NAT64 pode apontar para IPv4 interno
+	} {
+		_, n, _ := net.ParseCIDR(cidr)
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func previewKey(rawURL string) string {
+	sum := sha1.Sum([]byte(rawURL))
+	return "linkpreview:" + hex.EncodeToString(sum[:])
+}
+
+func (s *linkPreviewService) Cached(rawURL string) (*models.LinkPreview, bool) {
+	var p models.LinkPreview
+	if !s.redis.Get(previewKey(rawURL), &p) {
+		return nil, false
+	}
+	if p.Title == "" {
+		return nil, true
+	}
+	return &p, true
+}
+
+func (s *linkPreviewService) Fetch(rawURL string) (*models.LinkPreview, error) {
+	if p, known := s.Cached(rawURL); known {
+		return p, nil
+	}
+
+	p, err := s.fetch(rawURL)
+	if err != nil || p == nil {
+		// Negative entry: don't hammer a broken or metadata-less page.
+		s.redis.Set(previewKey(rawURL), models.LinkPreview{URL: rawURL}, previewMissTTL)
+		return nil, err
+	}
+	s.redis.Set(previewKey(rawURL), p, previewTTL)
+	return p, nil
+}
+
+func (s *linkPreviewService) fetch(rawURL string) (*models.LinkPreview, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url inválida: %q", rawURL)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), previewTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", previewUserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if mt, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mt != "text/html" && mt != "application/xhtml+xml" {
+		return nil, nil
+	}
+
+	p := parsePreview(io.LimitReader(resp.Body, previewMaxBody), resp.Request.URL)
+	if p == nil {
+		return nil, nil
+	}
+	p.URL = rawURL
+	return p, nil
+}
+
+// parsePreview reads OpenGraph (og:*), Twitter card (twitter:*) and plain
+// <title>/<meta name="description"> tags from an HTML document, stopping at
+// <body>. base resolves relative image URLs. Returns nil without a title.
+func parsePreview(r io.Reader, base *url.URL) *models.LinkPreview {
+	meta := map[string]string{}
+	var title strings.Builder
+	inTitle := false
+
+	z := html.NewTokenizer(r)
+loop:
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "body":
+				break loop
+			case "title":
+				inTitle = title.Len() == 0
+			case "meta":
+				var key, content string
+				for hasAttr {
+					var k, v []byte
+					k, v, hasAttr = z.TagAttr()
+					switch string(k) {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(string(v)))
+					case "content":
+						content = strings.TrimSpace(string(v))
+					}
+				}
+				if key != "" && content != "" {
+					if _, seen := meta[key]; !seen {
+						meta[key] = content
+					}
+				}
+			}
+		case html.TextToken:
+			if inTitle {
+				title.Write(z.Text())
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "title" {
+				inTitle = false
+			}
+		}
+	}
+
+	first := func(keys ...string) string {
+		for _, k := range keys {
+			if v := meta[k]; v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	p := &models.LinkPreview{
+		Title:       clip(first("og:title", "twitter:title"), 200),
+		Description: clip(first("og:description", "twitter:description", "description"), 300),
+		SiteName:    clip(first("og:site_name"), 100),
+	}
+	if p.Title == "" {
+		p.Title = clip(strings.Join(strings.Fields(title.String()), " "), 200)
+	}
+	if p.Title == "" {
+		return nil
+	}
+	if img := first("og:image:secure_url", "og:image", "twitter:image", "twitter:image:src"); img != "" {
+		if ref, err := url.Parse(img); err == nil {
+			if abs := base.ResolveReference(ref); abs.Scheme == "http" || abs.Scheme == "https" {
+				p.ImageURL = abs.String()
+			}
+		}
+	}
+	if p.SiteName == "" {
+		p.SiteName = strings.TrimPrefix(base.Hostname(), "www.")
+	}
+	return p
+}
+
+func clip(s string, max int) string {
+	s = strings.TrimSpace(s)
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:max-1])) + "…"
+}
+
+var previewURLRe = regexp.MustCompile(`https?://[^\s<>"]+`)
+
+// firstURL returns the first http(s) link in texto, without trailing
+// punctuation, or "".
+func firstURL(texto string) string {
+	m := previewURLRe.FindString(texto)
+	m = strings.TrimRight(m, ".,;:!?)]}'\"")
+	if _, err := url.ParseRequestURI(m); err != nil {
+		return ""
+	}
+	return m
+}
+
+// attachPreviews fills Preview from the Redis cache for posts (and their
+// loaded replies) whose first link was already unfurled. Reads never fetch:
+// only ResolvePreview does, when a post is written.
+func (s *socialService) attachPreviews(posts []models.Post) {
+	for i := range posts {
+		if u := firstURL(posts[i].Texto); u != "" {
+			posts[i].Preview, _ = s.previews.Cached(u)
+		}
+		s.attachPreviews(posts[i].Replies)
+	}
+}
+
+func (s *socialService) ResolvePreview(postID, authorID int, texto string) (*models.LinkPreview, error) {
+	u := firstURL(texto)
+	if u == "" {
+		return nil, nil
+	}
+	if p, known := s.previews.Cached(u); known {
+		return p, nil
+	}
+	p, err := s.previews.Fetch(u)
+	if p == nil {
+		return nil, err
+	}
+
+	// Threads and profile pages cached before the preview resolved don't
+	// have it; feed pages live 15s and the client gets post_preview_ready.
+	s.evictThreads(postID)
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", authorID))
+	return p, nil
+}
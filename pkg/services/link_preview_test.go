@@ -0,0 +1,111 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParsePreview(t *testing.T) {
+	base, _ := url.Parse("https://www.exemplo.com.br/eventos/semana")
+	doc := `<!doctype html><html><head>
+		<title>Título da aba</title>
+		<meta property="og:title" content="Semana Acadêmica &amp; Calourada">
+		<meta name="twitter:title" content="ignorado">
+		<meta name="description" content="Programação completa">
+		<meta property="og:image" content="/img/capa.png">
+		</head><body><meta property="og:title" content="depois do body"></body></html>`
+
+	p := parsePreview(strings.NewReader(doc), base)
+	if p == nil {
+		t.Fatal("expected a preview")
+	}
+	if p.Title != "Semana Acadêmica & Calourada" {
+		t.Errorf("title = %q", p.Title)
+	}
+	if p.Description != "Programação completa" {
+		t.Errorf("description = %q", p.Description)
+	}
+	if p.ImageURL != "https://www.exemplo.com.br/img/capa.png" {
+		t.Errorf("image = %q", p.ImageURL)
+	}
+	if p.SiteName != "exemplo.com.br" {
+		t.Errorf("site = %q", p.SiteName)
+	}
+}
+
+func TestParsePreviewFallsBackToTitleTag(t *testing.T) {
+	base, _ := url.Parse("https://forms.example.org/x")
+	p := parsePreview(strings.NewReader("<html><head><title>\n  Formulário   de inscrição </title></head>"), base)
+	if p == nil || p.Title != "Formulário de inscrição" {
+		t.Fatalf("got %+v", p)
+	}
+	if p := parsePreview(strings.NewReader("<html><body>sem head</body></html>"), base); p != nil {
+		t.Fatalf("page without title should have no preview, got %+v", p)
+	}
+}
+
+func TestFirstURL(t *testing.T) {
+	cases := map[string]string{
+		"inscrições em https://forms.gle/abc123.":             "https://forms.gle/abc123",
+		"(repo: https://github.com/cacc/portal) e http://x.y": "https://github.com/cacc/portal",
+		"sem link, só exemplo.com":                            "",
+	}
+	for in, want := range cases {
+		if got := firstURL(in); got != want {
+			t.Errorf("firstURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.1.2.3", "172.16.0.1", "192.168.0.10", "169.254.169.254", "100.64.0.1", "0.0.0.0", "::1", "fd00::1", "fe80::1"} {
+		if isPublicIP(net.ParseIP(ip)) {
+			t.Errorf("%s must be blocked", ip)
+		}
+	}
+	for _, ip := range []string{"8.8.8.8", "150.162.2.10", "2606:4700::1111"} {
+		if !isPublicIP(net.ParseIP(ip)) {
+			t.Errorf("%s should be allowed", ip)
+		}
+	}
+}
+
+// fakePreviews serves stored previews and counts fetches.
+type fakePreviews struct {
+	stored  map[string]*models.LinkPreview
+	fetched []string
+}
+
+func (f *fakePreviews) Cached(rawURL string) (*models.LinkPreview, bool) {
+	p, ok := f.stored[rawURL]
+	return p, ok
+}
+
+func (f *fakePreviews) Fetch(rawURL string) (*models.LinkPreview, error) {
+	f.fetched = append(f.fetched, rawURL)
+	return &models.LinkPreview{URL: rawURL, Title: "novo"}, nil
+}
+
+func TestAttachPreviewsNeverFetches(t *testing.T) {
+	previews := &fakePreviews{stored: map[string]*models.LinkPreview{
+		"https://a.com": {URL: "https://a.com", Title: "A"},
+	}}
+	s := &socialService{previews: previews}
+	posts := []models.Post{
+		{ID: 1, Texto: "veja https://a.com", Replies: []models.Post{{ID: 2, Texto: "e https://b.com"}}},
+	}
+
+	s.attachPreviews(posts)
+	if posts[0].Preview == nil || posts[0].Preview.Title != "A" {
+		t.Errorf("prévia guardada não foi anexada: %v", posts[0].Preview)
+	}
+	if posts[0].Replies[0].Preview != nil {
+		t.Errorf("link sem prévia guardada deveria ficar sem prévia: %v", posts[0].Replies[0].Preview)
+	}
+	if len(previews.fetched) != 0 {
+		t.Errorf("leitura não deveria buscar prévias, buscou %v", previews.fetched)
+	}
+}
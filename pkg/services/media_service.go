@@ -0,0 +1,214 @@
+package services
+
+import (
+	"bytes"
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Media kinds accepted as post attachments.
+const (
+	MediaImage = "image"
+	MediaGIF   = "gif"
+	MediaPDF   = "pdf"
+)
+
+// mediaLimits is the max size per kind. GIFs are kept short on purpose.
+var mediaLimits = map[string]int64{
+	MediaImage: 10 * 1024 * 1024,
+	MediaGIF:   8 * 1024 * 1024,
+	MediaPDF:   15 * 1024 * 1024,
+}
+
+// MaxMediaSize is the largest size any kind accepts. Handlers check it
+// before reading a file; the limit for its kind is checked once it is read.
+func MaxMediaSize() int64 {
+	var largest int64
+	for _, n := range mediaLimits {
+		largest = max(largest, n)
+	}
+	return largest
+}
+
+// MediaFile is an upload received by a handler, before it reaches Cloudinary.
+type MediaFile struct {
+	Data     []byte
+	FileName string
+	AltText  string
+}
+
+// MediaService stores files on Cloudinary. It is shared by the gallery and
+// post attachments so both go through the same signing and upload code.
+type MediaService interface {
+	Upload(data []byte, fileName, folder, resourceType string) (models.Attachment, error)
+	Destroy(publicID, resourceType string)
+}
+
+type cloudinaryMedia struct {
+	client *http.Client
+}
+
+func NewMediaService() MediaService {
+	return &cloudinaryMedia{client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+func cloudinaryConfig() (cloudName, apiKey, apiSecret string, ok bool) {
+	cloudName = os.Getenv("CLOUDINARY_CLOUD_NAME")
+	apiKey = os.Getenv("CLOUDINARY_API_KEY")
+	apiSecret = os.Getenv("CLOUDINARY_API_SECRET")
+	return cloudName, apiKey, apiSecret, cloudName != "" && apiKey != "" && apiSecret != ""
+}
+
+// Upload sends data to Cloudinary under folder. resourceType is "image" for
+// pictures/GIFs and "raw" for documents.
+func (m *cloudinaryMedia) Upload(data []byte, fileName, folder, resourceType string) (models.Attachment, error) {
+	cloudName, apiKey, apiSecret, ok := cloudinaryConfig()
+	if !ok {
+		return models.Attachment{}, fmt.Errorf("cloudinary não configurado: defina CLOUDINARY_CLOUD_NAME, CLOUDINARY_API_KEY e CLOUDINARY_API_SECRET")
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	params := map[string]string{
+		"folder":    folder,
+		"timestamp": timestamp,
+	}
+	sig := cloudinarySign(params, apiSecret)
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	if fw, err := mw.CreateFormFile("file", fileName); err == nil {
+		fw.Write(data)
+	}
+	mw.WriteField("api_key", apiKey)
+	mw.WriteField("timestamp", timestamp)
+	mw.WriteField("folder", folder)
+	mw.WriteField("signature", sig)
+	mw.Close()
+
+	uploadURL := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/upload", cloudName, resourceType)
+	req, err := http.NewRequest("POST", uploadURL, &buf)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("erro ao criar request cloudinary: %w", err)
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("erro ao fazer upload: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 {
+		return models.Attachment{}, fmt.Errorf("cloudinary retornou status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var cloudResp struct {
+		SecureURL string `json:"secure_url"`
+		PublicID  string `json:"public_id"`
+		Width     int    `json:"width"`
+		Height    int    `json:"height"`
+		Bytes     int64  `json:"bytes"`
+	}
+	if err := json.Unmarshal(body, &cloudResp); err != nil {
+		return models.Attachment{}, fmt.Errorf("erro ao parsear resposta cloudinary: %w", err)
+	}
+
+	return models.Attachment{
+		URL:          cloudResp.SecureURL,
+		PublicID:     cloudResp.PublicID,
+		ResourceType: resourceType,
+		FileName:     fileName,
+		Width:        cloudResp.Width,
+		Height:       cloudResp.Height,
+		Size:         cloudResp.Bytes,
+	}, nil
+}
+
+// Destroy removes a file from Cloudinary (best-effort).
+func (m *cloudinaryMedia) Destroy(publicID, resourceType string) {
+	cloudName, apiKey, apiSecret, ok := cloudinaryConfig()
+	if publicID == "" || !ok {
+		return
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	params := map[string]string{
+		"public_id": publicID,
+		"timestamp": timestamp,
+	}
+	sig := cloudinarySign(params, apiSecret)
+
+	data := url.Values{}
+	data.Set("public_id", publicID)
+	data.Set("api_key", apiKey)
+	data.Set("timestamp", timestamp)
+	data.Set("signature", sig)
+
+	destroyURL := fmt.Sprintf("https://api.cloudinary.com/v1_1/%s/%s/destroy", cloudName, resourceType)
+	resp, err := m.client.Post(destroyURL, "application/x-www-form-urlencoded", strings.NewReader(data.Encode()))
+	if err == nil {
+		resp.Body.Close()
+	}
+}
+
+// detectMediaKind checks both the extension and the sniffed content so a
+// renamed executable cannot pass as a PNG. Returns the kind and the Cloudinary
+// resource type.
+func detectMediaKind(data []byte, fileName string) (kind, resourceType string, err error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	sniffed := http.DetectContentType(data)
+
+	switch {
+	case ext == ".gif" && sniffed == "image/gif":
+		kind, resourceType = MediaGIF, "image"
+	case (ext == ".jpg" || ext == ".jpeg") && sniffed == "image/jpeg",
+		ext == ".png" && sniffed == "image/png",
+		ext == ".webp" && sniffed == "image/webp":
+		kind, resourceType = MediaImage, "image"
+	case ext == ".pdf" && sniffed == "application/pdf":
+		kind, resourceType = MediaPDF, "raw"
+	default:
+		return "", "", apperror.Validation(fmt.Sprintf("arquivo não suportado: %s. Use jpg, png, webp, gif ou pdf", filepath.Base(fileName)))
+	}
+
+	if int64(len(data)) > mediaLimits[kind] {
+		return "", "", apperror.Validation(fmt.Sprintf("%s muito grande (máx %d MB)", filepath.Base(fileName), mediaLimits[kind]/(1024*1024)))
+	}
+	return kind, resourceType, nil
+}
+
+// cloudinarySign gera assinatura SHA1 para requisições autenticadas da Cloudinary API.
+// Protocolo: SHA1(param1=v1&param2=v2...{api_secret})  — NÃO é HMAC.
+func cloudinarySign(params map[string]string, secret string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(params))
+	for _, k := range keys {
+		parts = append(parts, k+"="+params[k])
+	}
+	toSign := strings.Join(parts, "&") + secret
+
+	h := sha1.New()
+	h.Write([]byte(toSign))
+	return hex.EncodeToString(h.Sum(nil))
+}
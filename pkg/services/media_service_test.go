@@ -0,0 +1,47 @@
+package services
+
+import (
+	"bytes"
+	"testing"
+)
+
+var (
+	pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	gifHeader = []byte("GIF89a\x01\x00\x01\x00")
+	pdfHeader = []byte("%PDF-1.7\n")
+)
+
+func TestDetectMediaKind(t *testing.T) {
+	cases := []struct {
+		data         []byte
+		name         string
+		kind, rtype  string
+		wantRejected bool
+	}{
+		{pngHeader, "foto.PNG", MediaImage, "image", false},
+		{gifHeader, "meme.gif", MediaGIF, "image", false},
+		{pdfHeader, "edital.pdf", MediaPDF, "raw", false},
+		{pngHeader, "foto.gif", "", "", true},     // extensão não bate com o conteúdo
+		{pdfHeader, "script.exe", "", "", true},   // extensão não permitida
+		{[]byte("<html>"), "x.pdf", "", "", true}, // conteúdo disfarçado
+	}
+	for _, tc := range cases {
+		kind, rtype, err := detectMediaKind(tc.data, tc.name)
+		if tc.wantRejected {
+			if err == nil {
+				t.Errorf("detectMediaKind(%q) accepted a mismatched file", tc.name)
+			}
+			continue
+		}
+		if err != nil || kind != tc.kind || rtype != tc.rtype {
+			t.Errorf("detectMediaKind(%q) = %q, %q, %v; want %q, %q", tc.name, kind, rtype, err, tc.kind, tc.rtype)
+		}
+	}
+}
+
+func TestDetectMediaKindSizeLimit(t *testing.T) {
+	big := append(append([]byte{}, gifHeader...), bytes.Repeat([]byte{0}, int(mediaLimits[MediaGIF]))...)
+	if _, _, err := detectMediaKind(big, "grande.gif"); err == nil {
+		t.Fatal("expected oversized gif to be rejected")
+	}
+}
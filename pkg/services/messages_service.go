@@ -0,0 +1,304 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxMessageLength = 2000
+	maxGroupMembers  = 8 // incluindo quem criou
+	maxGroupTitle    = 80
+)
+
+type MessagesService interface {
+	Settings(userID int) models.DMSettings
+	UpdateSettings(userID int, req models.DMSettings) (models.DMSettings, error)
+
+	// StartConversation opens a conversation with the given users. With a
+	// single username it returns the existing one-to-one conversation when
+	// there is one (created false).
+	StartConversation(userID int, req models.CreateConversationRequest) (conv models.Conversation, created bool, err error)
+	Conversations(userID, limit int, cursor string) (models.Page[models.Conversation], error)
+	Conversation(userID, conversationID int) (models.Conversation, error)
+	// Leave removes userID from a group and returns the remaining members.
+	Leave(userID, conversationID int) ([]int, error)
+
+	Messages(userID, conversationID, limit int, cursor string) (models.Page[models.Message], error)
+	// Send stores a message and returns it with the members to deliver it to.
+	Send(userID, conversationID int, texto string) (models.Message, []int, error)
+	// MarkRead moves the read receipt; the receipt is nil when nothing
+	// changed. The returned members are the ones to notify.
+	MarkRead(userID, conversationID, messageID int) (*models.ReadReceipt, []int, error)
+	// Typing returns the members who should see userID typing.
+	Typing(userID, conversationID int) ([]int, error)
+	UnreadTotal(userID int) int
+}
+
+type messagesService struct {
+	repo   repository.MessagesRepository
+	social repository.SocialRepository
+	auth   repository.AuthRepository
+}
+
+func NewMessagesService(repo repository.MessagesRepository, social repository.SocialRepository, auth repository.AuthRepository) MessagesService {
+	return &messagesService{repo: repo, social: social, auth: auth}
+}
+
+func (s *messagesService) Settings(userID int) models.DMSettings {
+	return models.DMSettings{AllowFrom: s.repo.DMPolicy(userID)}
+}
+
+func (s *messagesService) UpdateSettings(userID int, req models.DMSettings) (models.DMSettings, error) {
+	policy := strings.ToLower(strings.TrimSpace(req.AllowFrom))
+	if policy != models.DMPolicyEveryone && policy != models.DMPolicyFollowers {
+		return models.DMSettings{}, apperror.Validation("allow_from deve ser everyone ou followers")
+	}
+	if err := s.repo.SetDMPolicy(userID, policy); err != nil {
+		return models.DMSettings{}, apperror.Internal("erro interno na db")
+	}
+	return models.DMSettings{AllowFrom: policy}, nil
+}
+
+// canMessage enforces blocks and the recipient's DM policy.
+func (s *messagesService) canMessage(senderID int, recipient models.User) error {
+	if s.social.IsBlocked(senderID, recipient.ID) {
+		return apperror.Forbidden(fmt.Sprintf("você não pode enviar mensagens para @%s", recipient.Username))
+	}
+	if s.repo.DMPolicy(recipient.ID) == models.DMPolicyFollowers && !s.social.IsFollowing(senderID, recipient.ID) {
+		return apperror.Forbidden(fmt.Sprintf("@%s só recebe mensagens de quem o segue", recipient.Username))
+	}
+	return nil
+}
+
+func (s *messagesService) StartConversation(userID int, req models.CreateConversationRequest) (models.Conversation, bool, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Conversation{}, false, err
+	}
+	usernames := normalizeUsernames(req.Usernames)
+	if len(usernames) == 0 {
+		return models.Conversation{}, false, apperror.Validation("informe ao menos um usuário")
+	}
+	if len(usernames)+1 > maxGroupMembers {
+		return models.Conversation{}, false, apperror.Validation(fmt.Sprintf("grupos têm no máximo %d participantes", maxGroupMembers))
+	}
+	title := strings.TrimSpace(req.Title)
+	if utf8.RuneCountInString(title) > maxGroupTitle {
+		return models.Conversation{}, false, apperror.Validation(fmt.Sprintf("título muito longo (máx %d caracteres)", maxGroupTitle))
+	}
+
+	var memberIDs []int
+	for _, name := range usernames {
+		u, _, err := s.auth.GetUserByUsername(name)
+		if err != nil || u.ID == 0 {
+			return models.Conversation{}, false, apperror.NotFound(fmt.Sprintf("usuário @%s não encontrado", name))
+		}
+		if u.ID == userID {
+			continue
+		}
+		if err := s.canMessage(userID, u); err != nil {
+			return models.Conversation{}, false, err
+		}
+		memberIDs = append(memberIDs, u.ID)
+	}
+	if len(memberIDs) == 0 {
+		return models.Conversation{}, false, apperror.Validation("você não pode conversar só consigo mesmo")
+	}
+
+	isGroup := len(memberIDs) > 1
+	key := ""
+	if !isGroup {
+		key = directKey(userID, memberIDs[0])
+		title = ""
+	}
+	id, created, err := s.repo.CreateConversation(userID, memberIDs, isGroup, title, key)
+	if err != nil {
+		return models.Conversation{}, false, apperror.Internal("erro interno na db")
+	}
+	conv, err := s.repo.Conversation(id, userID)
+	return conv, created, err
+}
+
+func (s *messagesService) Conversations(userID, limit int, cursor string) (models.Page[models.Conversation], error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	cur, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Conversation]{}, apperror.Validation(err.Error())
+	}
+	list, err := s.repo.Conversations(userID, limit+1, cur)
+	if err != nil {
+		return models.Page[models.Conversation]{}, err
+	}
+	return models.NewPage(list, limit, func(c models.Conversation) models.Cursor {
+		return models.Cursor{CreatedAt: c.LastMessageAt, ID: c.ID}
+	}), nil
+}
+
+func (s *messagesService) Conversation(userID, conversationID int) (models.Conversation, error) {
+	conv, err := s.repo.Conversation(conversationID, userID)
+	if err == sql.ErrNoRows {
+		return conv, apperror.NotFound("conversa não encontrada")
+	}
+	return conv, err
+}
+
+// members returns the members of a conversation userID belongs to.
+func (s *messagesService) members(userID, conversationID int) ([]int, error) {
+	ids, err := s.repo.MemberIDs(conversationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if id == userID {
+			return ids, nil
+		}
+	}
+	return nil, apperror.NotFound("conversa não encontrada")
+}
+
+func (s *messagesService) Leave(userID, conversationID int) ([]int, error) {
+	conv, err := s.Conversation(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !conv.IsGroup {
+		return nil, apperror.Validation("só é possível sair de grupos")
+	}
+	if _, err := s.repo.Leave(conversationID, userID); err != nil {
+		return nil, apperror.Internal("erro interno na db")
+	}
+	var rest []int
+	for _, m := range conv.Members {
+		if m.ID != userID {
+			rest = append(rest, m.ID)
+		}
+	}
+	return rest, nil
+}
+
+func (s *messagesService) Messages(userID, conversationID, limit int, cursor string) (models.Page[models.Message], error) {
+	if _, err := s.members(userID, conversationID); err != nil {
+		return models.Page[models.Message]{}, err
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	cur, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Message]{}, apperror.Validation(err.Error())
+	}
+	msgs, err := s.repo.Messages(conversationID, limit+1, cur)
+	if err != nil {
+		return models.Page[models.Message]{}, err
+	}
+	return models.NewPage(msgs, limit, func(m models.Message) models.Cursor {
+		return models.Cursor{CreatedAt: m.CreatedAt, ID: m.ID}
+	}), nil
+}
+
+func (s *messagesService) Send(userID, conversationID int, texto string) (models.Message, []int, error) {
+	texto = strings.TrimSpace(texto)
+	if texto == "" {
+		return models.Message{}, nil, apperror.Validation("mensagem vazia")
+	}
+	if utf8.RuneCountInString(texto) > maxMessageLength {
+		return models.Message{}, nil, apperror.Validation(fmt.Sprintf("mensagem muito longa (máx %d caracteres)", maxMessageLength))
+	}
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Message{}, nil, err
+	}
+	conv, err := s.Conversation(userID, conversationID)
+	if err != nil {
+		return models.Message{}, nil, err
+	}
+
+	// One-to-one conversations follow the other side's current block and
+	// policy. In groups the policy was checked when it was created, but a
+	// block made since then still stops the sender.
+	for _, m := range conv.Members {
+		if m.ID == userID {
+			continue
+		}
+		if !conv.IsGroup {
+			if err := s.canMessage(userID, models.User{ID: m.ID, Username: m.Username}); err != nil {
+				return models.Message{}, nil, err
+			}
+		} else if s.social.IsBlocked(userID, m.ID) {
+			return models.Message{}, nil, apperror.Forbidden(fmt.Sprintf("há um bloqueio entre você e @%s neste grupo", m.Username))
+		}
+	}
+
+	msg, err := s.repo.CreateMessage(conversationID, userID, texto)
+	if err != nil {
+		return models.Message{}, nil, apperror.Internal("erro interno na db")
+	}
+	ids := make([]int, len(conv.Members))
+	for i, m := range conv.Members {
+		ids[i] = m.ID
+	}
+	return msg, ids, nil
+}
+
+func (s *messagesService) MarkRead(userID, conversationID, messageID int) (*models.ReadReceipt, []int, error) {
+	ids, err := s.members(userID, conversationID)
+	if err != nil {
+		return nil, nil, err
+	}
+	lastRead, changed, err := s.repo.MarkRead(conversationID, userID, max(messageID, 0))
+	if err != nil {
+		return nil, nil, apperror.Internal("erro interno na db")
+	}
+	if !changed {
+		return nil, ids, nil
+	}
+	return &models.ReadReceipt{ConversationID: conversationID, UserID: userID, MessageID: lastRead}, ids, nil
+}
+
+func (s *messagesService) Typing(userID, conversationID int) ([]int, error) {
+	ids, err := s.members(userID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	others := ids[:0]
+	for _, id := range ids {
+		if id != userID {
+			others = append(others, id)
+		}
+	}
+	return others, nil
+}
+
+func (s *messagesService) UnreadTotal(userID int) int {
+	return s.repo.UnreadTotal(userID)
+}
+
+// directKey identifies the one-to-one conversation between two users,
+// whatever the order.
+func directKey(a, b int) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%d:%d", a, b)
+}
+
+// normalizeUsernames lowercases, strips "@" and drops blanks and repeats.
+func normalizeUsernames(in []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, name := range in {
+		name = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "@"))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+)
+
+func TestDirectKeyIgnoresOrder(t *testing.T) {
+	if directKey(7, 3) != "3:7" || directKey(3, 7) != "3:7" {
+		t.Fatalf("got %q and %q, want 3:7", directKey(7, 3), directKey(3, 7))
+	}
+}
+
+func TestNormalizeUsernames(t *testing.T) {
+	got := normalizeUsernames([]string{" @Maria ", "joao", "", "MARIA", "@", "ana_b"})
+	want := []string{"maria", "joao", "ana_b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+// fakeMessagesRepo keeps conversations in memory with the same read-marker
+// rules as the SQL: sending marks the message read by its sender, and
+// unread counts messages from others past the marker.
+type fakeMessagesRepo struct {
+	repository.MessagesRepository
+
+	policies map[int]string
+	convs    map[int]*fakeConversation
+	messages []models.Message
+}
+
+type fakeConversation struct {
+	isGroup  bool
+	key      string
+	lastRead map[int]int // membro → última mensagem lida
+}
+
+func newFakeMessagesRepo() *fakeMessagesRepo {
+	return &fakeMessagesRepo{policies: map[int]string{}, convs: map[int]*fakeConversation{}}
+}
+
+func (f *fakeMessagesRepo) DMPolicy(userID int) string {
+	if p, ok := f.policies[userID]; ok {
+		return p
+	}
+	return models.DMPolicyEveryone
+}
+
+func (f *fakeMessagesRepo) CreateConversation(creatorID int, memberIDs []int, isGroup bool, title, directKey string) (int, bool, error) {
+	for id, c := range f.convs {
+		if directKey != "" && c.key == directKey {
+			return id, false, nil
+		}
+	}
+	id := len(f.convs) + 1
+	c := &fakeConversation{isGroup: isGroup, key: directKey, lastRead: map[int]int{creatorID: 0}}
+	for _, m := range memberIDs {
+		c.lastRead[m] = 0
+	}
+	f.convs[id] = c
+	return id, true, nil
+}
+
+func (f *fakeMessagesRepo) Conversation(id, userID int) (models.Conversation, error) {
+	c, ok := f.convs[id]
+	if !ok {
+		return models.Conversation{}, sql.ErrNoRows
+	}
+	if _, member := c.lastRead[userID]; !member {
+		return models.Conversation{}, sql.ErrNoRows
+	}
+	conv := models.Conversation{ID: id, IsGroup: c.isGroup}
+	for _, m := range f.memberIDs(id) {
+		conv.Members = append(conv.Members, models.ConversationMember{ID: m, Username: fmt.Sprintf("u%d", m), LastReadID: c.lastRead[m]})
+	}
+	return conv, nil
+}
+
+func (f *fakeMessagesRepo) memberIDs(id int) []int {
+	var ids []int
+	for m := range f.convs[id].lastRead {
+		ids = append(ids, m)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (f *fakeMessagesRepo) MemberIDs(conversationID int) ([]int, error) {
+	if _, ok := f.convs[conversationID]; !ok {
+		return nil, nil
+	}
+	return f.memberIDs(conversationID), nil
+}
+
+func (f *fakeMessagesRepo) Leave(conversationID, userID int) (bool, error) {
+	c := f.convs[conversationID]
+	if _, member := c.lastRead[userID]; !member {
+		return false, nil
+	}
+	delete(c.lastRead, userID)
+	return true, nil
+}
+
+func (f *fakeMessagesRepo) CreateMessage(conversationID, senderID int, texto string) (models.Message, error) {
+	m := models.Message{ID: len(f.messages) + 1, ConversationID: conversationID, SenderID: senderID, Texto: texto}
+	f.messages = append(f.messages, m)
+	f.convs[conversationID].lastRead[senderID] = m.ID
+	return m, nil
+}
+
+func (f *fakeMessagesRepo) MarkRead(conversationID, userID, messageID int) (int, bool, error) {
+	target := 0
+	for _, m := range f.messages {
+		if m.ConversationID == conversationID && (messageID == 0 || m.ID <= messageID) {
+			target = max(target, m.ID)
+		}
+	}
+	c := f.convs[conversationID]
+	if target <= c.lastRead[userID] {
+		return 0, false, nil
+	}
+	c.lastRead[userID] = target
+	return target, true, nil
+}
+
+func (f *fakeMessagesRepo) UnreadTotal(userID int) int {
+	n := 0
+	for _, m := range f.messages {
+		lastRead, member := f.convs[m.ConversationID].lastRead[userID]
+		if member && m.ID > lastRead && m.SenderID != userID {
+			n++
+		}
+	}
+	return n
+}
+
+// newTestMessages wires ana (1), bia (2), caio (3) and davi (4).
+func newTestMessages() (*messagesService, *fakeMessagesRepo, *fakeSocialRepo, *fakeAuthRepo) {
+	repo := newFakeMessagesRepo()
+	social := newFakeSocialRepo()
+	auth := &fakeAuthRepo{users: map[string]models.User{}, suspended: map[int]time.Time{}}
+	for id, name := range map[int]string{1: "ana", 2: "bia", 3: "caio", 4: "davi"} {
+		auth.users[name] = models.User{ID: id, Username: name}
+	}
+	return &messagesService{repo: repo, social: social, auth: auth}, repo, social, auth
+}
+
+func TestStartConversationChecksBlocksAndPolicy(t *testing.T) {
+	s, repo, social, auth := newTestMessages()
+	social.blocks[[2]int{2, 1}] = true
+	repo.policies[3] = models.DMPolicyFollowers
+	auth.suspended[4] = time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name      string
+		userID    int
+		usernames []string
+		code      apperror.Code
+	}{
+		{"bloqueado", 1, []string{"bia"}, apperror.ErrForbidden},
+		{"bloqueado no grupo", 1, []string{"caio", "bia"}, apperror.ErrForbidden},
+		{"só seguidores", 1, []string{"caio"}, apperror.ErrForbidden},
+		{"suspenso", 4, []string{"ana"}, apperror.ErrForbidden},
+		{"inexistente", 1, []string{"zeca"}, apperror.ErrNotFound},
+		{"consigo mesmo", 1, []string{"ana"}, apperror.ErrValidation},
+	}
+	for _, tc := range cases {
+		if _, _, err := s.StartConversation(tc.userID, models.CreateConversationRequest{Usernames: tc.usernames}); appErrorCode(err) != tc.code {
+			t.Errorf("%s: esperado %d, obteve %v", tc.name, tc.code, err)
+		}
+	}
+
+	social.follows[[2]int{1, 3}] = true
+	conv, created, err := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"caio"}})
+	if err != nil || !created {
+		t.Fatalf("seguidor deveria conversar com caio: created=%v err=%v", created, err)
+	}
+	again, created, _ := s.StartConversation(3, models.CreateConversationRequest{Usernames: []string{"ana"}})
+	if created || again.ID != conv.ID {
+		t.Errorf("a conversa 1:1 deveria ser reaproveitada: %d vs %d", again.ID, conv.ID)
+	}
+}
+
+func TestSendRechecksBlocks(t *testing.T) {
+	s, _, social, _ := newTestMessages()
+	direct, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia"}})
+	group, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia", "caio"}})
+
+	if _, to, err := s.Send(1, group.ID, "oi"); err != nil || len(to) != 3 {
+		t.Fatalf("envio ao grupo: to=%v err=%v", to, err)
+	}
+
+	// Um bloqueio feito depois da criação vale nas duas conversas.
+	social.blocks[[2]int{2, 1}] = true
+	if _, _, err := s.Send(1, direct.ID, "oi"); appErrorCode(err) != apperror.ErrForbidden {
+		t.Errorf("1:1 com bloqueio: esperado 403, obteve %v", err)
+	}
+	if _, _, err := s.Send(1, group.ID, "oi"); appErrorCode(err) != apperror.ErrForbidden {
+		t.Errorf("grupo com bloqueio: esperado 403, obteve %v", err)
+	}
+	if _, _, err := s.Send(3, group.ID, "oi"); err != nil {
+		t.Errorf("quem não bloqueou segue falando no grupo: %v", err)
+	}
+	if _, _, err := s.Send(4, group.ID, "oi"); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("quem não é membro: esperado 404, obteve %v", err)
+	}
+}
+
+func TestLeave(t *testing.T) {
+	s, _, _, _ := newTestMessages()
+	direct, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia"}})
+	group, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia", "caio"}})
+
+	if _, err := s.Leave(1, direct.ID); appErrorCode(err) != apperror.ErrValidation {
+		t.Errorf("sair de conversa 1:1: esperado 400, obteve %v", err)
+	}
+	rest, err := s.Leave(2, group.ID)
+	if err != nil || !reflect.DeepEqual(rest, []int{1, 3}) {
+		t.Fatalf("Leave: rest=%v err=%v", rest, err)
+	}
+	if _, err := s.Leave(2, group.ID); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("sair de novo: esperado 404, obteve %v", err)
+	}
+	if _, _, err := s.Send(2, group.ID, "voltei?"); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("quem saiu não envia: esperado 404, obteve %v", err)
+	}
+}
+
+func TestUnreadCounting(t *testing.T) {
+	s, _, _, _ := newTestMessages()
+	direct, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia"}})
+	group, _, _ := s.StartConversation(1, models.CreateConversationRequest{Usernames: []string{"bia", "caio"}})
+
+	s.Send(1, direct.ID, "oi")
+	first, _, _ := s.Send(1, group.ID, "oi, grupo")
+	s.Send(3, group.ID, "oi!")
+	if n := s.UnreadTotal(2); n != 3 {
+		t.Errorf("bia: esperado 3 não lidas, obteve %d", n)
+	}
+	if n := s.UnreadTotal(1); n != 1 {
+		t.Errorf("ana não conta as próprias mensagens: esperado 1, obteve %d", n)
+	}
+
+	receipt, to, err := s.MarkRead(2, group.ID, first.ID)
+	if err != nil || receipt == nil || receipt.MessageID != first.ID || len(to) != 3 {
+		t.Fatalf("MarkRead parcial: receipt=%v to=%v err=%v", receipt, to, err)
+	}
+	if n := s.UnreadTotal(2); n != 2 {
+		t.Errorf("bia depois de ler até a primeira do grupo: esperado 2, obteve %d", n)
+	}
+	s.MarkRead(2, group.ID, 0)
+	if receipt, _, _ := s.MarkRead(2, group.ID, 0); receipt != nil {
+		t.Errorf("ler de novo não deveria gerar recibo: %v", receipt)
+	}
+	if n := s.UnreadTotal(2); n != 1 {
+		t.Errorf("bia com o grupo lido: esperado 1, obteve %d", n)
+	}
+	if _, _, err := s.MarkRead(4, group.ID, 0); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("MarkRead de quem não é membro: esperado 404, obteve %v", err)
+	}
+}
@@ -0,0 +1,319 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/cache"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// reportReasons are the reasons a user can pick when reporting.
+var reportReasons = map[string]bool{
+	"spam":           true,
+	"harassment":     true,
+	"hate":           true,
+	"sexual":         true,
+	"violence":       true,
+	"misinformation": true,
+	"other":          true,
+}
+
+const (
+	maxReportDetails  = 1000
+	maxSuspension     = 365 * 24 * time.Hour
+	defaultSuspension = 72 * time.Hour
+)
+
+type ModerationService interface {
+	Report(reporterID int, req models.CreateReportRequest) (models.Report, error)
+	Queue(status string, limit int, cursor string) (models.Page[models.Report], error)
+	GetReport(id int) (models.Report, error)
+	Act(moderatorID, reportID int, req models.ModerationActionRequest) (models.Report, error)
+	AuditLog(limit int, cursor string) (models.Page[models.ModerationAction], error)
+}
+
+type moderationService struct {
+	repo    repository.ModerationRepository
+	social  repository.SocialRepository
+	galeria repository.GaleriaRepository
+	notif   repository.NotificationRepository
+	media   MediaService
+	redis   *cache.Redis
+}
+
+func NewModerationService(repo repository.ModerationRepository, social repository.SocialRepository, galeria repository.GaleriaRepository, notif repository.NotificationRepository, media MediaService, redis *cache.Redis) ModerationService {
+	return &moderationService{repo: repo, social: social, galeria: galeria, notif: notif, media: media, redis: redis}
+}
+
+func (s *moderationService) Report(reporterID int, req models.CreateReportRequest) (models.Report, error) {
+	switch req.TargetType {
+	case models.ReportPost, models.ReportGaleria, models.ReportProfile:
+	default:
+		return models.Report{}, apperror.Validation("target_type deve ser post, galeria ou profile")
+	}
+	if !reportReasons[req.Reason] {
+		return models.Report{}, apperror.Validation("motivo inválido")
+	}
+	details := strings.TrimSpace(req.Details)
+	if utf8.RuneCountInString(details) > maxReportDetails {
+		return models.Report{}, apperror.Validation(fmt.Sprintf("detalhes muito longos (máx %d caracteres)", maxReportDetails))
+	}
+
+	ownerID, snapshot, err := s.repo.ReportTarget(req.TargetType, req.TargetID)
+	if err == sql.ErrNoRows || (err == nil && ownerID == 0) {
+		return models.Report{}, apperror.NotFound("conteúdo não encontrado")
+	}
+	if err != nil {
+		return models.Report{}, err
+	}
+	if ownerID == reporterID {
+		return models.Report{}, apperror.Validation("você não pode denunciar a si mesmo")
+	}
+
+	rep, created, err := s.repo.CreateReport(models.Report{
+		TargetType:     req.TargetType,
+		TargetID:       req.TargetID,
+		ReportedUserID: ownerID,
+		ReporterID:     reporterID,
+		Reason:         req.Reason,
+		Details:        details,
+		Snapshot:       snapshot,
+	})
+	if err != nil {
+		return models.Report{}, err
+	}
+	if !created {
+		return models.Report{}, apperror.Conflict("você já denunciou este conteúdo")
+	}
+	return rep, nil
+}
+
+func (s *moderationService) Queue(status string, limit int, cursor string) (models.Page[models.Report], error) {
+	if status == "" {
+		status = models.ReportOpen
+	}
+	switch status {
+	case models.ReportOpen, models.ReportActioned, models.ReportDismissed:
+	default:
+		return models.Page[models.Report]{}, apperror.Validation("status deve ser open, actioned ou dismissed")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 30
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Report]{}, apperror.Validation("cursor inválido")
+	}
+
+	reports, err := s.repo.Reports(status, limit+1, after)
+	if err != nil {
+		return models.Page[models.Report]{}, err
+	}
+	return models.NewPage(reports, limit, models.ReportCursor), nil
+}
+
+func (s *moderationService) GetReport(id int) (models.Report, error) {
+	rep, err := s.repo.GetReport(id)
+	if err == sql.ErrNoRows {
+		return models.Report{}, apperror.NotFound("denúncia não encontrada")
+	}
+	if err != nil {
+		return models.Report{}, err
+	}
+	rep.Actions, _ = s.repo.Actions(id)
+	return rep, nil
+}
+
+// Act applies a moderator decision to the content (or user) behind a report
+// and records it in the audit trail. Any action other than dismiss/unhide
+// closes every open report on the same target.
+func (s *moderationService) Act(moderatorID, reportID int, req models.ModerationActionRequest) (models.Report, error) {
+	rep, err := s.GetReport(reportID)
+	if err != nil {
+		return models.Report{}, err
+	}
+	note := strings.TrimSpace(req.Note)
+	if utf8.RuneCountInString(note) > maxReportDetails {
+		return models.Report{}, apperror.Validation(fmt.Sprintf("nota muito longa (máx %d caracteres)", maxReportDetails))
+	}
+
+	entry := models.ModerationAction{
+		ReportID:    &rep.ID,
+		ModeratorID: moderatorID,
+		Action:      req.Action,
+		TargetType:  rep.TargetType,
+		TargetID:    rep.TargetID,
+		UserID:      rep.ReportedUserID,
+		Note:        note,
+	}
+	var postID *int
+	if rep.TargetType == models.ReportPost {
+		postID = &rep.TargetID
+	}
+
+	switch req.Action {
+	case models.ModHide, models.ModUnhide:
+		if rep.TargetType == models.ReportProfile {
+			return models.Report{}, apperror.Validation("perfis não podem ser ocultados; use warn ou suspend")
+		}
+		err := s.repo.SetHidden(rep.TargetType, rep.TargetID, req.Action == models.ModHide)
+		if err == sql.ErrNoRows {
+			return models.Report{}, apperror.NotFound("o conteúdo já foi apagado")
+		}
+		if err != nil {
+			return models.Report{}, err
+		}
+		if req.Action == models.ModHide {
+			s.notif.CreateNotification(rep.ReportedUserID, nil, "content_hidden", postID)
+		}
+
+	case models.ModDelete:
+		if rep.TargetType == models.ReportProfile {
+			return models.Report{}, apperror.Validation("perfis não podem ser apagados; use suspend")
+		}
+		if err := s.deleteTarget(rep.TargetType, rep.TargetID); err != nil {
+			return models.Report{}, err
+		}
+		s.notif.CreateNotification(rep.ReportedUserID, nil, "content_removed", nil)
+		postID = nil
+
+	case models.ModWarn:
+		s.notif.CreateNotification(rep.ReportedUserID, nil, "moderation_warning", postID)
+
+	case models.ModSuspend:
+		d, err := parseSuspension(req.Duration)
+		if err != nil {
+			return models.Report{}, err
+		}
+		until := time.Now().Add(d)
+		if err := s.repo.Suspend(rep.ReportedUserID, until); err != nil {
+			return models.Report{}, err
+		}
+		entry.Until = &until
+		s.notif.CreateNotification(rep.ReportedUserID, nil, "account_suspended", nil)
+
+	case models.ModDismiss:
+		if rep.Status != models.ReportOpen {
+			return models.Report{}, apperror.Conflict("esta denúncia já foi resolvida")
+		}
+		if err := s.repo.DismissReport(rep.ID, moderatorID); err != nil {
+			return models.Report{}, err
+		}
+		if rep.ReporterID != 0 {
+			s.notif.CreateNotification(rep.ReporterID, nil, "report_dismissed", postID)
+		}
+
+	default:
+		return models.Report{}, apperror.Validation("ação deve ser hide, unhide, delete, warn, suspend ou dismiss")
+	}
+
+	if _, err := s.repo.LogAction(entry); err != nil {
+		return models.Report{}, err
+	}
+
+	if req.Action != models.ModDismiss && req.Action != models.ModUnhide {
+		reporters, _ := s.repo.ActionReports(rep.TargetType, rep.TargetID, moderatorID)
+		for _, reporterID := range reporters {
+			s.notif.CreateNotification(reporterID, nil, "report_actioned", postID)
+		}
+	}
+	if rep.TargetType == models.ReportPost {
+		s.invalidatePosts()
+	}
+
+	return s.GetReport(rep.ID)
+}
+
+// deleteTarget removes reported content and its Cloudinary files.
+func (s *moderationService) deleteTarget(targetType string, targetID int) error {
+	var files []models.Attachment
+	switch targetType {
+	case models.ReportPost:
+		files, _ = s.social.ThreadAttachments(targetID)
+	case models.ReportGaleria:
+		if item, err := s.galeria.GetByID(targetID); err == nil && item.PublicID != "" {
+			files = append(files, models.Attachment{PublicID: item.PublicID, ResourceType: "image"})
+		}
+	}
+
+	err := s.repo.DeleteTarget(targetType, targetID)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("o conteúdo já foi apagado")
+	}
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		for _, f := range files {
+			s.media.Destroy(f.PublicID, f.ResourceType)
+		}
+	}()
+	return nil
+}
+
+// invalidatePosts drops every cached view that may contain the moderated
+// post; moderation is rare enough that precision is not worth it.
+func (s *moderationService) invalidatePosts() {
+	s.redis.DelPattern("social:feed:*")
+	s.redis.DelPattern("social:thread:*")
+	s.redis.DelPattern("social:profile:*")
+	s.redis.DelPattern("social:tag:*")
+	s.redis.DelPattern("search:posts:*")
+}
+
+func (s *moderationService) AuditLog(limit int, cursor string) (models.Page[models.ModerationAction], error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.ModerationAction]{}, apperror.Validation("cursor inválido")
+	}
+
+	actions, err := s.repo.AuditLog(limit+1, after)
+	if err != nil {
+		return models.Page[models.ModerationAction]{}, err
+	}
+	return models.NewPage(actions, limit, models.ModerationActionCursor), nil
+}
+
+// parseSuspension accepts Go durations ("72h", "90m") and whole days ("7d").
+// Empty means defaultSuspension.
+func parseSuspension(v string) (time.Duration, error) {
+	d, err := parseSpan(v, defaultSuspension)
+	if err != nil {
+		return 0, err
+	}
+	if d < time.Hour || d > maxSuspension {
+		return 0, apperror.Validation("a suspensão deve durar entre 1 hora e 365 dias")
+	}
+	return d, nil
+}
+
+// parseSpan reads a Go duration or a whole number of days ("7d"); empty
+// returns def.
+func parseSpan(v string, def time.Duration) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return def, nil
+	}
+	if days, ok := strings.CutSuffix(v, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, apperror.Validation("duração inválida (ex.: 72h ou 7d)")
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, apperror.Validation("duração inválida (ex.: 72h ou 7d)")
+	}
+	return d, nil
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+)
+
+func TestParseSuspension(t *testing.T) {
+	cases := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"", defaultSuspension, true},
+		{"72h", 72 * time.Hour, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{"90m", 90 * time.Minute, true},
+		{"30m", 0, false},  // menos de 1 hora
+		{"400d", 0, false}, // mais de um ano
+		{"xd", 0, false},
+		{"semana", 0, false},
+	}
+	for _, tc := range cases {
+		got, err := parseSuspension(tc.in)
+		if (err == nil) != tc.ok || (tc.ok && got != tc.want) {
+			t.Errorf("parseSuspension(%q) = %v, %v; want %v (ok=%v)", tc.in, got, err, tc.want, tc.ok)
+		}
+	}
+}
+
+// fakeModerationRepo keeps reports in memory, open ones unique per reporter
+// and target like uq_reports_open.
+type fakeModerationRepo struct {
+	repository.ModerationRepository
+
+	owners  map[int]int // post id → autor
+	reports map[int]models.Report
+	hidden  map[int]bool
+	actions []models.ModerationAction
+}
+
+func newFakeModerationRepo() *fakeModerationRepo {
+	return &fakeModerationRepo{owners: map[int]int{}, reports: map[int]models.Report{}, hidden: map[int]bool{}}
+}
+
+func (f *fakeModerationRepo) ReportTarget(targetType string, targetID int) (int, string, error) {
+	owner, ok := f.owners[targetID]
+	if !ok {
+		return 0, "", sql.ErrNoRows
+	}
+	return owner, "snapshot", nil
+}
+
+func (f *fakeModerationRepo) CreateReport(rep models.Report) (models.Report, bool, error) {
+	for _, r := range f.reports {
+		if r.ReporterID == rep.ReporterID && r.TargetType == rep.TargetType && r.TargetID == rep.TargetID && r.Status == models.ReportOpen {
+			return rep, false, nil
+		}
+	}
+	rep.ID = len(f.reports) + 1
+	rep.Status = models.ReportOpen
+	f.reports[rep.ID] = rep
+	return rep, true, nil
+}
+
+func (f *fakeModerationRepo) GetReport(id int) (models.Report, error) {
+	rep, ok := f.reports[id]
+	if !ok {
+		return models.Report{}, sql.ErrNoRows
+	}
+	return rep, nil
+}
+
+func (f *fakeModerationRepo) Actions(reportID int) ([]models.ModerationAction, error) {
+	var out []models.ModerationAction
+	for _, a := range f.actions {
+		if a.ReportID != nil && *a.ReportID == reportID {
+			out = append(out, a)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeModerationRepo) SetHidden(targetType string, targetID int, hidden bool) error {
+	f.hidden[targetID] = hidden
+	return nil
+}
+
+func (f *fakeModerationRepo) LogAction(a models.ModerationAction) (models.ModerationAction, error) {
+	f.actions = append(f.actions, a)
+	return a, nil
+}
+
+func (f *fakeModerationRepo) ActionReports(targetType string, targetID, moderatorID int) ([]int, error) {
+	var reporters []int
+	for id, r := range f.reports {
+		if r.TargetType == targetType && r.TargetID == targetID && r.Status == models.ReportOpen {
+			r.Status = models.ReportActioned
+			f.reports[id] = r
+			reporters = append(reporters, r.ReporterID)
+		}
+	}
+	return reporters, nil
+}
+
+func (f *fakeModerationRepo) DismissReport(id, moderatorID int) error {
+	r := f.reports[id]
+	r.Status = models.ReportDismissed
+	f.reports[id] = r
+	return nil
+}
+
+func TestReportDedupe(t *testing.T) {
+	repo := newFakeModerationRepo()
+	repo.owners[1] = 10
+	s := &moderationService{repo: repo}
+	req := models.CreateReportRequest{TargetType: models.ReportPost, TargetID: 1, Reason: "spam"}
+
+	if _, err := s.Report(20, req); err != nil {
+		t.Fatalf("primeira denúncia: %v", err)
+	}
+	if _, err := s.Report(20, req); appErrorCode(err) != apperror.ErrConflict {
+		t.Errorf("denúncia repetida: esperado 409, obteve %v", err)
+	}
+	if _, err := s.Report(21, req); err != nil {
+		t.Errorf("outro denunciante: %v", err)
+	}
+	if _, err := s.Report(10, req); appErrorCode(err) != apperror.ErrValidation {
+		t.Errorf("denunciar a si mesmo: esperado 400, obteve %v", err)
+	}
+	if _, err := s.Report(20, models.CreateReportRequest{TargetType: models.ReportPost, TargetID: 99, Reason: "spam"}); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("alvo inexistente: esperado 404, obteve %v", err)
+	}
+}
+
+func TestActHideClosesReports(t *testing.T) {
+	repo := newFakeModerationRepo()
+	repo.owners[1] = 10
+	notif := &fakeNotifRepo{}
+	s := &moderationService{repo: repo, notif: notif, redis: testRedis(t)}
+	req := models.CreateReportRequest{TargetType: models.ReportPost, TargetID: 1, Reason: "spam"}
+	first, _ := s.Report(20, req)
+	s.Report(21, req)
+
+	rep, err := s.Act(99, first.ID, models.ModerationActionRequest{Action: models.ModHide, Note: "spam"})
+	if err != nil {
+		t.Fatalf("Act: %v", err)
+	}
+	if !repo.hidden[1] {
+		t.Error("post deveria ficar oculto")
+	}
+	if rep.Status != models.ReportActioned || len(rep.Actions) != 1 || rep.Actions[0].ModeratorID != 99 {
+		t.Errorf("denúncia depois da ação: %+v", rep)
+	}
+	for _, r := range repo.reports {
+		if r.Status != models.ReportActioned {
+			t.Errorf("denúncia %d ficou %s", r.ID, r.Status)
+		}
+	}
+	want := map[string]bool{"content_hidden:10": true, "report_actioned:20": true, "report_actioned:21": true}
+	if len(notif.created) != len(want) {
+		t.Errorf("notificações: %v", notif.created)
+	}
+	for _, n := range notif.created {
+		if !want[n] {
+			t.Errorf("notificação inesperada %q", n)
+		}
+	}
+
+	// Uma denúncia já resolvida não pode ser descartada.
+	if _, err := s.Act(99, first.ID, models.ModerationActionRequest{Action: models.ModDismiss}); appErrorCode(err) != apperror.ErrConflict {
+		t.Errorf("descartar denúncia resolvida: esperado 409, obteve %v", err)
+	}
+	if _, err := s.Act(99, first.ID, models.ModerationActionRequest{Action: "ban"}); appErrorCode(err) != apperror.ErrValidation {
+		t.Errorf("ação desconhecida: esperado 400, obteve %v", err)
+	}
+}
@@ -6,6 +6,10 @@ import (
 	"cacc/pkg/repository"
 	"encoding/json"
 	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
 	"time"
 )
 
@@ -16,6 +20,7 @@ type NoticiasService interface {
 	Criar(req models.CriarNoticiaRequest, username string) (models.Noticia, error)
 	Atualizar(id int, req models.AtualizarNoticiaRequest) (models.Noticia, error)
 	Deletar(id int) (bool, error)
+	ReindexarBusca()
 }
 
 type noticiasService struct {
@@ -105,20 +110,22 @@ func (s *noticiasService) Criar(req models.CriarNoticiaRequest, username string)
 	}
 
 	nova := models.Noticia{
-		Titulo:    req.Titulo,
-		Conteudo:  conteudoStr,
-		Resumo:    req.Resumo,
-		Author:    req.Author,
-		Categoria: req.Categoria,
-		ImageURL:  req.ImageURL,
-		Destaque:  req.Destaque,
-		Tags:      req.Tags,
+		Titulo:     req.Titulo,
+		Conteudo:   conteudoStr,
+		Resumo:     req.Resumo,
+		Author:     req.Author,
+		Categoria:  req.Categoria,
+		ImageURL:   req.ImageURL,
+		Destaque:   req.Destaque,
+		Tags:       req.Tags,
+		TextoBusca: textoPlano(conteudoStr),
 	}
 
 	noticia, err := s.repo.Criar(nova)
 	if err == nil {
 		parseEditorJS(&noticia)
 		s.redis.DelPattern("noticias:*")
+		s.redis.DelPattern("search:noticias:*")
 	}
 
 	return noticia, err
@@ -134,10 +141,11 @@ func (s *noticiasService) Atualizar(id int, req models.AtualizarNoticiaRequest)
 		conteudoStr = parsed
 	}
 
-	noticia, err := s.repo.Atualizar(id, req, conteudoStr)
+	noticia, err := s.repo.Atualizar(id, req, conteudoStr, textoPlano(conteudoStr))
 	if err == nil {
 		parseEditorJS(&noticia)
 		s.redis.DelPattern("noticias:*")
+		s.redis.DelPattern("search:noticias:*")
 	}
 
 	return noticia, err
@@ -147,10 +155,37 @@ func (s *noticiasService) Deletar(id int) (bool, error) {
 	deletado, err := s.repo.Deletar(id)
 	if err == nil && deletado {
 		s.redis.DelPattern("noticias:*")
+		s.redis.DelPattern("search:noticias:*")
 	}
 	return deletado, err
 }
 
+// ReindexarBusca preenche texto_busca das notícias criadas antes da busca
+// existir. Roda uma vez na subida; notícias novas já entram indexadas.
+func (s *noticiasService) ReindexarBusca() {
+	total, afterID := 0, 0
+	for {
+		lote, err := s.repo.SemTextoBusca(afterID, 100)
+		if err != nil {
+			log.Printf("[Busca] erro ao reindexar notícias: %v", err)
+			return
+		}
+		if len(lote) == 0 {
+			break
+		}
+		for _, n := range lote {
+			if err := s.repo.AtualizarTextoBusca(n.ID, textoPlano(n.Conteudo)); err == nil {
+				total++
+			}
+			afterID = n.ID
+		}
+	}
+	if total > 0 {
+		log.Printf("[Busca] %d notícias reindexadas", total)
+		s.redis.DelPattern("search:noticias:*")
+	}
+}
+
 func parseEditorJS(noticia *models.Noticia) {
 	var editorData models.EditorJSData
 	if err := json.Unmarshal([]byte(noticia.Conteudo), &editorData); err == nil {
@@ -158,6 +193,47 @@ func parseEditorJS(noticia *models.Noticia) {
 	}
 }
 
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// textoPlano extrai o texto legível de um conteúdo Editor.js (parágrafos,
+// títulos, citações e listas), sem HTML, para o índice de busca.
+func textoPlano(conteudoStr string) string {
+	var editorData models.EditorJSData
+	if err := json.Unmarshal([]byte(conteudoStr), &editorData); err != nil || editorData.Blocks == nil {
+		return limparHTML(conteudoStr)
+	}
+
+	var partes []string
+	for _, block := range editorData.Blocks {
+		data, ok := block["data"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, campo := range []string{"text", "caption"} {
+			if text, ok := data[campo].(string); ok && text != "" {
+				partes = append(partes, limparHTML(text))
+			}
+		}
+		if items, ok := data["items"].([]interface{}); ok {
+			for _, item := range items {
+				switch v := item.(type) {
+				case string:
+					partes = append(partes, limparHTML(v))
+				case map[string]interface{}:
+					if content, ok := v["content"].(string); ok {
+						partes = append(partes, limparHTML(content))
+					}
+				}
+			}
+		}
+	}
+	return strings.Join(partes, "\n")
+}
+
+func limparHTML(s string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(s, " "))), " ")
+}
+
 func gerarResumo(conteudoStr string) string {
 	var editorData models.EditorJSData
 	if err := json.Unmarshal([]byte(conteudoStr), &editorData); err == nil {
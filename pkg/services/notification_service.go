@@ -0,0 +1,112 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+)
+
+type NotificationService interface {
+	List(userID, limit int, cursor string) (models.Page[models.Notification], error)
+	UnreadCount(userID int) (int, error)
+	// MarkRead marks everything read when id is 0; with upTo it marks id and
+	// all older notifications, otherwise just id.
+	MarkRead(userID, id int, upTo bool) error
+	Preferences(userID int) ([]models.NotificationPreference, error)
+	UpdatePreferences(userID int, updates []models.NotificationPreferenceUpdate) ([]models.NotificationPreference, error)
+}
+
+type notificationService struct {
+	repo repository.NotificationRepository
+}
+
+func NewNotificationService(repo repository.NotificationRepository) NotificationService {
+	return &notificationService{repo: repo}
+}
+
+func (s *notificationService) List(userID, limit int, cursor string) (models.Page[models.Notification], error) {
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	cur, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Notification]{}, apperror.Validation("Cursor inválido")
+	}
+	notifs, err := s.repo.GetNotifications(userID, limit+1, cur)
+	if err != nil {
+		return models.Page[models.Notification]{}, err
+	}
+	return models.NewPage(notifs, limit, models.NotificationCursor), nil
+}
+
+func (s *notificationService) UnreadCount(userID int) (int, error) {
+	return s.repo.UnreadCount(userID)
+}
+
+func (s *notificationService) MarkRead(userID, id int, upTo bool) error {
+	if id <= 0 {
+		return s.repo.MarkAsRead(userID)
+	}
+	var found bool
+	var err error
+	if upTo {
+		found, err = s.repo.MarkReadUpTo(userID, id)
+	} else {
+		found, err = s.repo.MarkOneAsRead(userID, id)
+	}
+	if err != nil {
+		return err
+	}
+	if !found {
+		return apperror.NotFound("notificação não encontrada")
+	}
+	return nil
+}
+
+func (s *notificationService) Preferences(userID int) ([]models.NotificationPreference, error) {
+	return s.repo.Preferences(userID)
+}
+
+func (s *notificationService) UpdatePreferences(userID int, updates []models.NotificationPreferenceUpdate) ([]models.NotificationPreference, error) {
+	current, err := s.repo.Preferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := mergeNotificationPreferences(current, updates)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetPreferences(userID, merged); err != nil {
+		return nil, err
+	}
+	return s.repo.Preferences(userID)
+}
+
+// mergeNotificationPreferences applies updates on top of current and returns
+// only the categories that changed.
+func mergeNotificationPreferences(current []models.NotificationPreference, updates []models.NotificationPreferenceUpdate) ([]models.NotificationPreference, error) {
+	byCategory := make(map[string]models.NotificationPreference, len(current))
+	for _, p := range current {
+		byCategory[p.Category] = p
+	}
+
+	var changed []models.NotificationPreference
+	for _, u := range updates {
+		p, ok := byCategory[u.Category]
+		if !ok {
+			return nil, apperror.Validation("categoria desconhecida: " + u.Category)
+		}
+		if u.InApp != nil {
+			p.InApp = *u.InApp
+		}
+		if u.Email != nil {
+			p.Email = *u.Email
+		}
+		if p.Category == models.NotifySystem && !p.InApp {
+			return nil, apperror.Validation("avisos do sistema não podem ser desligados no portal")
+		}
+		byCategory[u.Category] = p
+		changed = append(changed, p)
+	}
+	return changed, nil
+}
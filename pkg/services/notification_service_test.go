@@ -0,0 +1,52 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"testing"
+)
+
+func TestMergeNotificationPreferences(t *testing.T) {
+	current := []models.NotificationPreference{
+		{Category: models.NotifyLikes, InApp: true, Email: false},
+		{Category: models.NotifySystem, InApp: true, Email: true},
+	}
+	off, on := false, true
+
+	changed, err := mergeNotificationPreferences(current, []models.NotificationPreferenceUpdate{
+		{Category: models.NotifyLikes, InApp: &off},
+		{Category: models.NotifySystem, Email: &off},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.NotificationPreference{
+		{Category: models.NotifyLikes, InApp: false, Email: false},
+		{Category: models.NotifySystem, InApp: true, Email: false},
+	}
+	if len(changed) != 2 || changed[0] != want[0] || changed[1] != want[1] {
+		t.Fatalf("got %+v, want %+v", changed, want)
+	}
+
+	if _, err := mergeNotificationPreferences(current, []models.NotificationPreferenceUpdate{{Category: "dms", Email: &on}}); err == nil {
+		t.Fatal("unknown category must be rejected")
+	}
+	if _, err := mergeNotificationPreferences(current, []models.NotificationPreferenceUpdate{{Category: models.NotifySystem, InApp: &off}}); err == nil {
+		t.Fatal("system notices cannot be turned off in the portal")
+	}
+}
+
+func TestNotificationCategory(t *testing.T) {
+	cases := map[string]string{
+		"like":             models.NotifyLikes,
+		"reaction":         models.NotifyLikes,
+		"quote":            models.NotifyReposts,
+		"follow":           models.NotifyFollows,
+		"content_hidden":   models.NotifySystem,
+		"report_dismissed": models.NotifySystem,
+	}
+	for typ, want := range cases {
+		if got := models.NotificationCategory(typ); got != want {
+			t.Errorf("NotificationCategory(%q) = %q, want %q", typ, got, want)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes and verifies passwords stored in users.password.
+// Encoded hashes are self-describing (PHC string format for Argon2id,
+// "$2a$"/"$2b$" for bcrypt) so several algorithms can coexist in the table.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	// Supports reports whether encoded was produced by this algorithm.
+	Supports(encoded string) bool
+	// NeedsRehash reports whether encoded uses weaker parameters than the
+	// hasher is currently configured with.
+	NeedsRehash(encoded string) bool
+}
+
+// ─── Argon2id ────────────────────────────────────────────────────────────────
+
+// argon2idHasher produces $argon2id$v=19$m=<KiB>,t=<iter>,p=<threads>$<salt>$<key>
+// with unpadded standard base64, the same format as the reference implementation.
+type argon2idHasher struct {
+	memory  uint32 // KiB
+	time    uint32
+	threads uint8
+	saltLen int
+	keyLen  uint32
+}
+
+// newArgon2idHasher uses the OWASP baseline (19 MiB, 2 iterations, 1 lane),
+// overridable through ARGON2_MEMORY_KIB, ARGON2_ITERATIONS and ARGON2_PARALLELISM.
+func newArgon2idHasher() *argon2idHasher {
+	h := &argon2idHasher{memory: 19 * 1024, time: 2, threads: 1, saltLen: 16, keyLen: 32}
+	if v, err := strconv.ParseUint(os.Getenv("ARGON2_MEMORY_KIB"), 10, 32); err == nil && v >= 8*1024 {
+		h.memory = uint32(v)
+	}
+	if v, err := strconv.ParseUint(os.Getenv("ARGON2_ITERATIONS"), 10, 32); err == nil && v >= 1 {
+		h.time = uint32(v)
+	}
+	if v, err := strconv.ParseUint(os.Getenv("ARGON2_PARALLELISM"), 10, 8); err == nil && v >= 1 {
+		h.threads = uint8(v)
+	}
+	return h
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, h.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.time, h.memory, h.threads, h.keyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.memory, h.time, h.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (h *argon2idHasher) Verify(password, encoded string) (bool, error) {
+	p, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.time, p.memory, p.threads, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1, nil
+}
+
+func (h *argon2idHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	p, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return p.memory < h.memory || p.time < h.time || p.threads < h.threads || uint32(len(p.key)) < h.keyLen
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	key     []byte
+}
+
+func decodeArgon2id(encoded string) (argon2Params, error) {
+	var p argon2Params
+
+	// "", "argon2id", "v=19", "m=..,t=..,p=..", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, fmt.Errorf("hash argon2id inválido")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, fmt.Errorf("versão argon2 não suportada")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return p, fmt.Errorf("parâmetros argon2 inválidos")
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, fmt.Errorf("salt argon2 inválido")
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(p.key) == 0 {
+		return p, fmt.Errorf("chave argon2 inválida")
+	}
+	return p, nil
+}
+
+// ─── bcrypt (legacy) ─────────────────────────────────────────────────────────
+
+type bcryptHasher struct {
+	cost int
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	b, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	return string(b), err
+}
+
+func (h *bcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (h *bcryptHasher) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.cost
+}
+
+// ─── Chain ───────────────────────────────────────────────────────────────────
+
+// hasherChain hashes new passwords with the preferred algorithm and verifies
+// against whichever algorithm produced the stored hash. Any hash not produced
+// by the preferred hasher with current parameters is flagged for rehash.
+type hasherChain struct {
+	preferred PasswordHasher
+	legacy    []PasswordHasher
+}
+
+// NewPasswordHasher returns Argon2id for new hashes, still accepting bcrypt.
+func NewPasswordHasher() PasswordHasher {
+	return &hasherChain{
+		preferred: newArgon2idHasher(),
+		legacy:    []PasswordHasher{&bcryptHasher{cost: bcrypt.DefaultCost}},
+	}
+}
+
+func (c *hasherChain) Hash(password string) (string, error) {
+	return c.preferred.Hash(password)
+}
+
+func (c *hasherChain) Verify(password, encoded string) (bool, error) {
+	if h := c.find(encoded); h != nil {
+		return h.Verify(password, encoded)
+	}
+	return false, fmt.Errorf("algoritmo de hash desconhecido")
+}
+
+func (c *hasherChain) Supports(encoded string) bool {
+	return c.find(encoded) != nil
+}
+
+func (c *hasherChain) NeedsRehash(encoded string) bool {
+	if !c.preferred.Supports(encoded) {
+		return true
+	}
+	return c.preferred.NeedsRehash(encoded)
+}
+
+func (c *hasherChain) find(encoded string) PasswordHasher {
+	if c.preferred.Supports(encoded) {
+		return c.preferred
+	}
+	for _, h := range c.legacy {
+		if h.Supports(encoded) {
+			return h
+		}
+	}
+	return nil
+}
+
+// ─── Breached / common passwords ─────────────────────────────────────────────
+
+//go:embed data/common_passwords.txt
+var commonPasswordsRaw string
+
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordsRaw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		set[strings.ToLower(line)] = struct{}{}
+	}
+	return set
+}()
+
+// isCommonPassword is case-insensitive: "Senha123" is as guessable as "senha123".
+func isCommonPassword(p string) bool {
+	_, found := commonPasswords[strings.ToLower(p)]
+	return found
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestArgon2idHashVerify(t *testing.T) {
+	h := NewPasswordHasher()
+
+	encoded, err := h.Hash("correct horse battery")
+	if err != nil {
+		t.Fatalf("Hash retornou erro: %v", err)
+	}
+	if !strings.HasPrefix(encoded, "$argon2id$v=19$m=") {
+		t.Fatalf("hash fora do formato PHC esperado: %s", encoded)
+	}
+
+	if ok, err := h.Verify("correct horse battery", encoded); err != nil || !ok {
+		t.Errorf("senha correta deveria validar (ok=%v err=%v)", ok, err)
+	}
+	if ok, _ := h.Verify("wrong horse battery", encoded); ok {
+		t.Error("senha incorreta não deveria validar")
+	}
+	if h.NeedsRehash(encoded) {
+		t.Error("hash recém-gerado não deveria precisar de rehash")
+	}
+}
+
+func TestLegacyBcryptVerifiesAndNeedsRehash(t *testing.T) {
+	h := NewPasswordHasher()
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("senha-antiga-123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt falhou: %v", err)
+	}
+
+	if ok, err := h.Verify("senha-antiga-123", string(legacy)); err != nil || !ok {
+		t.Errorf("hash bcrypt legado deveria validar (ok=%v err=%v)", ok, err)
+	}
+	if ok, _ := h.Verify("outra-senha", string(legacy)); ok {
+		t.Error("senha incorreta não deveria validar contra bcrypt")
+	}
+	if !h.NeedsRehash(string(legacy)) {
+		t.Error("hash bcrypt deveria ser marcado para rehash")
+	}
+}
+
+func TestArgon2idWeakerParamsNeedRehash(t *testing.T) {
+	weak := &argon2idHasher{memory: 8 * 1024, time: 1, threads: 1, saltLen: 16, keyLen: 32}
+	encoded, err := weak.Hash("qualquer-coisa")
+	if err != nil {
+		t.Fatalf("Hash retornou erro: %v", err)
+	}
+
+	h := NewPasswordHasher()
+	if ok, _ := h.Verify("qualquer-coisa", encoded); !ok {
+		t.Error("hash com parâmetros antigos ainda deve validar")
+	}
+	if !h.NeedsRehash(encoded) {
+		t.Error("parâmetros mais fracos deveriam exigir rehash")
+	}
+}
+
+func TestUnknownHashFormat(t *testing.T) {
+	h := NewPasswordHasher()
+	if ok, err := h.Verify("x", "plaintext"); ok || err == nil {
+		t.Errorf("formato desconhecido deveria falhar (ok=%v err=%v)", ok, err)
+	}
+}
+
+func TestValidatePasswordRejectsCommon(t *testing.T) {
+	for _, p := range []string{"12345678", "Senha123", "PASSWORD1", "flamengo"} {
+		if err := validatePassword(p); err == nil {
+			t.Errorf("senha comum '%s' deveria ser rejeitada", p)
+		}
+	}
+	if err := validatePassword("v8#kP2!xQm"); err != nil {
+		t.Errorf("senha forte rejeitada: %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+const (
+	maxAnnouncements        = 3
+	defaultAnnouncementSpan = 7 * 24 * time.Hour
+	maxAnnouncementSpan     = 90 * 24 * time.Hour
+)
+
+// PinPost pins one of userID's root posts to their profile, replacing the
+// previous pin.
+func (s *socialService) PinPost(userID, postID int) (map[string]interface{}, error) {
+	p, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.UserID != userID {
+		return nil, apperror.Forbidden("só o autor pode fixar este post")
+	}
+	if p.ParentID != nil {
+		return nil, apperror.Validation("respostas não podem ser fixadas")
+	}
+
+	if err := s.repo.SetPinnedPost(userID, &postID); err != nil {
+		return nil, err
+	}
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+	return map[string]interface{}{"post_id": postID, "pinned": true}, nil
+}
+
+func (s *socialService) UnpinPost(userID, postID int) (map[string]interface{}, error) {
+	if s.repo.PinnedPostID(userID) != postID {
+		return nil, apperror.NotFound("este post não está fixado no seu perfil")
+	}
+	if err := s.repo.SetPinnedPost(userID, nil); err != nil {
+		return nil, err
+	}
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+	return map[string]interface{}{"post_id": postID, "pinned": false}, nil
+}
+
+// pinnedPost loads the post pinned to profileUserID's profile as seen by
+// viewerID, or nil when there is none or the viewer cannot see it.
+func (s *socialService) pinnedPost(profileUserID, viewerID int) *models.Post {
+	id := s.repo.PinnedPostID(profileUserID)
+	if id == 0 {
+		return nil
+	}
+	byID, err := s.repo.PostsByID([]int{id}, viewerID)
+	if err != nil {
+		return nil
+	}
+	p, ok := byID[id]
+	if !ok {
+		return nil
+	}
+	p.Pinned = true
+	p.Replies = []models.Post{}
+	pinned := []models.Post{p}
+	s.hydratePosts(pinned, viewerID)
+	return &pinned[0]
+}
+
+// PinAnnouncement keeps postID at the top of the feed for duration ("72h",
+// "7d"; default 7 days). Re-pinning an announcement renews it.
+func (s *socialService) PinAnnouncement(adminID, postID int, duration string) (models.Post, error) {
+	d, err := parseSpan(duration, defaultAnnouncementSpan)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if d < time.Hour || d > maxAnnouncementSpan {
+		return models.Post{}, apperror.Validation("o aviso deve ficar fixado entre 1 hora e 90 dias")
+	}
+
+	p, err := s.repo.Thread(postID, 0)
+	if err == sql.ErrNoRows {
+		return models.Post{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Post{}, err
+	}
+	if p.ParentID != nil {
+		return models.Post{}, apperror.Validation("respostas não podem virar aviso")
+	}
+
+	active, err := s.repo.Announcements(0)
+	if err != nil {
+		return models.Post{}, err
+	}
+	renewing := false
+	for _, a := range active {
+		renewing = renewing || a.ID == postID
+	}
+	if !renewing && len(active) >= maxAnnouncements {
+		return models.Post{}, apperror.Conflict(fmt.Sprintf("já existem %d avisos fixados; remova um antes", maxAnnouncements))
+	}
+
+	until := time.Now().Add(d)
+	if err := s.repo.PinAnnouncement(postID, adminID, until); err != nil {
+		return models.Post{}, err
+	}
+	s.redis.DelPattern("social:feed:*")
+
+	p.Pinned = true
+	p.PinnedUntil = &until
+	p.Replies = []models.Post{}
+	return p, nil
+}
+
+func (s *socialService) UnpinAnnouncement(postID int) error {
+	ok, err := s.repo.UnpinAnnouncement(postID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.NotFound("este post não é um aviso fixado")
+	}
+	s.redis.DelPattern("social:feed:*")
+	return nil
+}
+
+func (s *socialService) Announcements(userID int) ([]models.Post, error) {
+	posts, err := s.repo.Announcements(userID)
+	if err != nil {
+		return nil, err
+	}
+	s.hydratePosts(posts, userID)
+	if posts == nil {
+		posts = []models.Post{}
+	}
+	return posts, nil
+}
+
+// withAnnouncements puts the active announcements on top of the first feed
+// page, dropping their regular copy from that page. The first page can so
+// hold up to maxAnnouncements posts more than limit: trimming it would make
+// NextCursor skip the posts cut off.
+func (s *socialService) withAnnouncements(items []models.Post, userID int) []models.Post {
+	announcements, err := s.Announcements(userID)
+	if err != nil || len(announcements) == 0 {
+		return items
+	}
+	pinned := make(map[int]bool, len(announcements))
+	for _, a := range announcements {
+		pinned[a.ID] = true
+	}
+	out := append(make([]models.Post, 0, len(announcements)+len(items)), announcements...)
+	for _, p := range items {
+		if !pinned[p.ID] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// withoutPost drops the pinned post from a profile page; the profile already
+// shows it on top as PinnedPost. The page may come out one post short.
+func withoutPost(items []models.Post, postID int) []models.Post {
+	out := items[:0]
+	for _, p := range items {
+		if p.ID != postID {
+			out = append(out, p)
+		}
+	}
+	return out
+}
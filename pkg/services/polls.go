@@ -0,0 +1,207 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	pollMinOptions   = 2
+	pollMaxOptions   = 6
+	pollOptionMaxLen = 100
+	pollMinDuration  = 5 * time.Minute
+	pollMaxDuration  = 30 * 24 * time.Hour
+)
+
+// normalizePoll trims the options and checks the limits of a new poll.
+func normalizePoll(req models.PollRequest, now time.Time) (models.PollRequest, error) {
+	if len(req.Options) < pollMinOptions || len(req.Options) > pollMaxOptions {
+		return req, apperror.Validation(fmt.Sprintf("a enquete precisa de %d a %d opções", pollMinOptions, pollMaxOptions))
+	}
+
+	seen := map[string]bool{}
+	options := make([]string, len(req.Options))
+	for i, o := range req.Options {
+		o = strings.TrimSpace(o)
+		if o == "" {
+			return req, apperror.Validation("opção da enquete vazia")
+		}
+		if utf8.RuneCountInString(o) > pollOptionMaxLen {
+			return req, apperror.Validation(fmt.Sprintf("opção da enquete muito longa (máx %d caracteres)", pollOptionMaxLen))
+		}
+		key := strings.ToLower(o)
+		if seen[key] {
+			return req, apperror.Validation("opções da enquete repetidas")
+		}
+		seen[key] = true
+		options[i] = o
+	}
+	req.Options = options
+
+	if req.ClosesAt != nil {
+		d := req.ClosesAt.Sub(now)
+		if d < pollMinDuration || d > pollMaxDuration {
+			return req, apperror.Validation("a enquete deve encerrar entre 5 minutos e 30 dias")
+		}
+		closesAt := req.ClosesAt.UTC()
+		req.ClosesAt = &closesAt
+	}
+	return req, nil
+}
+
+// hidePollResults zeroes the counts of an open poll the viewer hasn't voted
+// in. The author always sees the partial results.
+func hidePollResults(p *models.Poll, authorID, userID int) {
+	p.ResultsVisible = p.Voted || p.Closed || (userID > 0 && userID == authorID)
+	if p.ResultsVisible {
+		return
+	}
+	for i := range p.Options {
+		p.Options[i].Votes = 0
+	}
+}
+
+// attachPolls fills Poll for posts and their loaded replies with a single
+// batch query.
+func (s *socialService) attachPolls(posts []models.Post, userID int) {
+	var ids []int
+	var collect func([]models.Post)
+	collect = func(ps []models.Post) {
+		for i := range ps {
+			ids = append(ids, ps[i].ID)
+			collect(ps[i].Replies)
+		}
+	}
+	collect(posts)
+	if len(ids) == 0 {
+		return
+	}
+
+	byPost, err := s.repo.Polls(ids, userID)
+	if err != nil || len(byPost) == 0 {
+		return
+	}
+
+	var fill func([]models.Post)
+	fill = func(ps []models.Post) {
+		for i := range ps {
+			if poll, ok := byPost[ps[i].ID]; ok {
+				hidePollResults(poll, ps[i].UserID, userID)
+				ps[i].Poll = poll
+			}
+			fill(ps[i].Replies)
+		}
+	}
+	fill(posts)
+}
+
+// savePoll attaches a poll to a post that was just created. If it fails the
+// post is removed again, so a broken poll never shows up as a plain post;
+// when even that removal fails the post is left behind, logged, and both
+// errors are returned.
+func (s *socialService) savePoll(p *models.Post, userID int, poll *models.PollRequest) error {
+	if poll == nil {
+		return nil
+	}
+	if err := s.repo.CreatePoll(p.ID, *poll); err != nil {
+		if _, delErr := s.repo.DeletePost(p.ID, userID); delErr != nil {
+			log.Printf("[SOCIAL] enquete falhou e o post %d não pôde ser removido: %v", p.ID, delErr)
+			return errors.Join(err, delErr)
+		}
+		s.destroyAttachments(p.Attachments)
+		return err
+	}
+	// The post and poll are committed by now; a failed read only leaves
+	// p.Poll empty in the response.
+	polls, err := s.repo.Polls([]int{p.ID}, userID)
+	if err != nil {
+		log.Printf("[SOCIAL] enquete do post %d criada, mas não pôde ser lida: %v", p.ID, err)
+		return nil
+	}
+	if created, ok := polls[p.ID]; ok {
+		hidePollResults(created, userID, userID)
+		p.Poll = created
+	}
+	return nil
+}
+
+func (s *socialService) Poll(postID, userID int) (models.Poll, error) {
+	p, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows || (err == nil && s.blockedWith(p.UserID, userID)) {
+		return models.Poll{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Poll{}, err
+	}
+	polls, err := s.repo.Polls([]int{postID}, userID)
+	if err != nil {
+		return models.Poll{}, err
+	}
+	poll, ok := polls[postID]
+	if !ok {
+		return models.Poll{}, apperror.NotFound("este post não tem enquete")
+	}
+	hidePollResults(poll, p.UserID, userID)
+	return *poll, nil
+}
+
+// Vote casts userID's ballot. Single-choice polls take exactly one option.
+func (s *socialService) Vote(userID, postID int, optionIDs []int) (models.Poll, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Poll{}, err
+	}
+	poll, err := s.Poll(postID, userID)
+	if err != nil {
+		return models.Poll{}, err
+	}
+	if poll.Closed {
+		return models.Poll{}, apperror.Forbidden("esta enquete já foi encerrada")
+	}
+	if poll.Voted {
+		return models.Poll{}, apperror.Conflict("você já votou nesta enquete")
+	}
+
+	valid := map[int]bool{}
+	for _, o := range poll.Options {
+		valid[o.ID] = true
+	}
+	chosen := map[int]bool{}
+	var choices []int
+	for _, id := range optionIDs {
+		if !valid[id] {
+			return models.Poll{}, apperror.Validation("opção inválida")
+		}
+		if !chosen[id] {
+			chosen[id] = true
+			choices = append(choices, id)
+		}
+	}
+	if len(choices) == 0 {
+		return models.Poll{}, apperror.Validation("escolha ao menos uma opção")
+	}
+	if !poll.Multiple && len(choices) > 1 {
+		return models.Poll{}, apperror.Validation("esta enquete aceita só uma opção")
+	}
+
+	ok, err := s.repo.VotePoll(postID, userID, choices)
+	if err != nil {
+		return models.Poll{}, err
+	}
+	if !ok {
+		return models.Poll{}, apperror.Conflict("você já votou nesta enquete ou ela foi encerrada")
+	}
+
+	// Every viewer's cached copy has a stale voter count; the voter's own
+	// pages also flip to showing results.
+	s.evictThreads(postID)
+	s.redis.DelPattern(fmt.Sprintf("social:*:lid%d", userID))
+
+	return s.Poll(postID, userID)
+}
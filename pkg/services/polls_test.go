@@ -0,0 +1,163 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizePoll(t *testing.T) {
+	now := time.Date(2025, 5, 2, 12, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *time.Time { t := now.Add(d); return &t }
+
+	cases := []struct {
+		name string
+		req  models.PollRequest
+		ok   bool
+	}{
+		{"ok", models.PollRequest{Options: []string{" Sexta ", "Sábado"}, ClosesAt: in(24 * time.Hour)}, true},
+		{"sem prazo", models.PollRequest{Options: []string{"a", "b", "c"}, Multiple: true}, true},
+		{"uma opção", models.PollRequest{Options: []string{"a"}}, false},
+		{"sete opções", models.PollRequest{Options: []string{"a", "b", "c", "d", "e", "f", "g"}}, false},
+		{"opção vazia", models.PollRequest{Options: []string{"a", "  "}}, false},
+		{"repetida", models.PollRequest{Options: []string{"Sim", "sim"}}, false},
+		{"já encerrada", models.PollRequest{Options: []string{"a", "b"}, ClosesAt: in(-time.Hour)}, false},
+		{"longa demais", models.PollRequest{Options: []string{"a", "b"}, ClosesAt: in(60 * 24 * time.Hour)}, false},
+	}
+	for _, tc := range cases {
+		got, err := normalizePoll(tc.req, now)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: err = %v, want ok=%v", tc.name, err, tc.ok)
+		}
+		if tc.name == "ok" && got.Options[0] != "Sexta" {
+			t.Errorf("options not trimmed: %q", got.Options)
+		}
+	}
+}
+
+func TestHidePollResults(t *testing.T) {
+	newPoll := func() *models.Poll {
+		return &models.Poll{Options: []models.PollOption{{ID: 1, Votes: 3}, {ID: 2, Votes: 5}}}
+	}
+
+	p := newPoll()
+	hidePollResults(p, 10, 20)
+	if p.ResultsVisible || p.Options[0].Votes != 0 || p.Options[1].Votes != 0 {
+		t.Fatalf("open poll leaked results to a non-voter: %+v", p)
+	}
+
+	for name, p := range map[string]*models.Poll{
+		"voted":  func() *models.Poll { p := newPoll(); p.Voted = true; return p }(),
+		"closed": func() *models.Poll { p := newPoll(); p.Closed = true; return p }(),
+	} {
+		hidePollResults(p, 10, 20)
+		if !p.ResultsVisible || p.Options[1].Votes != 5 {
+			t.Errorf("%s: results should be visible: %+v", name, p)
+		}
+	}
+
+	p = newPoll()
+	hidePollResults(p, 10, 10)
+	if !p.ResultsVisible {
+		t.Fatal("author should see partial results")
+	}
+}
+
+// brokenPollRepo fails to save the poll and, optionally, to remove the post.
+type brokenPollRepo struct {
+	*fakeSocialRepo
+	deleteErr error
+	deleted   []int
+}
+
+func (r *brokenPollRepo) CreatePoll(postID int, poll models.PollRequest) error {
+	return errors.New("falha na enquete")
+}
+
+func (r *brokenPollRepo) DeletePost(postID, userID int) (int, error) {
+	if r.deleteErr != nil {
+		return 0, r.deleteErr
+	}
+	r.deleted = append(r.deleted, postID)
+	return 1, nil
+}
+
+func TestSavePollRollsBackPost(t *testing.T) {
+	repo := &brokenPollRepo{fakeSocialRepo: newFakeSocialRepo()}
+	s := &socialService{repo: repo}
+	poll := &models.PollRequest{Options: []string{"a", "b"}}
+
+	if err := s.savePoll(&models.Post{ID: 3}, 1, poll); err == nil || len(repo.deleted) != 1 || repo.deleted[0] != 3 {
+		t.Errorf("post deveria ser removido: err=%v deleted=%v", err, repo.deleted)
+	}
+
+	repo.deleteErr = errors.New("banco fora do ar")
+	err := s.savePoll(&models.Post{ID: 4}, 1, poll)
+	if !errors.Is(err, repo.deleteErr) {
+		t.Errorf("falha ao remover o post deveria voltar no erro, obteve %v", err)
+	}
+}
+
+// unreadablePollRepo saves the poll but fails to read it back.
+type unreadablePollRepo struct {
+	*fakeSocialRepo
+}
+
+func (r *unreadablePollRepo) CreatePoll(postID int, poll models.PollRequest) error { return nil }
+
+func (r *unreadablePollRepo) Polls(postIDs []int, userID int) (map[int]*models.Poll, error) {
+	return nil, errors.New("falha na leitura")
+}
+
+func TestSavePollKeepsPostWhenReadFails(t *testing.T) {
+	s := &socialService{repo: &unreadablePollRepo{newFakeSocialRepo()}}
+	p := &models.Post{ID: 3}
+	if err := s.savePoll(p, 1, &models.PollRequest{Options: []string{"a", "b"}}); err != nil || p.Poll != nil {
+		t.Errorf("enquete já salva não deveria falhar a criação: err=%v poll=%v", err, p.Poll)
+	}
+}
+
+// brokenAttachmentsRepo saves the first attachment and fails on the next.
+type brokenAttachmentsRepo struct {
+	*brokenPollRepo
+}
+
+func (r *brokenAttachmentsRepo) AddAttachments(postID int, attachments []models.Attachment) ([]models.Attachment, error) {
+	return attachments[:1], errors.New("falha nos anexos")
+}
+
+// fakeMedia records what was removed from storage.
+type fakeMedia struct {
+	MediaService
+	destroyed []string
+}
+
+func (m *fakeMedia) Destroy(publicID, resourceType string) {
+	m.destroyed = append(m.destroyed, publicID)
+}
+
+func TestSaveAttachmentsRollsBackPost(t *testing.T) {
+	repo := &brokenAttachmentsRepo{&brokenPollRepo{fakeSocialRepo: newFakeSocialRepo()}}
+	media := &fakeMedia{}
+	s := &socialService{repo: repo, media: media}
+	uploaded := []models.Attachment{{PublicID: "a"}, {PublicID: "b"}}
+
+	if err := s.saveAttachments(&models.Post{ID: 3}, 1, uploaded); err == nil || len(repo.deleted) != 1 || repo.deleted[0] != 3 {
+		t.Errorf("post deveria ser removido: err=%v deleted=%v", err, repo.deleted)
+	}
+	if len(media.destroyed) != 2 {
+		t.Errorf("os dois uploads deveriam ser apagados, apagados: %v", media.destroyed)
+	}
+
+	// Sem conseguir remover o post, o anexo já ligado a ele fica.
+	repo.deleteErr = errors.New("banco fora do ar")
+	media.destroyed = nil
+	err := s.saveAttachments(&models.Post{ID: 4}, 1, uploaded)
+	if !errors.Is(err, repo.deleteErr) {
+		t.Errorf("falha ao remover o post deveria voltar no erro, obteve %v", err)
+	}
+	if len(media.destroyed) != 1 || media.destroyed[0] != "b" {
+		t.Errorf("só o upload não salvo deveria ser apagado, apagados: %v", media.destroyed)
+	}
+}
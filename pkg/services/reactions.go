@@ -0,0 +1,217 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// reactionAliases lets clients name reactions without sending the emoji.
+var reactionAliases = map[string]string{
+	"like":  "👍",
+	"love":  "❤️",
+	"haha":  "😂",
+	"wow":   "😮",
+	"sad":   "😢",
+	"party": "🎉",
+}
+
+// normalizeReaction maps an emoji or alias to its canonical form in
+// models.Reactions. The variation selector (U+FE0F) is optional, so "❤" and
+// "❤️" are the same reaction.
+func normalizeReaction(v string) (string, bool) {
+	v = strings.TrimSpace(v)
+	if e, ok := reactionAliases[strings.ToLower(v)]; ok {
+		return e, true
+	}
+	bare := strings.ReplaceAll(v, "\ufe0f", "")
+	for _, e := range models.Reactions {
+		if strings.ReplaceAll(e, "\ufe0f", "") == bare {
+			return e, true
+		}
+	}
+	return "", false
+}
+
+func (s *socialService) React(userID, postID int, emoji string) (map[string]interface{}, error) {
+	return s.toggleReaction(userID, postID, emoji, true)
+}
+
+func (s *socialService) Unreact(userID, postID int, emoji string) (map[string]interface{}, error) {
+	return s.toggleReaction(userID, postID, emoji, false)
+}
+
+func (s *socialService) Like(userID, postID int) (map[string]interface{}, error) {
+	return s.toggleReaction(userID, postID, models.ReactionLike, true)
+}
+
+func (s *socialService) Unlike(userID, postID int) (map[string]interface{}, error) {
+	return s.toggleReaction(userID, postID, models.ReactionLike, false)
+}
+
+// toggleReaction adds or removes one of userID's reactions. 👍 also moves
+// posts.likes, which the legacy like endpoints and events report.
+func (s *socialService) toggleReaction(userID, postID int, emoji string, on bool) (map[string]interface{}, error) {
+	emoji, ok := normalizeReaction(emoji)
+	if !ok {
+		return nil, apperror.Validation("reação inválida; use " + strings.Join(models.Reactions, " "))
+	}
+
+	var post models.Post
+	var success bool
+	var err error
+	if on {
+		if err := ensureActive(s.auth, userID); err != nil {
+			return nil, err
+		}
+		if post, err = s.interactable(postID, userID); err != nil {
+			return nil, err
+		}
+		success, err = s.repo.AddReaction(userID, postID, emoji)
+	} else {
+		success, err = s.repo.RemoveReaction(userID, postID, emoji)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("erro interno na db")
+	}
+
+	var likes int
+	switch {
+	case success && emoji == models.ReactionLike && on:
+		likes, err = s.repo.IncLikeCount(postID)
+	case success && emoji == models.ReactionLike:
+		likes, err = s.repo.DecLikeCount(postID)
+	default:
+		likes, err = s.repo.GetLikeCount(postID)
+	}
+	if err != nil {
+		return nil, apperror.NotFound("post não encontrado")
+	}
+
+	if success && on && post.UserID != userID && post.UserID != 0 {
+		kind := "reaction"
+		if emoji == models.ReactionLike {
+			kind = "like"
+		}
+		s.notif.CreateNotification(post.UserID, &userID, kind, &postID)
+	}
+
+	if success {
+		s.evictThreads(postID)
+		s.redis.DelPattern("social:feed:*")
+	}
+
+	counts, err := s.repo.Reactions([]int{postID}, userID, models.Reactions)
+	if err != nil {
+		return nil, err
+	}
+	reactions := counts[postID]
+	if reactions == nil {
+		reactions = []models.ReactionCount{}
+	}
+	return map[string]interface{}{
+		"post_id":   postID,
+		"emoji":     emoji,
+		"likes":     likes,
+		"reactions": reactions,
+	}, nil
+}
+
+// Reactors is the "who reacted" list of a post, optionally for one emoji.
+func (s *socialService) Reactors(postID, userID int, emoji string, limit int, cursor string) (models.Page[models.Reactor], error) {
+	if emoji != "" {
+		var ok bool
+		if emoji, ok = normalizeReaction(emoji); !ok {
+			return models.Page[models.Reactor]{}, apperror.Validation("reação inválida")
+		}
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	cur, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Reactor]{}, apperror.Validation(err.Error())
+	}
+	post, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows || (err == nil && s.blockedWith(post.UserID, userID)) {
+		return models.Page[models.Reactor]{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Page[models.Reactor]{}, err
+	}
+
+	list, err := s.repo.Reactors(postID, userID, emoji, limit+1, cur)
+	if err != nil {
+		return models.Page[models.Reactor]{}, err
+	}
+	return models.NewPage(list, limit, func(r models.Reactor) models.Cursor {
+		return models.Cursor{CreatedAt: r.Since, ID: r.ReactionID}
+	}), nil
+}
+
+// Likers lists who liked (reacted 👍 to) a post, newest like first. Pages
+// are cached next to the thread, so reaction changes and profile edits
+// evict them with it.
+func (s *socialService) Likers(postID, userID, limit int, cursor string) (models.Page[models.UserSummary], error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	pos := cursor
+	if pos == "" {
+		pos = "head"
+	}
+	cacheKey := fmt.Sprintf("social:thread:%d:likes:%d:%s:lid%d", postID, limit, pos, userID)
+	var cached models.Page[models.UserSummary]
+	if s.redis.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	reactors, err := s.Reactors(postID, userID, models.ReactionLike, limit, cursor)
+	if err != nil {
+		return models.Page[models.UserSummary]{}, err
+	}
+	page := models.Page[models.UserSummary]{
+		Items:      make([]models.UserSummary, len(reactors.Items)),
+		NextCursor: reactors.NextCursor,
+	}
+	for i, r := range reactors.Items {
+		page.Items[i] = r.UserSummary
+	}
+
+	s.redis.Set(cacheKey, page, 30*time.Second)
+	return page, nil
+}
+
+// attachReactions fills the per-emoji counts of posts and their loaded
+// replies, as seen by userID.
+func (s *socialService) attachReactions(posts []models.Post, userID int) {
+	var ids []int
+	var collect func([]models.Post)
+	collect = func(ps []models.Post) {
+		for i := range ps {
+			ids = append(ids, ps[i].ID)
+			collect(ps[i].Replies)
+		}
+	}
+	collect(posts)
+	if len(ids) == 0 {
+		return
+	}
+
+	byPost, err := s.repo.Reactions(ids, userID, models.Reactions)
+	if err != nil || len(byPost) == 0 {
+		return
+	}
+
+	var fill func([]models.Post)
+	fill = func(ps []models.Post) {
+		for i := range ps {
+			ps[i].Reactions = byPost[ps[i].ID]
+			fill(ps[i].Replies)
+		}
+	}
+	fill(posts)
+}
@@ -0,0 +1,24 @@
+package services
+
+import "testing"
+
+func TestNormalizeReaction(t *testing.T) {
+	cases := map[string]string{
+		"👍":      "👍",
+		" LOVE ": "❤️",
+		"❤":      "❤️", // sem o seletor de variação
+		"❤️":     "❤️",
+		"party":  "🎉",
+	}
+	for in, want := range cases {
+		got, ok := normalizeReaction(in)
+		if !ok || got != want {
+			t.Errorf("normalizeReaction(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+	for _, in := range []string{"", "👎", "curtir", "👍👍"} {
+		if _, ok := normalizeReaction(in); ok {
+			t.Errorf("normalizeReaction(%q) should be rejected", in)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+)
+
+// CreateRepost reposts repostID, or quotes it when texto is not empty.
+// Reposting a plain repost targets the post it points to.
+func (s *socialService) CreateRepost(userID, repostID int, texto string) (models.Post, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Post{}, err
+	}
+	original, err := s.interactable(repostID, userID)
+	if err != nil {
+		return models.Post{}, err
+	}
+	if original.RepostID != nil && original.Texto == "" {
+		repostID = *original.RepostID
+		if original, err = s.interactable(repostID, userID); err != nil {
+			return models.Post{}, err
+		}
+	}
+
+	filtered := FilterResult{Text: texto}
+	if texto != "" {
+		if filtered, err = s.filter.Check(FilterInput{Kind: "post", Source: UserSource(userID), Text: texto}); err != nil {
+			return models.Post{}, err
+		}
+		texto = filtered.Text
+	}
+
+	p, created, err := s.repo.CreateRepost(userID, repostID, texto, filtered.Action == FilterHold)
+	if err != nil {
+		return p, err
+	}
+	if !created {
+		return models.Post{}, apperror.Conflict("você já repostou este post")
+	}
+
+	username, displayName, _, avatar, _ := s.repo.ProfileInfo(userID)
+
+	p.Texto = texto
+	p.Author = username
+	p.AuthorName = username
+	p.AvatarURL = avatar
+	if displayName != "" {
+		p.AuthorName = displayName
+	}
+
+	p.UserID = userID
+	p.RepostID = &repostID
+	p.Likes = 0
+	p.ReplyCount = 0
+	p.Replies = []models.Post{}
+
+	reposts := []models.Post{p}
+	s.attachReposts(reposts, userID)
+	p = reposts[0]
+
+	if s.hold(&p, filtered) {
+		return p, nil
+	}
+
+	if original.UserID != userID {
+		// Plain reposts point at the original so they group per post; a
+		// quote points at the quote itself.
+		kind, target := "repost", original.ID
+		if texto != "" {
+			kind, target = "quote", p.ID
+		}
+		s.notif.CreateNotification(original.UserID, &userID, kind, &target)
+	}
+	if texto != "" {
+		s.processMentions(texto, "", userID, p.ID)
+		s.indexTags(p.ID, texto, "")
+		s.redis.DelPattern("search:posts:*")
+	}
+
+	s.invalidateRepost(userID, repostID, original.UserID)
+	return p, nil
+}
+
+// UndoRepost removes userID's plain repost of repostID.
+func (s *socialService) UndoRepost(userID, repostID int) (map[string]interface{}, error) {
+	deletedID, err := s.repo.UndoRepost(userID, repostID)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("você não repostou este post")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The original may be gone or hidden by now; its author's profile is
+	// only evicted when it can still be loaded.
+	original, _ := s.repo.Thread(repostID, userID)
+	s.invalidateRepost(userID, repostID, original.UserID)
+	return map[string]interface{}{
+		"post_id":      repostID,
+		"deleted_id":   deletedID,
+		"repost_count": s.repo.RepostCount(repostID),
+	}, nil
+}
+
+// invalidateRepost drops what shows the original's repost_count and
+// reposted flag: its threads (and the ones above, when it is a reply), the
+// feed, and both the reposter's and the original author's profiles.
+func (s *socialService) invalidateRepost(userID, repostID, authorID int) {
+	s.evictThreads(repostID)
+	s.redis.DelPattern("social:feed:*")
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+	if authorID > 0 && authorID != userID {
+		s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", authorID))
+	}
+}
+
+// attachReposts embeds the original of every repost or quote. Originals the
+// viewer cannot see (deleted, hidden, blocked) leave RepostUnavailable set.
+func (s *socialService) attachReposts(posts []models.Post, userID int) {
+	var ids []int
+	for _, p := range posts {
+		if p.RepostID != nil {
+			ids = append(ids, *p.RepostID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	byID, err := s.repo.PostsByID(ids, userID)
+	if err != nil {
+		return
+	}
+
+	// PostsByID already filters what the viewer cannot see.
+	originals := make([]models.Post, 0, len(byID))
+	for _, o := range byID {
+		originals = append(originals, o)
+	}
+	s.attachMedia(originals)
+	s.attachPolls(originals, userID)
+
+	loaded := make(map[int]*models.Post, len(originals))
+	for i := range originals {
+		loaded[originals[i].ID] = &originals[i]
+	}
+	for i := range posts {
+		if posts[i].RepostID == nil {
+			continue
+		}
+		if o, ok := loaded[*posts[i].RepostID]; ok {
+			posts[i].Repost = o
+		} else {
+			posts[i].RepostUnavailable = true
+		}
+	}
+}
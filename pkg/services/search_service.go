@@ -0,0 +1,101 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/cache"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	searchMinQuery = 2
+	searchMaxQuery = 100
+)
+
+type SearchService interface {
+	Search(q, kind string, limit int, cursor string, userID int) (models.Page[models.SearchHit], error)
+}
+
+type searchService struct {
+	social   repository.SocialRepository
+	noticias repository.NoticiasRepository
+	redis    *cache.Redis
+}
+
+func NewSearchService(social repository.SocialRepository, noticias repository.NoticiasRepository, redis *cache.Redis) SearchService {
+	return &searchService{social: social, noticias: noticias, redis: redis}
+}
+
+// Search runs q against one result type. Results are ranked, not ordered by
+// date, so the cursor is an opaque offset rather than a keyset position.
+// Cached pages live under search:{type}:* and are evicted by the services that
+// write posts, profiles and notícias.
+func (s *searchService) Search(q, kind string, limit int, cursor string, userID int) (models.Page[models.SearchHit], error) {
+	q = strings.Join(strings.Fields(q), " ")
+	if n := utf8.RuneCountInString(q); n < searchMinQuery || n > searchMaxQuery {
+		return models.Page[models.SearchHit]{}, apperror.Validation(fmt.Sprintf("a busca deve ter entre %d e %d caracteres", searchMinQuery, searchMaxQuery))
+	}
+	if kind == "" {
+		kind = models.SearchPosts
+	}
+	if limit <= 0 || limit > 50 {
+		limit = 20
+	}
+	offset := 0
+	if cursor != "" {
+		var err error
+		if offset, err = strconv.Atoi(cursor); err != nil || offset < 0 {
+			return models.Page[models.SearchHit]{}, apperror.Validation("cursor inválido")
+		}
+	}
+
+	sum := sha1.Sum([]byte(strings.ToLower(q)))
+	cacheKey := fmt.Sprintf("search:%s:%s:%d:%d", kind, hex.EncodeToString(sum[:8]), limit, offset)
+	if kind == models.SearchPosts {
+		cacheKey += fmt.Sprintf(":lid%d", userID)
+	}
+	var cached models.Page[models.SearchHit]
+	if s.redis.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	var hits []models.SearchHit
+	var err error
+	switch kind {
+	case models.SearchPosts:
+		hits, err = s.social.SearchPosts(q, userID, limit+1, offset)
+	case models.SearchUsers:
+		hits, err = s.social.SearchUsers(q, limit+1, offset)
+	case models.SearchNoticias:
+		hits, err = s.noticias.Buscar(q, limit+1, offset)
+	default:
+		return models.Page[models.SearchHit]{}, apperror.Validation("type deve ser posts, users ou noticias")
+	}
+	if err != nil {
+		return models.Page[models.SearchHit]{}, err
+	}
+
+	page := models.Page[models.SearchHit]{Items: hits}
+	if len(hits) > limit {
+		page.Items = hits[:limit]
+		page.NextCursor = strconv.Itoa(offset + limit)
+	}
+	if page.Items == nil {
+		page.Items = []models.SearchHit{}
+	}
+
+	terms := searchTerms(q)
+	for i := range page.Items {
+		page.Items[i].Snippet = highlight(page.Items[i].Snippet, terms)
+	}
+
+	s.redis.Set(cacheKey, page, 30*time.Second)
+	return page, nil
+}
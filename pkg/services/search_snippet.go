@@ -0,0 +1,129 @@
+package services
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const snippetLen = 180 // runes shown around the first match
+
+var accentFold = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n',
+}
+
+// foldRune lowercases and strips the accent so "Ação" matches "acao". It maps
+// rune to rune, keeping positions in the folded text aligned with the original.
+func foldRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if f, ok := accentFold[r]; ok {
+		return f
+	}
+	return r
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// searchTerms splits a query into folded words, ignoring one-letter words and
+// websearch syntax (quotes, "-", "or").
+func searchTerms(q string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, w := range strings.FieldsFunc(q, func(r rune) bool { return !isWordRune(r) }) {
+		rs := []rune(w)
+		for i := range rs {
+			rs[i] = foldRune(rs[i])
+		}
+		t := string(rs)
+		if len(rs) < 2 || t == "or" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, t)
+	}
+	return terms
+}
+
+// highlight returns an HTML-safe excerpt of text around the first match, with
+// every word starting with one of terms wrapped in <mark>. Prefix matching
+// approximates the stemming done by the full-text index ("curso" → "cursos").
+// Without a match it returns the start of the text.
+func highlight(text string, terms []string) string {
+	runes := []rune(strings.Join(strings.Fields(text), " "))
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = foldRune(r)
+	}
+
+	// marks[i] > 0 means a highlight of that many runes starts at i.
+	marks := make([]int, len(runes))
+	first := -1
+	for i := range folded {
+		if i > 0 && isWordRune(folded[i-1]) {
+			continue
+		}
+		for _, t := range terms {
+			tr := []rune(t)
+			if len(tr) <= len(folded)-i && string(folded[i:i+len(tr)]) == t {
+				end := i + len(tr)
+				for end < len(folded) && isWordRune(folded[end]) {
+					end++
+				}
+				marks[i] = end - i
+				if first < 0 {
+					first = i
+				}
+				break
+			}
+		}
+	}
+
+	start := 0
+	if first > snippetLen/4 {
+		start = first - snippetLen/4
+		for start < first && runes[start] != ' ' {
+			start++
+		}
+	}
+	end := min(start+snippetLen, len(runes))
+	if end < len(runes) {
+		for end > start && runes[end] != ' ' {
+			end--
+		}
+		if end == start {
+			end = min(start+snippetLen, len(runes))
+		}
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	for i := start; i < end; {
+		if n := marks[i]; n > 0 {
+			stop := min(i+n, end)
+			b.WriteString("<mark>")
+			b.WriteString(html.EscapeString(string(runes[i:stop])))
+			b.WriteString("</mark>")
+			i = stop
+			continue
+		}
+		j := i + 1
+		for j < end && marks[j] == 0 {
+			j++
+		}
+		b.WriteString(html.EscapeString(string(runes[i:j])))
+		i = j
+	}
+	if end < len(runes) {
+		b.WriteString("…")
+	}
+	return strings.TrimSpace(b.String())
+}
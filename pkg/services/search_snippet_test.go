@@ -0,0 +1,60 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchTerms(t *testing.T) {
+	got := searchTerms(`"Reunião" do CA -festa or Festa a`)
+	want := []string{"reuniao", "do", "ca", "festa"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("searchTerms = %v, want %v", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		name, text, q, want string
+	}{
+		{"accent and case", "Reunião do CA amanhã", "reuniao", "<mark>Reunião</mark> do CA amanhã"},
+		{"prefix of word", "Abertas as inscrições dos cursos", "curso", "Abertas as inscrições dos <mark>cursos</mark>"},
+		{"only word starts", "discurso longo", "curso", "discurso longo"},
+		{"escapes html", "<b>prova</b> & trabalho", "prova", "&lt;b&gt;<mark>prova</mark>&lt;/b&gt; &amp; trabalho"},
+	}
+	for _, tc := range cases {
+		if got := highlight(tc.text, searchTerms(tc.q)); got != tc.want {
+			t.Errorf("%s: highlight = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestHighlightWindowsAroundFirstMatch(t *testing.T) {
+	text := strings.Repeat("palavra ", 60) + "calouros " + strings.Repeat("fim ", 60)
+	got := highlight(text, searchTerms("calouro"))
+
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Fatalf("expected ellipsis on both sides, got %q", got)
+	}
+	if !strings.Contains(got, "<mark>calouros</mark>") {
+		t.Fatalf("match missing from snippet: %q", got)
+	}
+	if n := len([]rune(got)); n > snippetLen+len("<mark></mark>")+2 {
+		t.Fatalf("snippet too long (%d runes)", n)
+	}
+}
+
+func TestTextoPlano(t *testing.T) {
+	conteudo := `{"blocks":[
+		{"type":"header","data":{"text":"Semana <i>acadêmica</i>"}},
+		{"type":"paragraph","data":{"text":"Inscrições &amp; horários"}},
+		{"type":"list","data":{"items":["Palestras",{"content":"Oficinas"}]}}
+	]}`
+	want := "Semana acadêmica\nInscrições & horários\nPalestras\nOficinas"
+	if got := textoPlano(conteudo); got != want {
+		t.Fatalf("textoPlano = %q, want %q", got, want)
+	}
+	if got := textoPlano("texto <b>simples</b>"); got != "texto simples" {
+		t.Fatalf("textoPlano(plain) = %q", got)
+	}
+}
@@ -1,74 +1,188 @@
 package services
 
 import (
+	"cacc/pkg/apperror"
 	"cacc/pkg/cache"
 	"cacc/pkg/models"
 	"cacc/pkg/repository"
 	"database/sql"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 )
 
+// Feed modes accepted by SocialService.Feed.
+const (
+	FeedModeGlobal    = "global"
+	FeedModeFollowing = "following"
+)
+
+// profilePageSize is how many posts Profile embeds; the rest is fetched with
+// ProfilePosts.
+const profilePageSize = 30
+
 type SocialService interface {
-	Feed(limit, offset, userID int) ([]models.Post, error)
+	Feed(mode string, limit int, cursor string, userID int) (models.Page[models.Post], error)
 	Thread(postID, userID int) (models.Post, error)
+	// Replies pages through the direct replies of any post in a thread.
+	Replies(postID, userID, limit int, cursor string) (models.Page[models.Post], error)
 	Profile(username string, profileUserID, requestingUserID int) (models.Profile, error)
+	ProfilePosts(username string, requestingUserID, limit int, cursor string) (models.Page[models.Post], error)
+	TagTimeline(tag string, limit int, cursor string, userID int) (models.Page[models.Post], error)
+	Trending(limit int) []models.TrendingTag
 	UpdateProfile(userID int, displayName, bio, avatarURL string) error
-	CreatePost(texto, username string, userID int) (models.Post, error)
-	CreateReply(texto, username string, userID, parentID int) (models.Post, error)
-	CreateRepost(userID, repostID int) (models.Post, error)
+	CreatePost(texto, username string, userID int, files []MediaFile, poll *models.PollRequest) (models.Post, error)
+	CreateReply(texto, username string, userID, parentID int, files []MediaFile, poll *models.PollRequest) (models.Post, error)
+	CreateRepost(userID, repostID int, texto string) (models.Post, error)
+	UndoRepost(userID, repostID int) (map[string]interface{}, error)
+	PinPost(userID, postID int) (map[string]interface{}, error)
+	UnpinPost(userID, postID int) (map[string]interface{}, error)
+	PinAnnouncement(adminID, postID int, duration string) (models.Post, error)
+	UnpinAnnouncement(postID int) error
+	Announcements(userID int) ([]models.Post, error)
+	// ResolvePreview fetches the preview of the first link in texto; nil
+	// when there is no link or no usable metadata.
+	ResolvePreview(postID, authorID int, texto string) (*models.LinkPreview, error)
 	Like(userID, postID int) (map[string]interface{}, error)
 	Unlike(userID, postID int) (map[string]interface{}, error)
+	// React and Unreact take an emoji from models.Reactions or its alias
+	// (like, love, haha, wow, sad, party).
+	React(userID, postID int, emoji string) (map[string]interface{}, error)
+	Unreact(userID, postID int, emoji string) (map[string]interface{}, error)
+	Reactors(postID, userID int, emoji string, limit int, cursor string) (models.Page[models.Reactor], error)
+	Likers(postID, userID, limit int, cursor string) (models.Page[models.UserSummary], error)
 	Delete(userID, postID int) error
+	Edit(userID, postID int, texto string) (models.Post, error)
+	History(postID, userID int) ([]models.PostRevision, error)
+	Poll(postID, userID int) (models.Poll, error)
+	Vote(userID, postID int, optionIDs []int) (models.Poll, error)
+
+	Bookmark(userID, postID int) (map[string]interface{}, error)
+	Unbookmark(userID, postID int) (map[string]interface{}, error)
+	Bookmarks(userID, limit int, cursor string) (models.Page[models.Post], error)
+
+	Block(userID int, username string) (map[string]interface{}, error)
+	Unblock(userID int, username string) (map[string]interface{}, error)
+	Mute(userID int, username string) (map[string]interface{}, error)
+	Unmute(userID int, username string) (map[string]interface{}, error)
+	Blocks(userID, limit, offset int) ([]models.UserSummary, error)
+	Mutes(userID, limit, offset int) ([]models.UserSummary, error)
+	// BlockedWith reports whether a block in either direction stands
+	// between authorID and userID; never for anonymous viewers.
+	BlockedWith(authorID, userID int) bool
+
+	Follow(followerID int, username string) (map[string]interface{}, error)
+	Unfollow(followerID int, username string) (map[string]interface{}, error)
+	Followers(username string, limit, offset int) ([]models.UserSummary, error)
+	Following(username string, limit, offset int) ([]models.UserSummary, error)
 }
 
 type socialService struct {
-	repo  repository.SocialRepository
-	auth  repository.AuthRepository // Used for finding users by username if needed for profile
-	notif repository.NotificationRepository
-	redis *cache.Redis
+	repo     repository.SocialRepository
+	auth     repository.AuthRepository // Used for finding users by username if needed for profile
+	notif    repository.NotificationRepository
+	media    MediaService
+	filter   ContentFilter
+	previews LinkPreviewService
+	redis    *cache.Redis
+
+	editWindow     time.Duration // 0 = posts can be edited forever
+	maxAttachments int
+}
+
+func NewSocialService(repo repository.SocialRepository, auth repository.AuthRepository, notif repository.NotificationRepository, media MediaService, filter ContentFilter, previews LinkPreviewService, redis *cache.Redis) SocialService {
+	return &socialService{
+		repo:           repo,
+		auth:           auth,
+		notif:          notif,
+		media:          media,
+		filter:         filter,
+		previews:       previews,
+		redis:          redis,
+		editWindow:     postEditWindow(),
+		maxAttachments: PostMaxAttachments(),
+	}
 }
 
-func NewSocialService(repo repository.SocialRepository, auth repository.AuthRepository, notif repository.NotificationRepository, redis *cache.Redis) SocialService {
-	return &socialService{repo: repo, auth: auth, notif: notif, redis: redis}
+// postEditWindow reads POST_EDIT_WINDOW (Go duration, e.g. "15m", "1h";
+// "0" removes the limit). Defaults to 15 minutes.
+func postEditWindow() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("POST_EDIT_WINDOW")); err == nil && d >= 0 {
+		return d
+	}
+	return 15 * time.Minute
 }
 
-func (s *socialService) Feed(limit, offset, userID int) ([]models.Post, error) {
+func (s *socialService) Feed(mode string, limit int, cursor string, userID int) (models.Page[models.Post], error) {
 	if limit <= 0 || limit > 100 {
 		limit = 30
 	}
-	if offset < 0 {
-		offset = 0
+	if mode != FeedModeFollowing {
+		mode = FeedModeGlobal
+	}
+	if mode == FeedModeFollowing && userID <= 0 {
+		return models.Page[models.Post]{}, apperror.Unauthorized("faça login para ver quem você segue")
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.Validation("cursor inválido")
 	}
 
-	cacheKey := fmt.Sprintf("social:feed:%d:%d:lid%d", limit, offset, userID)
-	var cached []models.Post
+	// The first page is keyed as "head" so a new post only has to evict
+	// social:feed:* once; older pages keep their cursor in the key.
+	pageKey := cursor
+	if pageKey == "" {
+		pageKey = "head"
+	}
+	cacheKey := fmt.Sprintf("social:feed:%s:%d:%s:lid%d", mode, limit, pageKey, userID)
+	var cached models.Page[models.Post]
 	if s.redis.Get(cacheKey, &cached) {
 		return cached, nil
 	}
 
-	posts, err := s.repo.Feed(userID, limit, offset)
+	posts, err := s.repo.Feed(userID, limit+1, after, mode == FeedModeFollowing)
 	if err != nil {
-		return nil, err
+		return models.Page[models.Post]{}, err
 	}
 
-	if len(posts) > 0 {
-		var postIDs []int
-		for _, p := range posts {
-			postIDs = append(postIDs, p.ID)
-		}
+	page := models.NewPage(posts, limit, models.PostCursor)
+	s.attachReplies(page.Items, userID)
+	s.hydratePosts(page.Items, userID)
+	if cursor == "" {
+		page.Items = s.withAnnouncements(page.Items, userID)
+	}
 
-		repliesMap, _ := s.repo.BatchLoadReplies(postIDs, userID)
-		for i := range posts {
-			if replies, ok := repliesMap[posts[i].ID]; ok {
-				posts[i].Replies = replies
-			}
+	s.redis.Set(cacheKey, page, 15*time.Second)
+	return page, nil
+}
+
+// attachReplies fills Replies for a page of root posts with one batch query.
+func (s *socialService) attachReplies(posts []models.Post, userID int) {
+	if len(posts) == 0 {
+		return
+	}
+	ids := make([]int, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	repliesMap, _ := s.repo.BatchLoadReplies(ids, userID)
+	for i := range posts {
+		if replies, ok := repliesMap[posts[i].ID]; ok {
+			posts[i].Replies = replies
 		}
 	}
+}
 
-	s.redis.Set(cacheKey, posts, 15*time.Second)
-	return posts, nil
+// hydratePosts loads what lives outside the posts table (reposted originals,
+// attachments, polls, link previews) for posts and their already loaded
+// replies.
+func (s *socialService) hydratePosts(posts []models.Post, userID int) {
+	s.attachReposts(posts, userID)
+	s.attachMedia(posts)
+	s.attachPolls(posts, userID)
+	s.attachReactions(posts, userID)
+	s.attachPreviews(posts)
 }
 
 func (s *socialService) Thread(postID, userID int) (models.Post, error) {
@@ -82,36 +196,19 @@ func (s *socialService) Thread(postID, userID int) (models.Post, error) {
 	if err != nil {
 		return models.Post{}, err
 	}
+	if s.blockedWith(p.UserID, userID) {
+		return models.Post{}, apperror.NotFound("post não encontrado")
+	}
 
-	p.Replies = s.loadRepliesRecursive(p.ID, 5, userID)
+	s.loadReplyTree(&p, userID)
+	thread := []models.Post{p}
+	s.hydratePosts(thread, userID)
+	p = thread[0]
 
 	s.redis.Set(cacheKey, p, 30*time.Second)
 	return p, nil
 }
 
-func (s *socialService) loadRepliesRecursive(parentID, maxDepth, userID int) []models.Post {
-	if maxDepth <= 0 {
-		return []models.Post{}
-	}
-
-	replies, err := s.repo.Replies(parentID, userID, 50)
-	if err != nil {
-		return []models.Post{}
-	}
-
-	for i := range replies {
-		pid := parentID
-		replies[i].ParentID = &pid
-		if replies[i].ReplyCount > 0 {
-			replies[i].Replies = s.loadRepliesRecursive(replies[i].ID, maxDepth-1, userID)
-		} else {
-			replies[i].Replies = []models.Post{}
-		}
-	}
-
-	return replies
-}
-
 func (s *socialService) Profile(username string, profileUserID, requestingUserID int) (models.Profile, error) {
 	userID := profileUserID
 
@@ -134,25 +231,20 @@ func (s *socialService) Profile(username string, profileUserID, requestingUserID
 	}
 
 	totalPosts, totalLikes := s.repo.ProfileStats(userID)
-	posts, err := s.repo.ProfilePosts(userID, requestingUserID, 100)
+	posts, err := s.repo.ProfilePosts(userID, requestingUserID, profilePageSize+1, nil)
 	if err != nil {
 		return models.Profile{}, fmt.Errorf("erro ao buscar perfil")
 	}
-
-	if len(posts) > 0 {
-		ids := make([]int, len(posts))
-		for i, p := range posts {
-			ids[i] = p.ID
-		}
-		repliesMap, _ := s.repo.BatchLoadReplies(ids, requestingUserID)
-		for i := range posts {
-			if replies, ok := repliesMap[posts[i].ID]; ok {
-				posts[i].Replies = replies
-			}
-		}
+	page := models.NewPage(posts, profilePageSize, models.PostCursor)
+	pinned := s.pinnedPost(userID, requestingUserID)
+	if pinned != nil {
+		page.Items = withoutPost(page.Items, pinned.ID)
 	}
+	s.attachReplies(page.Items, requestingUserID)
+	s.hydratePosts(page.Items, requestingUserID)
 
 	un, displayName, bio, avatarURL, _ := s.repo.ProfileInfo(userID)
+	followers, following := s.repo.FollowCounts(userID)
 
 	profile := models.Profile{
 		Username:    un,
@@ -161,13 +253,56 @@ func (s *socialService) Profile(username string, profileUserID, requestingUserID
 		AvatarURL:   avatarURL,
 		TotalPosts:  totalPosts,
 		TotalLikes:  totalLikes,
-		Posts:       posts,
+		Followers:   followers,
+		Following:   following,
+		IsFollowing: requestingUserID != userID && s.repo.IsFollowing(requestingUserID, userID),
+		PinnedPost:  pinned,
+		Posts:       page.Items,
+		NextCursor:  page.NextCursor,
 	}
 
 	s.redis.Set(cacheKey, profile, 30*time.Second)
 	return profile, nil
 }
 
+// ProfilePosts continues a profile's post list from the NextCursor returned
+// by Profile. Pages share the social:profile:{id}:* prefix so profile
+// invalidation drops them too.
+func (s *socialService) ProfilePosts(username string, requestingUserID, limit int, cursor string) (models.Page[models.Post], error) {
+	if limit <= 0 || limit > 100 {
+		limit = profilePageSize
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.Validation("cursor inválido")
+	}
+
+	user, _, err := s.auth.GetUserByUsername(strings.ToLower(username))
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.NotFound("usuário não encontrado")
+	}
+
+	cacheKey := fmt.Sprintf("social:profile:%d:posts:%d:%s:lid%d", user.ID, limit, cursor, requestingUserID)
+	var cached models.Page[models.Post]
+	if s.redis.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	posts, err := s.repo.ProfilePosts(user.ID, requestingUserID, limit+1, after)
+	if err != nil {
+		return models.Page[models.Post]{}, err
+	}
+	page := models.NewPage(posts, limit, models.PostCursor)
+	if pinnedID := s.repo.PinnedPostID(user.ID); pinnedID != 0 {
+		page.Items = withoutPost(page.Items, pinnedID)
+	}
+	s.attachReplies(page.Items, requestingUserID)
+	s.hydratePosts(page.Items, requestingUserID)
+
+	s.redis.Set(cacheKey, page, 30*time.Second)
+	return page, nil
+}
+
 func (s *socialService) UpdateProfile(userID int, displayName, bio, avatarURL string) error {
 	err := s.repo.UpdateProfile(userID, displayName, bio, avatarURL)
 	if err == nil {
@@ -177,15 +312,45 @@ func (s *socialService) UpdateProfile(userID int, displayName, bio, avatarURL st
 		// Also evict feed and thread caches since avatar/displayName appears in posts
 		s.redis.DelPattern("social:feed:*")
 		s.redis.DelPattern("social:thread:*")
+		s.redis.DelPattern("search:users:*")
+		s.redis.DelPattern("search:posts:*")
 	}
 	return err
 }
 
-func (s *socialService) CreatePost(texto, username string, userID int) (models.Post, error) {
-	p, err := s.repo.CreatePost(texto, username, userID)
+func (s *socialService) CreatePost(texto, username string, userID int, files []MediaFile, poll *models.PollRequest) (models.Post, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Post{}, err
+	}
+	filtered, err := s.filter.Check(FilterInput{Kind: "post", Source: UserSource(userID), Text: texto})
+	if err != nil {
+		return models.Post{}, err
+	}
+	texto = filtered.Text
+	if poll != nil {
+		normalized, err := normalizePoll(*poll, time.Now())
+		if err != nil {
+			return models.Post{}, err
+		}
+		poll = &normalized
+	}
+
+	uploaded, err := s.uploadAttachments(files)
 	if err != nil {
+		return models.Post{}, err
+	}
+
+	p, err := s.repo.CreatePost(texto, username, userID, filtered.Action == FilterHold)
+	if err != nil {
+		s.destroyAttachments(uploaded)
 		return p, err
 	}
+	if err := s.saveAttachments(&p, userID, uploaded); err != nil {
+		return models.Post{}, err
+	}
+	if err := s.savePoll(&p, userID, poll); err != nil {
+		return models.Post{}, err
+	}
 
 	_, displayName, _, avatar, _ := s.repo.ProfileInfo(userID)
 
@@ -202,21 +367,57 @@ func (s *socialService) CreatePost(texto, username string, userID int) (models.P
 	p.ReplyCount = 0
 	p.Replies = []models.Post{}
 
-	s.processMentions(texto, userID, p.ID)
+	if s.hold(&p, filtered) {
+		return p, nil
+	}
+
+	s.processMentions(texto, "", userID, p.ID)
+	s.indexTags(p.ID, texto, "")
 
 	s.redis.DelPattern("social:feed:*")
-	s.redis.Del(fmt.Sprintf("social:profile:%d", userID))
+	s.redis.DelPattern("search:posts:*")
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
 
 	return p, nil
 }
 
-func (s *socialService) CreateReply(texto, username string, userID, parentID int) (models.Post, error) {
-	reply, err := s.repo.CreateReply(texto, username, userID, parentID)
+func (s *socialService) CreateReply(texto, username string, userID, parentID int, files []MediaFile, poll *models.PollRequest) (models.Post, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Post{}, err
+	}
+	filtered, err := s.filter.Check(FilterInput{Kind: "reply", Source: UserSource(userID), Text: texto})
+	if err != nil {
+		return models.Post{}, err
+	}
+	texto = filtered.Text
+	if poll != nil {
+		normalized, err := normalizePoll(*poll, time.Now())
+		if err != nil {
+			return models.Post{}, err
+		}
+		poll = &normalized
+	}
+	if _, err := s.interactable(parentID, userID); err != nil {
+		return models.Post{}, err
+	}
+
+	uploaded, err := s.uploadAttachments(files)
+	if err != nil {
+		return models.Post{}, err
+	}
+
+	reply, err := s.repo.CreateReply(texto, username, userID, parentID, filtered.Action == FilterHold)
 	if err != nil {
+		s.destroyAttachments(uploaded)
 		return reply, err
 	}
+	if err := s.saveAttachments(&reply, userID, uploaded); err != nil {
+		return models.Post{}, err
+	}
+	if err := s.savePoll(&reply, userID, poll); err != nil {
+		return models.Post{}, err
+	}
 
-	s.repo.IncrementReplyCount(parentID)
 	_, displayName, _, avatar, _ := s.repo.ProfileInfo(userID)
 
 	reply.Texto = texto
@@ -233,58 +434,76 @@ func (s *socialService) CreateReply(texto, username string, userID, parentID int
 	reply.ReplyCount = 0
 	reply.Replies = []models.Post{}
 
+	// A held reply only counts once moderation shows it (SetHidden).
+	if s.hold(&reply, filtered) {
+		return reply, nil
+	}
+	s.repo.IncrementReplyCount(parentID)
+
 	// Notify parent user
 	parentPost, _ := s.repo.Thread(parentID, userID)
 	if parentPost.ID != 0 && parentPost.UserID != userID {
 		s.notif.CreateNotification(parentPost.UserID, &userID, "reply", &reply.ID)
 	}
 
-	s.processMentions(texto, userID, reply.ID)
+	s.processMentions(texto, "", userID, reply.ID)
+	s.indexTags(reply.ID, texto, "")
 
-	s.redis.Del(fmt.Sprintf("social:thread:%d", parentID))
+	s.evictThreads(parentID)
 	s.redis.DelPattern("social:feed:*")
-	s.redis.Del(fmt.Sprintf("social:profile:%d", userID))
+	s.redis.DelPattern("search:posts:*")
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
 
 	return reply, nil
 }
 
-func (s *socialService) CreateRepost(userID, repostID int) (models.Post, error) {
-	p, err := s.repo.CreateRepost(userID, repostID)
-	if err != nil {
-		return p, err
+// hold puts a post the content filter flagged in the moderation queue. New
+// posts were already inserted hidden; an edited one is hidden here. It
+// reports whether the post was held, in which case mentions, notifications
+// and the new_post broadcast are skipped.
+func (s *socialService) hold(p *models.Post, res FilterResult) bool {
+	if res.Action != FilterHold {
+		return false
 	}
+	s.filter.Hold(models.ReportPost, p.ID, p.UserID, res)
+	p.Held = true
 
-	_, displayName, _, avatar, _ := s.repo.ProfileInfo(userID)
+	// An edit can hold a post that is already in cached pages.
+	s.redis.DelPattern("social:feed:*")
+	s.redis.DelPattern("social:thread:*")
+	s.redis.DelPattern("social:profile:*")
+	s.redis.DelPattern("social:tag:*")
+	s.redis.DelPattern("search:posts:*")
+	return true
+}
 
-	p.Author = "Você"
-	p.AuthorName = "Você"
-	p.AvatarURL = avatar
-	if displayName != "" {
-		p.AuthorName = displayName
+// ensureActive refuses writes from a suspended user. The social and messages
+// services both go through it.
+func ensureActive(auth repository.AuthRepository, userID int) error {
+	if until := auth.SuspendedUntil(userID); until != nil {
+		return apperror.Forbidden("sua conta está suspensa até " + until.Format("02/01/2006 15:04"))
 	}
+	return nil
+}
 
-	p.UserID = userID
-	p.RepostID = &repostID
-	p.Likes = 0
-	p.ReplyCount = 0
-	p.Replies = []models.Post{}
-
-	originalPost, _ := s.repo.Thread(repostID, userID)
-	if originalPost.ID != 0 && originalPost.UserID != userID {
-		s.notif.CreateNotification(originalPost.UserID, &userID, "repost", &p.ID)
+// processMentions notifies users @mentioned in texto. On edits, previous is
+// the old text and handles already mentioned there are not notified again.
+func (s *socialService) processMentions(texto, previous string, actorID int, postID int) {
+	already := map[string]bool{}
+	for _, w := range strings.Fields(previous) {
+		if strings.HasPrefix(w, "@") && len(w) > 1 {
+			already[strings.TrimPrefix(w, "@")] = true
+		}
 	}
 
-	s.redis.DelPattern("social:feed:*")
-	s.redis.Del(fmt.Sprintf("social:profile:%d", userID))
-
-	return p, nil
-}
-
-func (s *socialService) processMentions(texto string, actorID int, postID int) {
 	words := strings.Fields(texto)
 	for _, w := range words {
 		if strings.HasPrefix(w, "@") && len(w) > 1 {
 			username := strings.TrimPrefix(w, "@")
+			if already[username] {
+				continue
+			}
+			already[username] = true
 			user, _, err := s.auth.GetUserByUsername(username)
 			if err == nil && user.ID != 0 && user.ID != actorID {
 				s.notif.CreateNotification(user.ID, &actorID, "mention", &postID)
@@ -293,64 +512,245 @@ func (s *socialService) processMentions(texto string, actorID int, postID int) {
 	}
 }
 
-func (s *socialService) toggleLike(userID, postID int, isLike bool) (map[string]interface{}, error) {
-	var success bool
-	var err error
+func (s *socialService) Delete(userID, postID int) error {
+	// Collected before the delete: the rows go away with the post (and its
+	// replies) through ON DELETE CASCADE, the Cloudinary files do not.
+	attachments, _ := s.repo.ThreadAttachments(postID)
+	ancestors, _ := s.repo.AncestorIDs(postID)
 
-	if isLike {
-		success, err = s.repo.LikePost(userID, postID)
-	} else {
-		success, err = s.repo.UnlikePost(userID, postID)
+	_, err := s.repo.DeletePost(postID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("post não encontrado ou sem permissão")
+		}
+		return err
+	}
+	go s.destroyAttachments(attachments)
+
+	s.redis.DelPattern("social:feed:*")
+	s.redis.DelPattern("search:posts:*")
+	s.redis.DelPattern("social:tag:*")
+	s.evictThreadIDs(append(ancestors, postID))
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+
+	return nil
+}
+
+// Edit replaces the text of the caller's post within editWindow. The old text
+// goes to post_revisions; likes and replies are kept.
+func (s *socialService) Edit(userID, postID int, texto string) (models.Post, error) {
+	if err := ensureActive(s.auth, userID); err != nil {
+		return models.Post{}, err
+	}
+	p, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows {
+		return models.Post{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Post{}, err
+	}
+	if p.UserID != userID {
+		return models.Post{}, apperror.Forbidden("só o autor pode editar este post")
+	}
+	if p.RepostID != nil && p.Texto == "" {
+		return models.Post{}, apperror.Validation("reposts não podem ser editados")
+	}
+	if s.editWindow > 0 && time.Since(p.CreatedAt) > s.editWindow {
+		return models.Post{}, apperror.Forbidden(fmt.Sprintf("o prazo para editar (%s) já passou", formatWindow(s.editWindow)))
+	}
+	filtered, err := s.filter.Check(FilterInput{Kind: "post", Source: UserSource(userID), Text: texto})
+	if err != nil {
+		return models.Post{}, err
+	}
+	texto = filtered.Text
+	if texto == p.Texto {
+		return p, nil
 	}
 
-	if err != nil && err != sql.ErrNoRows {
-		return nil, fmt.Errorf("erro interno na db")
+	editedAt, err := s.repo.EditPost(postID, userID, texto)
+	if err != nil {
+		return models.Post{}, err
 	}
+	previous := p.Texto
+	p.Texto = texto
+	p.EditedAt = &editedAt
 
-	var newLikes int
-	if success {
-		if isLike {
-			newLikes, _ = s.repo.IncLikeCount(postID)
-			post, err := s.repo.Thread(postID, userID)
-			if err == nil && post.UserID != userID {
-				s.notif.CreateNotification(post.UserID, &userID, "like", &postID)
-			}
-		} else {
-			newLikes, err = s.repo.DecLikeCount(postID)
-		}
-		if err != nil {
-			return nil, fmt.Errorf("post não encontrado")
+	if s.hold(&p, filtered) {
+		return p, nil
+	}
+
+	s.processMentions(texto, previous, userID, postID)
+	s.indexTags(postID, texto, previous)
+
+	s.evictThreads(postID)
+	s.redis.DelPattern("social:feed:*")
+	s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", userID))
+	s.redis.DelPattern("search:posts:*")
+
+	return p, nil
+}
+
+// History lists every version of a post, the live text first.
+func (s *socialService) History(postID, userID int) ([]models.PostRevision, error) {
+	p, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows {
+		return nil, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return nil, err
+	}
+	revisions, err := s.repo.PostRevisions(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	since := p.CreatedAt
+	if p.EditedAt != nil {
+		since = *p.EditedAt
+	}
+	return append([]models.PostRevision{{Texto: p.Texto, CreatedAt: since, Current: true}}, revisions...), nil
+}
+
+func formatWindow(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%d min", int(d.Minutes()))
+}
+
+// ─── Bookmarks ───────────────────────────────────────────────────────────────
+
+func (s *socialService) Bookmark(userID, postID int) (map[string]interface{}, error) {
+	return s.toggleBookmark(userID, postID, true)
+}
+
+func (s *socialService) Unbookmark(userID, postID int) (map[string]interface{}, error) {
+	return s.toggleBookmark(userID, postID, false)
+}
+
+func (s *socialService) toggleBookmark(userID, postID int, save bool) (map[string]interface{}, error) {
+	if save {
+		if _, err := s.interactable(postID, userID); err != nil {
+			return nil, err
 		}
+	}
+
+	var changed bool
+	var err error
+	if save {
+		changed, err = s.repo.Bookmark(userID, postID)
 	} else {
-		newLikes, _ = s.repo.GetLikeCount(postID)
+		changed, err = s.repo.Unbookmark(userID, postID)
+	}
+	if err != nil {
+		return nil, apperror.Internal("erro interno na db")
 	}
 
-	s.redis.Del(fmt.Sprintf("social:thread:%d", postID))
-	s.redis.DelPattern("social:feed:*")
+	// The flag is only part of this user's cached pages.
+	if changed {
+		s.redis.DelPattern(fmt.Sprintf("social:*:lid%d", userID))
+	}
+
+	return map[string]interface{}{"post_id": postID, "bookmarked": save}, nil
+}
+
+// Bookmarks is the caller's private list of saved posts, newest save first.
+// It is not cached: it only serves one user and changes on every toggle.
+func (s *socialService) Bookmarks(userID, limit int, cursor string) (models.Page[models.Post], error) {
+	if limit <= 0 || limit > 100 {
+		limit = 30
+	}
+	after, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.Validation("cursor inválido")
+	}
 
-	return map[string]interface{}{"post_id": postID, "likes": newLikes}, nil
+	posts, err := s.repo.Bookmarks(userID, limit+1, after)
+	if err != nil {
+		return models.Page[models.Post]{}, err
+	}
+	page := models.NewPage(posts, limit, func(p models.Post) models.Cursor {
+		return models.Cursor{CreatedAt: *p.BookmarkedAt, ID: p.ID}
+	})
+	s.hydratePosts(page.Items, userID)
+	return page, nil
 }
 
-func (s *socialService) Like(userID, postID int) (map[string]interface{}, error) {
-	return s.toggleLike(userID, postID, true)
+// ─── Follow graph ────────────────────────────────────────────────────────────
+
+func (s *socialService) Follow(followerID int, username string) (map[string]interface{}, error) {
+	return s.toggleFollow(followerID, username, true)
 }
 
-func (s *socialService) Unlike(userID, postID int) (map[string]interface{}, error) {
-	return s.toggleLike(userID, postID, false)
+func (s *socialService) Unfollow(followerID int, username string) (map[string]interface{}, error) {
+	return s.toggleFollow(followerID, username, false)
 }
 
-func (s *socialService) Delete(userID, postID int) error {
-	_, err := s.repo.DeletePost(postID, userID)
+func (s *socialService) toggleFollow(followerID int, username string, follow bool) (map[string]interface{}, error) {
+	target, _, err := s.auth.GetUserByUsername(strings.ToLower(username))
+	if err != nil || target.ID == 0 {
+		return nil, apperror.NotFound("usuário não encontrado")
+	}
+	if target.ID == followerID {
+		return nil, apperror.Validation("você não pode seguir a si mesmo")
+	}
+	if follow && s.blockedWith(target.ID, followerID) {
+		return nil, apperror.NotFound("usuário não encontrado")
+	}
+
+	var changed bool
+	if follow {
+		changed, err = s.repo.Follow(followerID, target.ID)
+	} else {
+		changed, err = s.repo.Unfollow(followerID, target.ID)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return fmt.Errorf("post não encontrado ou sem permissão")
+		return nil, apperror.Internal("erro interno na db")
+	}
+
+	if changed {
+		if follow {
+			s.notif.CreateNotification(target.ID, &followerID, "follow", nil)
 		}
-		return err
+		s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", target.ID))
+		s.redis.DelPattern(fmt.Sprintf("social:profile:%d:*", followerID))
+		s.redis.DelPattern(fmt.Sprintf("social:feed:%s:*:lid%d", FeedModeFollowing, followerID))
 	}
 
-	s.redis.DelPattern("social:feed:*")
-	s.redis.Del(fmt.Sprintf("social:thread:%d", postID))
-	s.redis.Del(fmt.Sprintf("social:profile:%d", userID))
+	followers, _ := s.repo.FollowCounts(target.ID)
+	return map[string]interface{}{
+		"user_id":     target.ID,
+		"username":    target.Username,
+		"following":   follow,
+		"followers":   followers,
+		"follower_id": followerID,
+	}, nil
+}
 
-	return nil
+func (s *socialService) Followers(username string, limit, offset int) ([]models.UserSummary, error) {
+	return s.followList(username, limit, offset, s.repo.Followers)
+}
+
+func (s *socialService) Following(username string, limit, offset int) ([]models.UserSummary, error) {
+	return s.followList(username, limit, offset, s.repo.Following)
+}
+
+func (s *socialService) followList(username string, limit, offset int, load func(userID, limit, offset int) ([]models.UserSummary, error)) ([]models.UserSummary, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	user, _, err := s.auth.GetUserByUsername(strings.ToLower(username))
+	if err != nil || user.ID == 0 {
+		return nil, apperror.NotFound("usuário não encontrado")
+	}
+
+	users, err := load(user.ID, limit, offset)
+	if err != nil {
+		return nil, apperror.Internal("erro ao carregar lista")
+	}
+	return users, nil
 }
@@ -0,0 +1,194 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+)
+
+func appErrorCode(err error) apperror.Code {
+	if ae, ok := err.(*apperror.AppError); ok {
+		return ae.Code
+	}
+	return 0
+}
+
+func TestEditChecksOwnerAndWindow(t *testing.T) {
+	repo := newFakeSocialRepo()
+	repo.posts[1] = models.Post{ID: 1, UserID: 10, Texto: "antes", CreatedAt: time.Now().Add(-5 * time.Minute)}
+	repo.posts[2] = models.Post{ID: 2, UserID: 10, Texto: "velho", CreatedAt: time.Now().Add(-time.Hour)}
+	auth := &fakeAuthRepo{suspended: map[int]time.Time{11: time.Now().Add(time.Hour)}}
+	s := &socialService{repo: repo, auth: auth, filter: passFilter{}, editWindow: 15 * time.Minute}
+
+	cases := []struct {
+		name   string
+		userID int
+		postID int
+		code   apperror.Code
+	}{
+		{"outro usuário", 20, 1, apperror.ErrForbidden},
+		{"fora do prazo", 10, 2, apperror.ErrForbidden},
+		{"post inexistente", 10, 99, apperror.ErrNotFound},
+		{"conta suspensa", 11, 1, apperror.ErrForbidden},
+	}
+	for _, tc := range cases {
+		if _, err := s.Edit(tc.userID, tc.postID, "depois"); appErrorCode(err) != tc.code {
+			t.Errorf("%s: esperado %d, obteve %v", tc.name, tc.code, err)
+		}
+	}
+	if len(repo.edits) != 0 {
+		t.Errorf("nenhuma edição deveria ter sido gravada, obteve %v", repo.edits)
+	}
+
+	// Sem prazo (POST_EDIT_WINDOW=0) o post antigo pode ser editado; texto
+	// igual não gera revisão.
+	s.editWindow = 0
+	p, err := s.Edit(10, 2, "velho")
+	if err != nil || p.EditedAt != nil || len(repo.edits) != 0 {
+		t.Errorf("texto igual não deveria editar: p=%+v err=%v edits=%v", p, err, repo.edits)
+	}
+}
+
+func TestHistoryListsCurrentFirst(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	edited := created.Add(10 * time.Minute)
+	repo := newFakeSocialRepo()
+	repo.posts[1] = models.Post{ID: 1, Texto: "v3", CreatedAt: created, EditedAt: &edited}
+	repo.posts[2] = models.Post{ID: 2, Texto: "único", CreatedAt: created}
+	repo.revisions[1] = []models.PostRevision{
+		{Texto: "v2", CreatedAt: created.Add(5 * time.Minute)},
+		{Texto: "v1", CreatedAt: created},
+	}
+	s := &socialService{repo: repo}
+
+	got, err := s.History(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.PostRevision{
+		{Texto: "v3", CreatedAt: edited, Current: true},
+		{Texto: "v2", CreatedAt: created.Add(5 * time.Minute)},
+		{Texto: "v1", CreatedAt: created},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("esperado %d versões, obteve %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("versão %d: esperado %+v, obteve %+v", i, want[i], got[i])
+		}
+	}
+
+	// Nunca editado: só a versão atual, desde a criação.
+	got, _ = s.History(2, 0)
+	if len(got) != 1 || !got[0].Current || !got[0].CreatedAt.Equal(created) {
+		t.Errorf("post sem edição: obteve %+v", got)
+	}
+
+	if _, err := s.History(99, 0); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("post inexistente: obteve %v", err)
+	}
+}
+
+func TestFollowRefusedAcrossBlock(t *testing.T) {
+	repo := newFakeSocialRepo()
+	repo.blocks[[2]int{2, 1}] = true
+	repo.follows[[2]int{1, 2}] = true
+	notif := &fakeNotifRepo{}
+	s := &socialService{
+		repo:  repo,
+		auth:  &fakeAuthRepo{users: map[string]models.User{"bia": {ID: 2, Username: "bia"}, "caio": {ID: 3, Username: "caio"}}},
+		notif: notif,
+		redis: testRedis(t),
+	}
+
+	// bia bloqueou ana (1): seguir some como se o perfil não existisse.
+	if _, err := s.Follow(1, "bia"); appErrorCode(err) != apperror.ErrNotFound {
+		t.Errorf("seguir quem bloqueou: esperado 404, obteve %v", err)
+	}
+	if len(notif.created) != 0 {
+		t.Errorf("bloqueio não deveria notificar, obteve %v", notif.created)
+	}
+
+	// Deixar de seguir continua possível depois do bloqueio.
+	if _, err := s.Unfollow(1, "bia"); err != nil || repo.follows[[2]int{1, 2}] {
+		t.Errorf("deixar de seguir: err=%v follows=%v", err, repo.follows)
+	}
+
+	if res, err := s.Follow(1, "caio"); err != nil || res["followers"] != 1 {
+		t.Errorf("seguir sem bloqueio: res=%v err=%v", res, err)
+	}
+	if len(notif.created) != 1 || notif.created[0] != "follow:3" {
+		t.Errorf("esperado um aviso de follow, obteve %v", notif.created)
+	}
+}
+
+func TestSuspendedUserCannotWrite(t *testing.T) {
+	repo := newFakeSocialRepo()
+	auth := &fakeAuthRepo{suspended: map[int]time.Time{10: time.Date(2030, 1, 2, 15, 4, 0, 0, time.UTC)}}
+	s := &socialService{repo: repo, auth: auth}
+
+	err := ensureActive(auth, 10)
+	if appErrorCode(err) != apperror.ErrForbidden || !strings.Contains(err.Error(), "02/01/2030") {
+		t.Errorf("conta suspensa: esperado 403 com a data, obteve %v", err)
+	}
+	if err := ensureActive(auth, 11); err != nil {
+		t.Errorf("conta ativa: %v", err)
+	}
+
+	// O bloqueio vem antes de qualquer filtro, upload ou INSERT.
+	if _, err := s.CreatePost("oi", "ana", 10, nil, nil); appErrorCode(err) != apperror.ErrForbidden {
+		t.Errorf("post de conta suspensa: esperado 403, obteve %v", err)
+	}
+	if _, err := s.CreateReply("oi", "ana", 10, 1, nil, nil); appErrorCode(err) != apperror.ErrForbidden {
+		t.Errorf("resposta de conta suspensa: esperado 403, obteve %v", err)
+	}
+}
+
+// holdFilter holds everything.
+type holdFilter struct{ passFilter }
+
+func (holdFilter) Check(in FilterInput) (FilterResult, error) {
+	return FilterResult{Text: in.Text, Action: FilterHold}, nil
+}
+
+// replyRepo records the rows CreateReply writes.
+type replyRepo struct {
+	*fakeSocialRepo
+	hidden  []bool
+	counted []int
+}
+
+func (r *replyRepo) CreateReply(texto, author string, userID, parentID int, hidden bool) (models.Post, error) {
+	r.hidden = append(r.hidden, hidden)
+	return models.Post{ID: 50}, nil
+}
+
+func (r *replyRepo) IncrementReplyCount(parentID int) error {
+	r.counted = append(r.counted, parentID)
+	return nil
+}
+
+func (r *replyRepo) ProfileInfo(userID int) (string, string, string, string, error) {
+	return "", "", "", "", nil
+}
+
+func TestHeldReplyDoesNotCount(t *testing.T) {
+	repo := &replyRepo{fakeSocialRepo: newFakeSocialRepo()}
+	repo.posts[1] = models.Post{ID: 1, UserID: 2}
+	s := &socialService{repo: repo, auth: &fakeAuthRepo{}, filter: holdFilter{}, redis: testRedis(t)}
+
+	reply, err := s.CreateReply("oi", "ana", 10, 1, nil, nil)
+	if err != nil || !reply.Held {
+		t.Fatalf("resposta deveria ser retida: %+v, %v", reply, err)
+	}
+	if len(repo.hidden) != 1 || !repo.hidden[0] {
+		t.Errorf("resposta retida deveria ser gravada oculta: %v", repo.hidden)
+	}
+	if len(repo.counted) != 0 {
+		t.Errorf("resposta retida não deveria contar no reply_count: %v", repo.counted)
+	}
+}
@@ -1,26 +1,32 @@
 package services
 
 import (
+	"cacc/pkg/apperror"
 	"cacc/pkg/cache"
 	"cacc/pkg/models"
 	"cacc/pkg/repository"
+	"database/sql"
 	"time"
 )
 
 type SugestoesService interface {
 	Listar() ([]models.Sugestao, error)
-	Criar(texto, author, categoria string) (models.Sugestao, error)
+	// source identifica quem envia para o filtro (UserSource ou IPSource).
+	Criar(texto, author, categoria, source string) (models.Sugestao, error)
 	Deletar(id int) error
 	Atualizar(id int, texto, categoria string) error
+	Pendentes() ([]models.Sugestao, error)
+	Aprovar(id int) error
 }
 
 type sugestoesService struct {
-	repo  repository.SugestoesRepository
-	redis *cache.Redis
+	repo   repository.SugestoesRepository
+	filter ContentFilter
+	redis  *cache.Redis
 }
 
-func NewSugestoesService(repo repository.SugestoesRepository, redis *cache.Redis) SugestoesService {
-	return &sugestoesService{repo: repo, redis: redis}
+func NewSugestoesService(repo repository.SugestoesRepository, filter ContentFilter, redis *cache.Redis) SugestoesService {
+	return &sugestoesService{repo: repo, filter: filter, redis: redis}
 }
 
 func (s *sugestoesService) Listar() ([]models.Sugestao, error) {
@@ -38,9 +44,21 @@ func (s *sugestoesService) Listar() ([]models.Sugestao, error) {
 	return lista, nil
 }
 
-func (s *sugestoesService) Criar(texto, author, categoria string) (models.Sugestao, error) {
-	sugestao, err := s.repo.Criar(texto, author, categoria)
-	if err == nil {
+func (s *sugestoesService) Criar(texto, author, categoria, source string) (models.Sugestao, error) {
+	res, err := s.filter.Check(FilterInput{Kind: "sugestao", Source: source, Text: texto})
+	if err != nil {
+		return models.Sugestao{}, err
+	}
+
+	// Sugestões vivem no MariaDB, fora da fila de denúncias: as retidas ficam
+	// pendentes até um admin aprovar.
+	var motivo string
+	if res.Action == FilterHold {
+		motivo = res.Rule + ": " + res.Reason
+	}
+
+	sugestao, err := s.repo.Criar(res.Text, author, categoria, motivo)
+	if err == nil && motivo == "" {
 		s.redis.Del("sugestoes:all")
 	}
 	return sugestao, err
@@ -61,3 +79,18 @@ func (s *sugestoesService) Atualizar(id int, texto, categoria string) error {
 	}
 	return err
 }
+
+func (s *sugestoesService) Pendentes() ([]models.Sugestao, error) {
+	return s.repo.Pendentes()
+}
+
+func (s *sugestoesService) Aprovar(id int) error {
+	err := s.repo.Aprovar(id)
+	if err == sql.ErrNoRows {
+		return apperror.NotFound("sugestão pendente não encontrada")
+	}
+	if err == nil {
+		s.redis.Del("sugestoes:all")
+	}
+	return err
+}
@@ -0,0 +1,455 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/cache"
+	"cacc/pkg/models"
+	"cacc/pkg/repository"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	syndicationItems    = 30
+	syndicationTitleLen = 80
+	syndicationTTL      = 5 * time.Minute
+)
+
+// SyndicationService renders the public feed, profiles and noticias as
+// Atom, RSS 2.0 or JSON Feed, always as an anonymous reader.
+type SyndicationService interface {
+	SocialFeed(format string) (models.SyndicationFeed, error)
+	ProfileFeed(username, format string) (models.SyndicationFeed, error)
+	NoticiasFeed(format string) (models.SyndicationFeed, error)
+}
+
+type syndicationService struct {
+	social   repository.SocialRepository
+	auth     repository.AuthRepository
+	noticias repository.NoticiasRepository
+	redis    *cache.Redis
+	appName  string
+	portal   string
+	api      string
+}
+
+func NewSyndicationService(social repository.SocialRepository, auth repository.AuthRepository, noticias repository.NoticiasRepository, redis *cache.Redis) SyndicationService {
+	return &syndicationService{
+		social:   social,
+		auth:     auth,
+		noticias: noticias,
+		redis:    redis,
+		appName:  envOrDefault("APP_NAME", "CACC Portal"),
+		portal:   strings.TrimRight(envOrDefault("FRONTEND_URL", "http://localhost:3000"), "/"),
+		api:      strings.TrimRight(envOrDefault("API_URL", "http://localhost:8082"), "/"),
+	}
+}
+
+// feedChannel and feedItem are the format-neutral feed the renderers share.
+type feedChannel struct {
+	Title       string
+	Description string
+	Link        string // página no portal
+	SelfURL     string // o próprio feed, no formato pedido
+	Items       []feedItem
+}
+
+type feedItem struct {
+	ID        string
+	Title     string
+	Link      string
+	Author    string
+	Summary   string
+	Content   string // texto puro; os renderers escapam
+	Published time.Time
+	Updated   time.Time
+}
+
+func validFeedFormat(format string) bool {
+	return format == models.FeedAtom || format == models.FeedRSS || format == models.FeedJSON
+}
+
+// Cached under social:feed:* and noticias:*, so the invalidation that
+// already runs on every post and noticia write refreshes them too.
+func (s *syndicationService) SocialFeed(format string) (models.SyndicationFeed, error) {
+	return s.cached("social:feed:syndication:"+format, format, func() (feedChannel, error) {
+		posts, err := s.social.Feed(0, syndicationItems, nil, false)
+		if err != nil {
+			return feedChannel{}, err
+		}
+		return feedChannel{
+			Title:       "Feed – " + s.appName,
+			Description: "Posts públicos do " + s.appName,
+			Link:        s.portal + "/social",
+			SelfURL:     s.api + "/social/feed." + format,
+			Items:       s.postItems(posts),
+		}, nil
+	})
+}
+
+func (s *syndicationService) ProfileFeed(username, format string) (models.SyndicationFeed, error) {
+	username = strings.ToLower(username)
+	return s.cached("social:feed:syndication:profile:"+username+":"+format, format, func() (feedChannel, error) {
+		user, _, err := s.auth.GetUserByUsername(username)
+		if err != nil {
+			return feedChannel{}, apperror.NotFound("usuário não encontrado")
+		}
+		_, displayName, bio, _, err := s.social.ProfileInfo(user.ID)
+		if err != nil {
+			return feedChannel{}, err
+		}
+		posts, err := s.social.ProfilePosts(user.ID, 0, syndicationItems, nil)
+		if err != nil {
+			return feedChannel{}, err
+		}
+		return feedChannel{
+			Title:       fmt.Sprintf("%s (@%s) – %s", displayName, user.Username, s.appName),
+			Description: bio,
+			Link:        s.portal + "/social/profile/" + user.Username,
+			SelfURL:     s.api + "/social/profile/" + user.Username + "/feed." + format,
+			Items:       s.postItems(posts),
+		}, nil
+	})
+}
+
+func (s *syndicationService) NoticiasFeed(format string) (models.SyndicationFeed, error) {
+	return s.cached("noticias:feed:"+format, format, func() (feedChannel, error) {
+		lista, err := s.noticias.Listar("", syndicationItems, 0)
+		if err != nil {
+			return feedChannel{}, err
+		}
+		ch := feedChannel{
+			Title:       "Notícias – " + s.appName,
+			Description: "Notícias e avisos do " + s.appName,
+			Link:        s.portal + "/noticias",
+			SelfURL:     s.api + "/noticias/feed." + format,
+		}
+		for _, n := range lista {
+			link := fmt.Sprintf("%s/noticias/%d", s.portal, n.ID)
+			ch.Items = append(ch.Items, feedItem{
+				ID:        link,
+				Title:     n.Titulo,
+				Link:      link,
+				Author:    n.Author,
+				Summary:   n.Resumo,
+				Content:   textoPlano(n.Conteudo),
+				Published: n.CreatedAt,
+				Updated:   n.UpdatedAt,
+			})
+		}
+		return ch, nil
+	})
+}
+
+// postItems leaves out plain reposts, which have no text of their own.
+func (s *syndicationService) postItems(posts []models.Post) []feedItem {
+	items := make([]feedItem, 0, len(posts))
+	for _, p := range posts {
+		if p.RepostID != nil && p.Texto == "" {
+			continue
+		}
+		link := fmt.Sprintf("%s/social/post/%d", s.portal, p.ID)
+		updated := p.CreatedAt
+		if p.EditedAt != nil {
+			updated = *p.EditedAt
+		}
+		items = append(items, feedItem{
+			ID:        link,
+			Title:     feedTitle(p.Texto),
+			Link:      link,
+			Author:    fmt.Sprintf("%s (@%s)", p.AuthorName, p.Author),
+			Content:   p.Texto,
+			Published: p.CreatedAt,
+			Updated:   updated,
+		})
+	}
+	return items
+}
+
+func (s *syndicationService) cached(key, format string, load func() (feedChannel, error)) (models.SyndicationFeed, error) {
+	if !validFeedFormat(format) {
+		return models.SyndicationFeed{}, apperror.NotFound("formato de feed desconhecido; use atom, rss ou json")
+	}
+	var feed models.SyndicationFeed
+	if s.redis.Get(key, &feed) {
+		return feed, nil
+	}
+
+	ch, err := load()
+	if err != nil {
+		return models.SyndicationFeed{}, err
+	}
+	feed, err = renderFeed(ch, format)
+	if err != nil {
+		return models.SyndicationFeed{}, err
+	}
+
+	s.redis.Set(key, feed, syndicationTTL)
+	return feed, nil
+}
+
+// renderFeed serializes ch and derives the ETag from the bytes and
+// Last-Modified from the newest item.
+func renderFeed(ch feedChannel, format string) (models.SyndicationFeed, error) {
+	var updated time.Time
+	for _, it := range ch.Items {
+		if it.Updated.After(updated) {
+			updated = it.Updated
+		}
+	}
+	if updated.IsZero() {
+		updated = time.Unix(0, 0)
+	}
+	updated = updated.UTC().Truncate(time.Second)
+
+	var body []byte
+	var contentType string
+	var err error
+	switch format {
+	case models.FeedAtom:
+		body, err = renderAtom(ch, updated)
+		contentType = "application/atom+xml; charset=utf-8"
+	case models.FeedRSS:
+		body, err = renderRSS(ch, updated)
+		contentType = "application/rss+xml; charset=utf-8"
+	default:
+		body, err = renderJSONFeed(ch)
+		contentType = "application/feed+json; charset=utf-8"
+	}
+	if err != nil {
+		return models.SyndicationFeed{}, err
+	}
+
+	sum := sha1.Sum(body)
+	return models.SyndicationFeed{
+		Body:        string(body),
+		ContentType: contentType,
+		ETag:        `"` + hex.EncodeToString(sum[:]) + `"`,
+		Updated:     updated,
+	}, nil
+}
+
+// feedTitle is the first line of a post, cut at syndicationTitleLen runes.
+func feedTitle(texto string) string {
+	title := strings.TrimSpace(texto)
+	if i := strings.IndexByte(title, '\n'); i >= 0 {
+		title = strings.TrimSpace(title[:i])
+	}
+	if utf8.RuneCountInString(title) > syndicationTitleLen {
+		title = strings.TrimSpace(string([]rune(title)[:syndicationTitleLen-1])) + "…"
+	}
+	if title == "" {
+		return "(sem texto)"
+	}
+	return title
+}
+
+// feedHTML turns plain text into escaped HTML, keeping line breaks.
+func feedHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>")
+}
+
+// ---------------------------------------------------------------------------
+// Atom (RFC 4287)
+// ---------------------------------------------------------------------------
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func renderAtom(ch feedChannel, updated time.Time) ([]byte, error) {
+	feed := atomFeed{
+		Title:    ch.Title,
+		Subtitle: ch.Description,
+		ID:       ch.SelfURL,
+		Updated:  updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: ch.Link, Rel: "alternate", Type: "text/html"},
+			{Href: ch.SelfURL, Rel: "self", Type: "application/atom+xml"},
+		},
+	}
+	for _, it := range ch.Items {
+		e := atomEntry{
+			Title:     it.Title,
+			ID:        it.ID,
+			Link:      atomLink{Href: it.Link, Rel: "alternate", Type: "text/html"},
+			Published: it.Published.UTC().Format(time.RFC3339),
+			Updated:   it.Updated.UTC().Format(time.RFC3339),
+			Summary:   it.Summary,
+			Content:   atomContent{Type: "html", Body: feedHTML(it.Content)},
+		}
+		if it.Author != "" {
+			e.Author = &atomPerson{Name: it.Author}
+		}
+		feed.Entries = append(feed.Entries, e)
+	}
+	return marshalXML(feed)
+}
+
+// ---------------------------------------------------------------------------
+// RSS 2.0
+// ---------------------------------------------------------------------------
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Creator     string  `xml:"dc:creator,omitempty"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func renderRSS(ch feedChannel, updated time.Time) ([]byte, error) {
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:         ch.Title,
+			Link:          ch.Link,
+			Description:   ch.Description,
+			Self:          atomLink{Href: ch.SelfURL, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: updated.Format(time.RFC1123Z),
+		},
+	}
+	if doc.Channel.Description == "" {
+		doc.Channel.Description = ch.Title
+	}
+	for _, it := range ch.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       it.Title,
+			Link:        it.Link,
+			GUID:        rssGUID{IsPermaLink: true, Value: it.ID},
+			PubDate:     it.Published.UTC().Format(time.RFC1123Z),
+			Creator:     it.Author,
+			Description: feedHTML(it.Content),
+		})
+	}
+	return marshalXML(doc)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// ---------------------------------------------------------------------------
+// JSON Feed 1.1
+// ---------------------------------------------------------------------------
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary,omitempty"`
+	ContentText   string           `json:"content_text"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func renderJSONFeed(ch feedChannel) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       ch.Title,
+		HomePageURL: ch.Link,
+		FeedURL:     ch.SelfURL,
+		Description: ch.Description,
+		Language:    "pt-BR",
+		Items:       []jsonFeedItem{},
+	}
+	for _, it := range ch.Items {
+		item := jsonFeedItem{
+			ID:            it.ID,
+			URL:           it.Link,
+			Title:         it.Title,
+			Summary:       it.Summary,
+			ContentText:   it.Content,
+			DatePublished: it.Published.UTC().Format(time.RFC3339),
+			DateModified:  it.Updated.UTC().Format(time.RFC3339),
+		}
+		if it.Author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: it.Author}}
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testChannel() feedChannel {
+	at := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	return feedChannel{
+		Title:   "Feed – Portal",
+		Link:    "https://portal.example.com/social",
+		SelfURL: "https://api.example.com/social/feed.atom",
+		Items: []feedItem{
+			{
+				ID:        "https://portal.example.com/social/post/1",
+				Title:     `<b>"oi" & tchau</b>`,
+				Link:      "https://portal.example.com/social/post/1",
+				Author:    "Ana (@ana)",
+				Content:   "linha 1\n<script>alert(1)</script>",
+				Published: at,
+				Updated:   at.Add(time.Hour),
+			},
+		},
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	feed, err := renderFeed(testChannel(), models.FeedAtom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(feed.Body, "<script>") || strings.Contains(feed.Body, "<b>") {
+		t.Fatalf("conteúdo sem escape:\n%s", feed.Body)
+	}
+
+	var parsed atomFeed
+	if err := xml.Unmarshal([]byte(feed.Body), &parsed); err != nil {
+		t.Fatalf("XML inválido: %v", err)
+	}
+	e := parsed.Entries[0]
+	if e.Title != `<b>"oi" & tchau</b>` {
+		t.Errorf("título: %q", e.Title)
+	}
+	if e.Content.Body != "linha 1<br>&lt;script&gt;alert(1)&lt;/script&gt;" {
+		t.Errorf("conteúdo: %q", e.Content.Body)
+	}
+	if parsed.Updated != "2026-10-18T13:00:00Z" || !feed.Updated.Equal(time.Date(2026, 10, 18, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("updated: %s / %v", parsed.Updated, feed.Updated)
+	}
+	if !strings.HasPrefix(feed.ContentType, "application/atom+xml") {
+		t.Errorf("content type: %s", feed.ContentType)
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	feed, err := renderFeed(testChannel(), models.FeedRSS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Channel struct {
+			Items []struct {
+				Title   string `xml:"title"`
+				GUID    string `xml:"guid"`
+				PubDate string `xml:"pubDate"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal([]byte(feed.Body), &parsed); err != nil {
+		t.Fatalf("XML inválido: %v\n%s", err, feed.Body)
+	}
+	it := parsed.Channel.Items[0]
+	if it.Title != `<b>"oi" & tchau</b>` || it.GUID != "https://portal.example.com/social/post/1" {
+		t.Errorf("item: %+v", it)
+	}
+	if _, err := time.Parse(time.RFC1123Z, it.PubDate); err != nil {
+		t.Errorf("pubDate: %v", err)
+	}
+	if !strings.Contains(feed.Body, `<atom:link href="https://api.example.com/social/feed.atom" rel="self"`) {
+		t.Error("RSS sem atom:link rel=self")
+	}
+}
+
+func TestRenderJSONFeed(t *testing.T) {
+	feed, err := renderFeed(testChannel(), models.FeedJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed jsonFeed
+	if err := json.Unmarshal([]byte(feed.Body), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version != "https://jsonfeed.org/version/1.1" || len(parsed.Items) != 1 || parsed.Items[0].ContentText != "linha 1\n<script>alert(1)</script>" {
+		t.Errorf("feed: %+v", parsed)
+	}
+
+	again, _ := renderFeed(testChannel(), models.FeedJSON)
+	if again.ETag != feed.ETag || !strings.HasPrefix(feed.ETag, `"`) {
+		t.Errorf("ETag deveria ser estável e entre aspas: %s / %s", feed.ETag, again.ETag)
+	}
+}
+
+func TestFeedTitle(t *testing.T) {
+	long := strings.Repeat("á", 100)
+	cases := map[string]string{
+		"  olá mundo  ":           "olá mundo",
+		"primeira\nsegunda":       "primeira",
+		"":                        "(sem texto)",
+		long:                      strings.Repeat("á", syndicationTitleLen-1) + "…",
+		"\n  texto só na segunda": "texto só na segunda",
+	}
+	for in, want := range cases {
+		if got := feedTitle(in); got != want {
+			t.Errorf("feedTitle(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
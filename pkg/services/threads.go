@@ -0,0 +1,128 @@
+package services
+
+import (
+	"cacc/pkg/apperror"
+	"cacc/pkg/models"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Limits of the reply tree GET /social/feed/:id embeds. Whatever is cut is
+// flagged with has_more_replies and read through Replies.
+const (
+	threadMaxDepth       = 5
+	threadRepliesPerNode = 20
+	threadMaxReplies     = 500
+)
+
+// loadReplyTree fills root.Replies with one recursive query.
+func (s *socialService) loadReplyTree(root *models.Post, userID int) {
+	flat, err := s.repo.ThreadReplies(root.ID, userID, threadMaxDepth, threadRepliesPerNode, threadMaxReplies)
+	if err != nil {
+		root.Replies = []models.Post{}
+		return
+	}
+	nestReplies(root, flat, threadMaxDepth, threadRepliesPerNode, len(flat) >= threadMaxReplies)
+}
+
+// nestReplies builds the tree under root from the breadth-first rows of
+// ThreadReplies. A post with more than perNode replies keeps the first
+// perNode and gets has_more_replies and replies_cursor; one whose replies
+// weren't loaded at all gets has_more_replies, plus continue_thread at the
+// depth limit. When the query hit its row cap (truncated) the deepest level
+// is dropped, so no post is left with only part of a page.
+func nestReplies(root *models.Post, flat []models.ThreadReply, maxDepth, perNode int, truncated bool) {
+	if truncated && len(flat) > 0 {
+		last := flat[len(flat)-1].Depth
+		for len(flat) > 0 && flat[len(flat)-1].Depth == last {
+			flat = flat[:len(flat)-1]
+		}
+	}
+
+	children := make(map[int][]int)
+	for i, tr := range flat {
+		if tr.ParentID != nil {
+			children[*tr.ParentID] = append(children[*tr.ParentID], i)
+		}
+	}
+
+	var build func(parentID int) ([]models.Post, bool, string)
+	build = func(parentID int) ([]models.Post, bool, string) {
+		idx := children[parentID]
+		more := len(idx) > perNode
+		if more {
+			idx = idx[:perNode]
+		}
+		replies := make([]models.Post, 0, len(idx))
+		for _, i := range idx {
+			tr := flat[i]
+			p := tr.Post
+			p.Replies, p.HasMoreReplies, p.RepliesCursor = build(p.ID)
+			if tr.HasReplies && len(p.Replies) == 0 {
+				p.HasMoreReplies = true
+				p.ContinueThread = tr.Depth >= maxDepth
+			}
+			replies = append(replies, p)
+		}
+		cursor := ""
+		if more && len(replies) > 0 {
+			cursor = models.PostCursor(replies[len(replies)-1]).Encode()
+		}
+		return replies, more, cursor
+	}
+	root.Replies, root.HasMoreReplies, root.RepliesCursor = build(root.ID)
+}
+
+// Replies lists the direct replies of a post, oldest first. Their own
+// replies aren't loaded; has_more_replies tells which have any.
+func (s *socialService) Replies(postID, userID, limit int, cursor string) (models.Page[models.Post], error) {
+	if limit <= 0 || limit > 50 {
+		limit = threadRepliesPerNode
+	}
+	cur, err := models.DecodeCursor(cursor)
+	if err != nil {
+		return models.Page[models.Post]{}, apperror.Validation("cursor inválido")
+	}
+
+	pos := cursor
+	if pos == "" {
+		pos = "head"
+	}
+	cacheKey := fmt.Sprintf("social:thread:%d:replies:%d:%s:lid%d", postID, limit, pos, userID)
+	var cached models.Page[models.Post]
+	if s.redis.Get(cacheKey, &cached) {
+		return cached, nil
+	}
+
+	parent, err := s.repo.Thread(postID, userID)
+	if err == sql.ErrNoRows || (err == nil && s.blockedWith(parent.UserID, userID)) {
+		return models.Page[models.Post]{}, apperror.NotFound("post não encontrado")
+	}
+	if err != nil {
+		return models.Page[models.Post]{}, err
+	}
+
+	list, err := s.repo.Replies(postID, userID, limit+1, cur)
+	if err != nil {
+		return models.Page[models.Post]{}, err
+	}
+	page := models.NewPage(list, limit, models.PostCursor)
+	s.hydratePosts(page.Items, userID)
+
+	s.redis.Set(cacheKey, page, 30*time.Second)
+	return page, nil
+}
+
+// evictThreads drops the cached thread of postID and of every post above
+// it, since each of them embeds postID's subtree.
+func (s *socialService) evictThreads(postID int) {
+	ancestors, _ := s.repo.AncestorIDs(postID)
+	s.evictThreadIDs(append(ancestors, postID))
+}
+
+func (s *socialService) evictThreadIDs(ids []int) {
+	for _, id := range ids {
+		s.redis.DelPattern(fmt.Sprintf("social:thread:%d:*", id))
+	}
+}
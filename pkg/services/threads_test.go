@@ -0,0 +1,75 @@
+package services
+
+import (
+	"cacc/pkg/models"
+	"testing"
+	"time"
+)
+
+func threadRow(id, parentID, depth int, hasReplies bool) models.ThreadReply {
+	pid := parentID
+	return models.ThreadReply{
+		Post:       models.Post{ID: id, ParentID: &pid, CreatedAt: time.Unix(int64(id), 0)},
+		Depth:      depth,
+		HasReplies: hasReplies,
+	}
+}
+
+func TestNestReplies(t *testing.T) {
+	// 1 ─┬─ 2 ─── 4 ─── 6 (limite de profundidade, tem respostas)
+	//    ├─ 3 ─┬─ 5
+	//    │     ├─ 7
+	//    │     └─ 9 (sentinela além do limite por post)
+	//    └─ 8 (sentinela da raiz)
+	root := models.Post{ID: 1}
+	flat := []models.ThreadReply{
+		threadRow(2, 1, 1, true),
+		threadRow(3, 1, 1, true),
+		threadRow(8, 1, 1, false),
+		threadRow(4, 2, 2, true),
+		threadRow(5, 3, 2, false),
+		threadRow(7, 3, 2, false),
+		threadRow(9, 3, 2, false),
+		threadRow(6, 4, 3, true),
+	}
+	nestReplies(&root, flat, 3, 2, false)
+
+	if len(root.Replies) != 2 || !root.HasMoreReplies || root.RepliesCursor == "" {
+		t.Fatalf("raiz: %d respostas, more=%v, cursor=%q", len(root.Replies), root.HasMoreReplies, root.RepliesCursor)
+	}
+	if cur, _ := models.DecodeCursor(root.RepliesCursor); cur == nil || cur.ID != 3 {
+		t.Errorf("cursor da raiz deveria apontar para o post 3: %+v", cur)
+	}
+
+	two, three := root.Replies[0], root.Replies[1]
+	if two.HasMoreReplies || len(two.Replies) != 1 {
+		t.Errorf("post 2: %+v", two)
+	}
+	deep := two.Replies[0].Replies[0]
+	if deep.ID != 6 || !deep.HasMoreReplies || !deep.ContinueThread || len(deep.Replies) != 0 {
+		t.Errorf("post 6 deveria continuar a thread: %+v", deep)
+	}
+	if len(three.Replies) != 2 || !three.HasMoreReplies || three.ContinueThread {
+		t.Errorf("post 3: %d respostas, more=%v", len(three.Replies), three.HasMoreReplies)
+	}
+}
+
+func TestNestRepliesTruncated(t *testing.T) {
+	// O corte pelo total de linhas descarta o último nível inteiro.
+	root := models.Post{ID: 1}
+	flat := []models.ThreadReply{
+		threadRow(2, 1, 1, true),
+		threadRow(3, 1, 1, true),
+		threadRow(4, 2, 2, false),
+	}
+	nestReplies(&root, flat, 5, 10, true)
+
+	for _, p := range root.Replies {
+		if len(p.Replies) != 0 || !p.HasMoreReplies || p.ContinueThread {
+			t.Errorf("post %d: %d respostas, more=%v, continue=%v", p.ID, len(p.Replies), p.HasMoreReplies, p.ContinueThread)
+		}
+	}
+	if root.HasMoreReplies {
+		t.Error("a raiz tem todas as respostas diretas")
+	}
+}
@@ -0,0 +1,79 @@
+package services
+
+import "strings"
+
+// deviceInfo is a coarse, human-readable description of a User-Agent string.
+// It is good enough for "Chrome no Windows" style labels in the sessions list,
+// not for analytics.
+type deviceInfo struct {
+	Device  string // Desktop, Celular, Tablet, Bot
+	Browser string
+	OS      string
+}
+
+// Fingerprint identifies a device class for the "new login" alert.
+// Versions are left out on purpose so browser updates don't look like new devices.
+func (d deviceInfo) Fingerprint() string {
+	return strings.ToLower(d.Device + "|" + d.Browser + "|" + d.OS)
+}
+
+func (d deviceInfo) Label() string {
+	return d.Browser + " em " + d.OS + " (" + d.Device + ")"
+}
+
+// parseUserAgent extracts browser, OS and device type from a User-Agent.
+// Order matters: most browsers embed "Safari" and "Chrome" tokens, so the more
+// specific ones are matched first.
+func parseUserAgent(ua string) deviceInfo {
+	info := deviceInfo{Device: "Desktop", Browser: "Desconhecido", OS: "Desconhecido"}
+	if ua == "" {
+		return info
+	}
+	l := strings.ToLower(ua)
+
+	switch {
+	case strings.Contains(l, "edg/") || strings.Contains(l, "edga/") || strings.Contains(l, "edgios/"):
+		info.Browser = "Edge"
+	case strings.Contains(l, "opr/") || strings.Contains(l, "opera"):
+		info.Browser = "Opera"
+	case strings.Contains(l, "samsungbrowser/"):
+		info.Browser = "Samsung Internet"
+	case strings.Contains(l, "firefox/") || strings.Contains(l, "fxios/"):
+		info.Browser = "Firefox"
+	case strings.Contains(l, "chrome/") || strings.Contains(l, "crios/"):
+		info.Browser = "Chrome"
+	case strings.Contains(l, "safari/"):
+		info.Browser = "Safari"
+	case strings.Contains(l, "curl/"):
+		info.Browser = "curl"
+	case strings.Contains(l, "postman"):
+		info.Browser = "Postman"
+	}
+
+	switch {
+	case strings.Contains(l, "android"):
+		info.OS = "Android"
+	case strings.Contains(l, "iphone") || strings.Contains(l, "ipad") || strings.Contains(l, "ipod"):
+		info.OS = "iOS"
+	case strings.Contains(l, "windows"):
+		info.OS = "Windows"
+	case strings.Contains(l, "mac os x") || strings.Contains(l, "macintosh"):
+		info.OS = "macOS"
+	case strings.Contains(l, "cros"):
+		info.OS = "ChromeOS"
+	case strings.Contains(l, "linux"):
+		info.OS = "Linux"
+	}
+
+	switch {
+	case strings.Contains(l, "bot") || strings.Contains(l, "spider") || strings.Contains(l, "crawler"):
+		info.Device = "Bot"
+	case strings.Contains(l, "ipad") || strings.Contains(l, "tablet") ||
+		(strings.Contains(l, "android") && !strings.Contains(l, "mobile")):
+		info.Device = "Tablet"
+	case strings.Contains(l, "mobi") || strings.Contains(l, "iphone") || strings.Contains(l, "ipod"):
+		info.Device = "Celular"
+	}
+
+	return info
+}
@@ -0,0 +1,62 @@
+package services
+
+import "testing"
+
+func TestParseUserAgent(t *testing.T) {
+	cases := []struct {
+		name string
+		ua   string
+		want deviceInfo
+	}{
+		{
+			name: "chrome windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			want: deviceInfo{Device: "Desktop", Browser: "Chrome", OS: "Windows"},
+		},
+		{
+			name: "edge windows",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0",
+			want: deviceInfo{Device: "Desktop", Browser: "Edge", OS: "Windows"},
+		},
+		{
+			name: "safari iphone",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1",
+			want: deviceInfo{Device: "Celular", Browser: "Safari", OS: "iOS"},
+		},
+		{
+			name: "chrome android phone",
+			ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36",
+			want: deviceInfo{Device: "Celular", Browser: "Chrome", OS: "Android"},
+		},
+		{
+			name: "android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+			want: deviceInfo{Device: "Tablet", Browser: "Chrome", OS: "Android"},
+		},
+		{
+			name: "firefox linux",
+			ua:   "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:127.0) Gecko/20100101 Firefox/127.0",
+			want: deviceInfo{Device: "Desktop", Browser: "Firefox", OS: "Linux"},
+		},
+		{
+			name: "vazio",
+			ua:   "",
+			want: deviceInfo{Device: "Desktop", Browser: "Desconhecido", OS: "Desconhecido"},
+		},
+	}
+
+	for _, tc := range cases {
+		got := parseUserAgent(tc.ua)
+		if got != tc.want {
+			t.Errorf("%s: esperado %+v, obteve %+v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestDeviceFingerprintIgnoresVersion(t *testing.T) {
+	a := parseUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/125.0.0.0 Safari/537.36")
+	b := parseUserAgent("Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36")
+	if a.Fingerprint() != b.Fingerprint() {
+		t.Errorf("fingerprints deveriam ser iguais: %q != %q", a.Fingerprint(), b.Fingerprint())
+	}
+}
@@ -22,18 +22,39 @@ const (
 )
 
 type Post struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	Texto         string                 `protobuf:"bytes,2,opt,name=texto,proto3" json:"texto,omitempty"`
-	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
-	UserId        int32                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ParentId      int32                  `protobuf:"varint,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 0 = root post
-	Likes         int32                  `protobuf:"varint,6,opt,name=likes,proto3" json:"likes,omitempty"`
-	ReplyCount    int32                  `protobuf:"varint,7,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
-	CreatedAt     int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // unix millis
-	Replies       []*Post                `protobuf:"bytes,9,rep,name=replies,proto3" json:"replies,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Texto             string                 `protobuf:"bytes,2,opt,name=texto,proto3" json:"texto,omitempty"`
+	Author            string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	UserId            int32                  `protobuf:"varint,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ParentId          int32                  `protobuf:"varint,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // 0 = root post
+	Likes             int32                  `protobuf:"varint,6,opt,name=likes,proto3" json:"likes,omitempty"`
+	ReplyCount        int32                  `protobuf:"varint,7,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	CreatedAt         int64                  `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"` // unix millis
+	Replies           []*Post                `protobuf:"bytes,9,rep,name=replies,proto3" json:"replies,omitempty"`
+	EditedAt          int64                  `protobuf:"varint,10,opt,name=edited_at,json=editedAt,proto3" json:"edited_at,omitempty"`          // unix millis, 0 = never edited
+	Pinned            bool                   `protobuf:"varint,11,opt,name=pinned,proto3" json:"pinned,omitempty"`                              // fixado no perfil ou aviso do feed
+	PinnedUntil       int64                  `protobuf:"varint,12,opt,name=pinned_until,json=pinnedUntil,proto3" json:"pinned_until,omitempty"` // unix millis, só avisos do feed
+	Reactions         []*ReactionCount       `protobuf:"bytes,13,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	AuthorName        string                 `protobuf:"bytes,14,opt,name=author_name,json=authorName,proto3" json:"author_name,omitempty"` // display name do perfil social
+	AvatarUrl         string                 `protobuf:"bytes,15,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	RepostId          int32                  `protobuf:"varint,16,opt,name=repost_id,json=repostId,proto3" json:"repost_id,omitempty"` // 0 = não é repost
+	Repost            *Post                  `protobuf:"bytes,17,opt,name=repost,proto3" json:"repost,omitempty"`
+	RepostUnavailable bool                   `protobuf:"varint,18,opt,name=repost_unavailable,json=repostUnavailable,proto3" json:"repost_unavailable,omitempty"` // original apagado, oculto ou bloqueado
+	RepostCount       int32                  `protobuf:"varint,19,opt,name=repost_count,json=repostCount,proto3" json:"repost_count,omitempty"`
+	Reposted          bool                   `protobuf:"varint,20,opt,name=reposted,proto3" json:"reposted,omitempty"`
+	Liked             bool                   `protobuf:"varint,21,opt,name=liked,proto3" json:"liked,omitempty"`
+	Bookmarked        bool                   `protobuf:"varint,22,opt,name=bookmarked,proto3" json:"bookmarked,omitempty"`
+	BookmarkedAt      int64                  `protobuf:"varint,23,opt,name=bookmarked_at,json=bookmarkedAt,proto3" json:"bookmarked_at,omitempty"` // unix millis, só em /social/bookmarks
+	Held              bool                   `protobuf:"varint,24,opt,name=held,proto3" json:"held,omitempty"`                                     // retido pelo filtro, aguardando moderação
+	Attachments       []*Attachment          `protobuf:"bytes,25,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	Poll              *Poll                  `protobuf:"bytes,26,opt,name=poll,proto3" json:"poll,omitempty"`
+	Preview           *LinkPreview           `protobuf:"bytes,27,opt,name=preview,proto3" json:"preview,omitempty"`
+	HasMoreReplies    bool                   `protobuf:"varint,28,opt,name=has_more_replies,json=hasMoreReplies,proto3" json:"has_more_replies,omitempty"`
+	RepliesCursor     string                 `protobuf:"bytes,29,opt,name=replies_cursor,json=repliesCursor,proto3" json:"replies_cursor,omitempty"`
+	ContinueThread    bool                   `protobuf:"varint,30,opt,name=continue_thread,json=continueThread,proto3" json:"continue_thread,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Post) Reset() {
@@ -105,28 +126,579 @@ func (x *Post) GetLikes() int32 {
 	if x != nil {
 		return x.Likes
 	}
-	return 0
+	return 0
+}
+
+func (x *Post) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Post) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Post) GetReplies() []*Post {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+func (x *Post) GetEditedAt() int64 {
+	if x != nil {
+		return x.EditedAt
+	}
+	return 0
+}
+
+func (x *Post) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Post) GetPinnedUntil() int64 {
+	if x != nil {
+		return x.PinnedUntil
+	}
+	return 0
+}
+
+func (x *Post) GetReactions() []*ReactionCount {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *Post) GetAuthorName() string {
+	if x != nil {
+		return x.AuthorName
+	}
+	return ""
+}
+
+func (x *Post) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *Post) GetRepostId() int32 {
+	if x != nil {
+		return x.RepostId
+	}
+	return 0
+}
+
+func (x *Post) GetRepost() *Post {
+	if x != nil {
+		return x.Repost
+	}
+	return nil
+}
+
+func (x *Post) GetRepostUnavailable() bool {
+	if x != nil {
+		return x.RepostUnavailable
+	}
+	return false
+}
+
+func (x *Post) GetRepostCount() int32 {
+	if x != nil {
+		return x.RepostCount
+	}
+	return 0
+}
+
+func (x *Post) GetReposted() bool {
+	if x != nil {
+		return x.Reposted
+	}
+	return false
+}
+
+func (x *Post) GetLiked() bool {
+	if x != nil {
+		return x.Liked
+	}
+	return false
+}
+
+func (x *Post) GetBookmarked() bool {
+	if x != nil {
+		return x.Bookmarked
+	}
+	return false
+}
+
+func (x *Post) GetBookmarkedAt() int64 {
+	if x != nil {
+		return x.BookmarkedAt
+	}
+	return 0
+}
+
+func (x *Post) GetHeld() bool {
+	if x != nil {
+		return x.Held
+	}
+	return false
+}
+
+func (x *Post) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *Post) GetPoll() *Poll {
+	if x != nil {
+		return x.Poll
+	}
+	return nil
+}
+
+func (x *Post) GetPreview() *LinkPreview {
+	if x != nil {
+		return x.Preview
+	}
+	return nil
+}
+
+func (x *Post) GetHasMoreReplies() bool {
+	if x != nil {
+		return x.HasMoreReplies
+	}
+	return false
+}
+
+func (x *Post) GetRepliesCursor() string {
+	if x != nil {
+		return x.RepliesCursor
+	}
+	return ""
+}
+
+func (x *Post) GetContinueThread() bool {
+	if x != nil {
+		return x.ContinueThread
+	}
+	return false
+}
+
+type ReactionCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Emoji         string                 `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Reacted       bool                   `protobuf:"varint,3,opt,name=reacted,proto3" json:"reacted,omitempty"` // o usuário da requisição reagiu com este emoji
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionCount) Reset() {
+	*x = ReactionCount{}
+	mi := &file_proto_social_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionCount) ProtoMessage() {}
+
+func (x *ReactionCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionCount.ProtoReflect.Descriptor instead.
+func (*ReactionCount) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReactionCount) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionCount) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionCount) GetReacted() bool {
+	if x != nil {
+		return x.Reacted
+	}
+	return false
+}
+
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"` // image, gif, pdf
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	AltText       string                 `protobuf:"bytes,4,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`
+	FileName      string                 `protobuf:"bytes,5,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	Width         int32                  `protobuf:"varint,6,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,7,opt,name=height,proto3" json:"height,omitempty"`
+	Size          int64                  `protobuf:"varint,8,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_proto_social_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Attachment) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Attachment) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Attachment) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Attachment) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+func (x *Attachment) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *Attachment) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Attachment) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Attachment) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type Poll struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PostId         int32                  `protobuf:"varint,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	Options        []*PollOption          `protobuf:"bytes,2,rep,name=options,proto3" json:"options,omitempty"`
+	Multiple       bool                   `protobuf:"varint,3,opt,name=multiple,proto3" json:"multiple,omitempty"`
+	ClosesAt       int64                  `protobuf:"varint,4,opt,name=closes_at,json=closesAt,proto3" json:"closes_at,omitempty"` // unix millis, 0 = sem prazo
+	Closed         bool                   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
+	Voters         int32                  `protobuf:"varint,6,opt,name=voters,proto3" json:"voters,omitempty"`
+	Voted          bool                   `protobuf:"varint,7,opt,name=voted,proto3" json:"voted,omitempty"`
+	MyChoices      []int32                `protobuf:"varint,8,rep,packed,name=my_choices,json=myChoices,proto3" json:"my_choices,omitempty"`
+	ResultsVisible bool                   `protobuf:"varint,9,opt,name=results_visible,json=resultsVisible,proto3" json:"results_visible,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Poll) Reset() {
+	*x = Poll{}
+	mi := &file_proto_social_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Poll) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Poll) ProtoMessage() {}
+
+func (x *Poll) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Poll.ProtoReflect.Descriptor instead.
+func (*Poll) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Poll) GetPostId() int32 {
+	if x != nil {
+		return x.PostId
+	}
+	return 0
+}
+
+func (x *Poll) GetOptions() []*PollOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Poll) GetMultiple() bool {
+	if x != nil {
+		return x.Multiple
+	}
+	return false
+}
+
+func (x *Poll) GetClosesAt() int64 {
+	if x != nil {
+		return x.ClosesAt
+	}
+	return 0
+}
+
+func (x *Poll) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *Poll) GetVoters() int32 {
+	if x != nil {
+		return x.Voters
+	}
+	return 0
+}
+
+func (x *Poll) GetVoted() bool {
+	if x != nil {
+		return x.Voted
+	}
+	return false
+}
+
+func (x *Poll) GetMyChoices() []int32 {
+	if x != nil {
+		return x.MyChoices
+	}
+	return nil
+}
+
+func (x *Poll) GetResultsVisible() bool {
+	if x != nil {
+		return x.ResultsVisible
+	}
+	return false
+}
+
+type PollOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Texto         string                 `protobuf:"bytes,2,opt,name=texto,proto3" json:"texto,omitempty"`
+	Votes         int32                  `protobuf:"varint,3,opt,name=votes,proto3" json:"votes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PollOption) Reset() {
+	*x = PollOption{}
+	mi := &file_proto_social_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PollOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PollOption) ProtoMessage() {}
+
+func (x *PollOption) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PollOption.ProtoReflect.Descriptor instead.
+func (*PollOption) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PollOption) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PollOption) GetTexto() string {
+	if x != nil {
+		return x.Texto
+	}
+	return ""
+}
+
+func (x *PollOption) GetVotes() int32 {
+	if x != nil {
+		return x.Votes
+	}
+	return 0
+}
+
+type LinkPreview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ImageUrl      string                 `protobuf:"bytes,4,opt,name=image_url,json=imageUrl,proto3" json:"image_url,omitempty"`
+	SiteName      string                 `protobuf:"bytes,5,opt,name=site_name,json=siteName,proto3" json:"site_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LinkPreview) Reset() {
+	*x = LinkPreview{}
+	mi := &file_proto_social_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LinkPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LinkPreview) ProtoMessage() {}
+
+func (x *LinkPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LinkPreview.ProtoReflect.Descriptor instead.
+func (*LinkPreview) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *LinkPreview) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *LinkPreview) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
 }
 
-func (x *Post) GetReplyCount() int32 {
+func (x *LinkPreview) GetDescription() string {
 	if x != nil {
-		return x.ReplyCount
+		return x.Description
 	}
-	return 0
+	return ""
 }
 
-func (x *Post) GetCreatedAt() int64 {
+func (x *LinkPreview) GetImageUrl() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.ImageUrl
 	}
-	return 0
+	return ""
 }
 
-func (x *Post) GetReplies() []*Post {
+func (x *LinkPreview) GetSiteName() string {
 	if x != nil {
-		return x.Replies
+		return x.SiteName
 	}
-	return nil
+	return ""
 }
 
 type Profile struct {
@@ -135,13 +707,21 @@ type Profile struct {
 	TotalPosts    int32                  `protobuf:"varint,2,opt,name=total_posts,json=totalPosts,proto3" json:"total_posts,omitempty"`
 	TotalLikes    int32                  `protobuf:"varint,3,opt,name=total_likes,json=totalLikes,proto3" json:"total_likes,omitempty"`
 	Posts         []*Post                `protobuf:"bytes,4,rep,name=posts,proto3" json:"posts,omitempty"`
+	PinnedPost    *Post                  `protobuf:"bytes,5,opt,name=pinned_post,json=pinnedPost,proto3" json:"pinned_post,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,6,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Bio           string                 `protobuf:"bytes,7,opt,name=bio,proto3" json:"bio,omitempty"`
+	AvatarUrl     string                 `protobuf:"bytes,8,opt,name=avatar_url,json=avatarUrl,proto3" json:"avatar_url,omitempty"`
+	Followers     int32                  `protobuf:"varint,9,opt,name=followers,proto3" json:"followers,omitempty"`
+	Following     int32                  `protobuf:"varint,10,opt,name=following,proto3" json:"following,omitempty"`
+	IsFollowing   bool                   `protobuf:"varint,11,opt,name=is_following,json=isFollowing,proto3" json:"is_following,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,12,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Profile) Reset() {
 	*x = Profile{}
-	mi := &file_proto_social_proto_msgTypes[1]
+	mi := &file_proto_social_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -153,7 +733,7 @@ func (x *Profile) String() string {
 func (*Profile) ProtoMessage() {}
 
 func (x *Profile) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[1]
+	mi := &file_proto_social_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -166,7 +746,7 @@ func (x *Profile) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Profile.ProtoReflect.Descriptor instead.
 func (*Profile) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{1}
+	return file_proto_social_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Profile) GetUsername() string {
@@ -197,17 +777,75 @@ func (x *Profile) GetPosts() []*Post {
 	return nil
 }
 
+func (x *Profile) GetPinnedPost() *Post {
+	if x != nil {
+		return x.PinnedPost
+	}
+	return nil
+}
+
+func (x *Profile) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *Profile) GetBio() string {
+	if x != nil {
+		return x.Bio
+	}
+	return ""
+}
+
+func (x *Profile) GetAvatarUrl() string {
+	if x != nil {
+		return x.AvatarUrl
+	}
+	return ""
+}
+
+func (x *Profile) GetFollowers() int32 {
+	if x != nil {
+		return x.Followers
+	}
+	return 0
+}
+
+func (x *Profile) GetFollowing() int32 {
+	if x != nil {
+		return x.Following
+	}
+	return 0
+}
+
+func (x *Profile) GetIsFollowing() bool {
+	if x != nil {
+		return x.IsFollowing
+	}
+	return false
+}
+
+func (x *Profile) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type LikeResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	PostId        int32                  `protobuf:"varint,1,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
 	Likes         int32                  `protobuf:"varint,2,opt,name=likes,proto3" json:"likes,omitempty"`
+	Emoji         string                 `protobuf:"bytes,3,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Reactions     []*ReactionCount       `protobuf:"bytes,4,rep,name=reactions,proto3" json:"reactions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LikeResult) Reset() {
 	*x = LikeResult{}
-	mi := &file_proto_social_proto_msgTypes[2]
+	mi := &file_proto_social_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -219,7 +857,7 @@ func (x *LikeResult) String() string {
 func (*LikeResult) ProtoMessage() {}
 
 func (x *LikeResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[2]
+	mi := &file_proto_social_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,7 +870,7 @@ func (x *LikeResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LikeResult.ProtoReflect.Descriptor instead.
 func (*LikeResult) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{2}
+	return file_proto_social_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *LikeResult) GetPostId() int32 {
@@ -249,6 +887,20 @@ func (x *LikeResult) GetLikes() int32 {
 	return 0
 }
 
+func (x *LikeResult) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *LikeResult) GetReactions() []*ReactionCount {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
 type DeleteResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -259,7 +911,7 @@ type DeleteResult struct {
 
 func (x *DeleteResult) Reset() {
 	*x = DeleteResult{}
-	mi := &file_proto_social_proto_msgTypes[3]
+	mi := &file_proto_social_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -271,7 +923,7 @@ func (x *DeleteResult) String() string {
 func (*DeleteResult) ProtoMessage() {}
 
 func (x *DeleteResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[3]
+	mi := &file_proto_social_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -284,7 +936,7 @@ func (x *DeleteResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteResult.ProtoReflect.Descriptor instead.
 func (*DeleteResult) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{3}
+	return file_proto_social_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *DeleteResult) GetId() int32 {
@@ -304,14 +956,16 @@ func (x *DeleteResult) GetStatus() string {
 type FeedRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Offset        int32                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"` // não usado; páginas seguem por cursor
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`  // next_cursor da página anterior
+	Mode          string                 `protobuf:"bytes,4,opt,name=mode,proto3" json:"mode,omitempty"`      // global (padrão) ou following
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FeedRequest) Reset() {
 	*x = FeedRequest{}
-	mi := &file_proto_social_proto_msgTypes[4]
+	mi := &file_proto_social_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -323,7 +977,7 @@ func (x *FeedRequest) String() string {
 func (*FeedRequest) ProtoMessage() {}
 
 func (x *FeedRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[4]
+	mi := &file_proto_social_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -336,7 +990,7 @@ func (x *FeedRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FeedRequest.ProtoReflect.Descriptor instead.
 func (*FeedRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{4}
+	return file_proto_social_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *FeedRequest) GetLimit() int32 {
@@ -353,6 +1007,20 @@ func (x *FeedRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *FeedRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *FeedRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
 type ThreadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int32                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -362,7 +1030,7 @@ type ThreadRequest struct {
 
 func (x *ThreadRequest) Reset() {
 	*x = ThreadRequest{}
-	mi := &file_proto_social_proto_msgTypes[5]
+	mi := &file_proto_social_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -374,7 +1042,7 @@ func (x *ThreadRequest) String() string {
 func (*ThreadRequest) ProtoMessage() {}
 
 func (x *ThreadRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[5]
+	mi := &file_proto_social_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -387,7 +1055,7 @@ func (x *ThreadRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThreadRequest.ProtoReflect.Descriptor instead.
 func (*ThreadRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{5}
+	return file_proto_social_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ThreadRequest) GetId() int32 {
@@ -406,7 +1074,7 @@ type ProfileRequest struct {
 
 func (x *ProfileRequest) Reset() {
 	*x = ProfileRequest{}
-	mi := &file_proto_social_proto_msgTypes[6]
+	mi := &file_proto_social_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -418,7 +1086,7 @@ func (x *ProfileRequest) String() string {
 func (*ProfileRequest) ProtoMessage() {}
 
 func (x *ProfileRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[6]
+	mi := &file_proto_social_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -431,7 +1099,7 @@ func (x *ProfileRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProfileRequest.ProtoReflect.Descriptor instead.
 func (*ProfileRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{6}
+	return file_proto_social_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ProfileRequest) GetUsername() string {
@@ -450,7 +1118,7 @@ type CreatePostRequest struct {
 
 func (x *CreatePostRequest) Reset() {
 	*x = CreatePostRequest{}
-	mi := &file_proto_social_proto_msgTypes[7]
+	mi := &file_proto_social_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -462,7 +1130,7 @@ func (x *CreatePostRequest) String() string {
 func (*CreatePostRequest) ProtoMessage() {}
 
 func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[7]
+	mi := &file_proto_social_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -475,7 +1143,7 @@ func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
 func (*CreatePostRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{7}
+	return file_proto_social_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CreatePostRequest) GetTexto() string {
@@ -495,7 +1163,7 @@ type CommentRequest struct {
 
 func (x *CommentRequest) Reset() {
 	*x = CommentRequest{}
-	mi := &file_proto_social_proto_msgTypes[8]
+	mi := &file_proto_social_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -507,7 +1175,7 @@ func (x *CommentRequest) String() string {
 func (*CommentRequest) ProtoMessage() {}
 
 func (x *CommentRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[8]
+	mi := &file_proto_social_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -520,7 +1188,7 @@ func (x *CommentRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CommentRequest.ProtoReflect.Descriptor instead.
 func (*CommentRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{8}
+	return file_proto_social_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CommentRequest) GetParentId() int32 {
@@ -546,7 +1214,7 @@ type LikeRequest struct {
 
 func (x *LikeRequest) Reset() {
 	*x = LikeRequest{}
-	mi := &file_proto_social_proto_msgTypes[9]
+	mi := &file_proto_social_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +1226,7 @@ func (x *LikeRequest) String() string {
 func (*LikeRequest) ProtoMessage() {}
 
 func (x *LikeRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[9]
+	mi := &file_proto_social_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +1239,7 @@ func (x *LikeRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LikeRequest.ProtoReflect.Descriptor instead.
 func (*LikeRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{9}
+	return file_proto_social_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *LikeRequest) GetId() int32 {
@@ -590,7 +1258,7 @@ type DeletePostRequest struct {
 
 func (x *DeletePostRequest) Reset() {
 	*x = DeletePostRequest{}
-	mi := &file_proto_social_proto_msgTypes[10]
+	mi := &file_proto_social_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -602,7 +1270,7 @@ func (x *DeletePostRequest) String() string {
 func (*DeletePostRequest) ProtoMessage() {}
 
 func (x *DeletePostRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[10]
+	mi := &file_proto_social_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -615,7 +1283,7 @@ func (x *DeletePostRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeletePostRequest.ProtoReflect.Descriptor instead.
 func (*DeletePostRequest) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{10}
+	return file_proto_social_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *DeletePostRequest) GetId() int32 {
@@ -625,17 +1293,62 @@ func (x *DeletePostRequest) GetId() int32 {
 	return 0
 }
 
+type SubscribeFeedRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       []string               `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"` // vazio = todos os eventos do social
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeFeedRequest) Reset() {
+	*x = SubscribeFeedRequest{}
+	mi := &file_proto_social_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeFeedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeFeedRequest) ProtoMessage() {}
+
+func (x *SubscribeFeedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeFeedRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeFeedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SubscribeFeedRequest) GetActions() []string {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
 type FeedResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Posts         []*Post                `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
-	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // não usado; páginas seguem por next_cursor
+	NextCursor    string                 `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *FeedResponse) Reset() {
 	*x = FeedResponse{}
-	mi := &file_proto_social_proto_msgTypes[11]
+	mi := &file_proto_social_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +1360,7 @@ func (x *FeedResponse) String() string {
 func (*FeedResponse) ProtoMessage() {}
 
 func (x *FeedResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[11]
+	mi := &file_proto_social_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +1373,7 @@ func (x *FeedResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FeedResponse.ProtoReflect.Descriptor instead.
 func (*FeedResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{11}
+	return file_proto_social_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *FeedResponse) GetPosts() []*Post {
@@ -677,6 +1390,13 @@ func (x *FeedResponse) GetTotal() int32 {
 	return 0
 }
 
+func (x *FeedResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
 type ThreadResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
@@ -686,7 +1406,7 @@ type ThreadResponse struct {
 
 func (x *ThreadResponse) Reset() {
 	*x = ThreadResponse{}
-	mi := &file_proto_social_proto_msgTypes[12]
+	mi := &file_proto_social_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -698,7 +1418,7 @@ func (x *ThreadResponse) String() string {
 func (*ThreadResponse) ProtoMessage() {}
 
 func (x *ThreadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[12]
+	mi := &file_proto_social_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -711,7 +1431,7 @@ func (x *ThreadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ThreadResponse.ProtoReflect.Descriptor instead.
 func (*ThreadResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{12}
+	return file_proto_social_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *ThreadResponse) GetPost() *Post {
@@ -730,7 +1450,7 @@ type ProfileResponse struct {
 
 func (x *ProfileResponse) Reset() {
 	*x = ProfileResponse{}
-	mi := &file_proto_social_proto_msgTypes[13]
+	mi := &file_proto_social_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -742,7 +1462,7 @@ func (x *ProfileResponse) String() string {
 func (*ProfileResponse) ProtoMessage() {}
 
 func (x *ProfileResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[13]
+	mi := &file_proto_social_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -755,7 +1475,7 @@ func (x *ProfileResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProfileResponse.ProtoReflect.Descriptor instead.
 func (*ProfileResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{13}
+	return file_proto_social_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *ProfileResponse) GetProfile() *Profile {
@@ -774,7 +1494,7 @@ type PostResponse struct {
 
 func (x *PostResponse) Reset() {
 	*x = PostResponse{}
-	mi := &file_proto_social_proto_msgTypes[14]
+	mi := &file_proto_social_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -786,7 +1506,7 @@ func (x *PostResponse) String() string {
 func (*PostResponse) ProtoMessage() {}
 
 func (x *PostResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[14]
+	mi := &file_proto_social_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,7 +1519,7 @@ func (x *PostResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PostResponse.ProtoReflect.Descriptor instead.
 func (*PostResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{14}
+	return file_proto_social_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *PostResponse) GetPost() *Post {
@@ -818,7 +1538,7 @@ type LikeResponse struct {
 
 func (x *LikeResponse) Reset() {
 	*x = LikeResponse{}
-	mi := &file_proto_social_proto_msgTypes[15]
+	mi := &file_proto_social_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -830,7 +1550,7 @@ func (x *LikeResponse) String() string {
 func (*LikeResponse) ProtoMessage() {}
 
 func (x *LikeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[15]
+	mi := &file_proto_social_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -843,7 +1563,7 @@ func (x *LikeResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LikeResponse.ProtoReflect.Descriptor instead.
 func (*LikeResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{15}
+	return file_proto_social_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *LikeResponse) GetResult() *LikeResult {
@@ -862,7 +1582,7 @@ type DeleteResponse struct {
 
 func (x *DeleteResponse) Reset() {
 	*x = DeleteResponse{}
-	mi := &file_proto_social_proto_msgTypes[16]
+	mi := &file_proto_social_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -874,7 +1594,7 @@ func (x *DeleteResponse) String() string {
 func (*DeleteResponse) ProtoMessage() {}
 
 func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_social_proto_msgTypes[16]
+	mi := &file_proto_social_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -887,7 +1607,7 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_social_proto_rawDescGZIP(), []int{16}
+	return file_proto_social_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *DeleteResponse) GetResult() *DeleteResult {
@@ -897,11 +1617,98 @@ func (x *DeleteResponse) GetResult() *DeleteResult {
 	return nil
 }
 
+// FeedEvent is one of the "social" events the hub broadcasts over the
+// WebSocket. data is the same JSON the WebSocket gets; post and post_id are
+// filled in when the event carries them.
+type FeedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"` // new_post, new_reply, post_edited, post_deleted, post_liked...
+	Ts            int64                  `protobuf:"varint,2,opt,name=ts,proto3" json:"ts,omitempty"`        // unix millis
+	Post          *Post                  `protobuf:"bytes,3,opt,name=post,proto3" json:"post,omitempty"`     // new_post, new_reply, post_edited
+	PostId        int32                  `protobuf:"varint,4,opt,name=post_id,json=postId,proto3" json:"post_id,omitempty"`
+	ParentId      int32                  `protobuf:"varint,5,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"` // new_reply
+	Data          []byte                 `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedEvent) Reset() {
+	*x = FeedEvent{}
+	mi := &file_proto_social_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedEvent) ProtoMessage() {}
+
+func (x *FeedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_social_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedEvent.ProtoReflect.Descriptor instead.
+func (*FeedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_social_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *FeedEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *FeedEvent) GetTs() int64 {
+	if x != nil {
+		return x.Ts
+	}
+	return 0
+}
+
+func (x *FeedEvent) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+func (x *FeedEvent) GetPostId() int32 {
+	if x != nil {
+		return x.PostId
+	}
+	return 0
+}
+
+func (x *FeedEvent) GetParentId() int32 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *FeedEvent) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_proto_social_proto protoreflect.FileDescriptor
 
 const file_proto_social_proto_rawDesc = "" +
 	"\n" +
-	"\x12proto/social.proto\x12\x06social\"\xf8\x01\n" +
+	"\x12proto/social.proto\x12\x06social\"\xe6\a\n" +
 	"\x04Post\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
 	"\x05texto\x18\x02 \x01(\tR\x05texto\x12\x16\n" +
@@ -913,24 +1720,102 @@ const file_proto_social_proto_rawDesc = "" +
 	"replyCount\x12\x1d\n" +
 	"\n" +
 	"created_at\x18\b \x01(\x03R\tcreatedAt\x12&\n" +
-	"\areplies\x18\t \x03(\v2\f.social.PostR\areplies\"\x8b\x01\n" +
+	"\areplies\x18\t \x03(\v2\f.social.PostR\areplies\x12\x1b\n" +
+	"\tedited_at\x18\n" +
+	" \x01(\x03R\beditedAt\x12\x16\n" +
+	"\x06pinned\x18\v \x01(\bR\x06pinned\x12!\n" +
+	"\fpinned_until\x18\f \x01(\x03R\vpinnedUntil\x123\n" +
+	"\treactions\x18\r \x03(\v2\x15.social.ReactionCountR\treactions\x12\x1f\n" +
+	"\vauthor_name\x18\x0e \x01(\tR\n" +
+	"authorName\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x0f \x01(\tR\tavatarUrl\x12\x1b\n" +
+	"\trepost_id\x18\x10 \x01(\x05R\brepostId\x12$\n" +
+	"\x06repost\x18\x11 \x01(\v2\f.social.PostR\x06repost\x12-\n" +
+	"\x12repost_unavailable\x18\x12 \x01(\bR\x11repostUnavailable\x12!\n" +
+	"\frepost_count\x18\x13 \x01(\x05R\vrepostCount\x12\x1a\n" +
+	"\breposted\x18\x14 \x01(\bR\breposted\x12\x14\n" +
+	"\x05liked\x18\x15 \x01(\bR\x05liked\x12\x1e\n" +
+	"\n" +
+	"bookmarked\x18\x16 \x01(\bR\n" +
+	"bookmarked\x12#\n" +
+	"\rbookmarked_at\x18\x17 \x01(\x03R\fbookmarkedAt\x12\x12\n" +
+	"\x04held\x18\x18 \x01(\bR\x04held\x124\n" +
+	"\vattachments\x18\x19 \x03(\v2\x12.social.AttachmentR\vattachments\x12 \n" +
+	"\x04poll\x18\x1a \x01(\v2\f.social.PollR\x04poll\x12-\n" +
+	"\apreview\x18\x1b \x01(\v2\x13.social.LinkPreviewR\apreview\x12(\n" +
+	"\x10has_more_replies\x18\x1c \x01(\bR\x0ehasMoreReplies\x12%\n" +
+	"\x0ereplies_cursor\x18\x1d \x01(\tR\rrepliesCursor\x12'\n" +
+	"\x0fcontinue_thread\x18\x1e \x01(\bR\x0econtinueThread\"U\n" +
+	"\rReactionCount\x12\x14\n" +
+	"\x05emoji\x18\x01 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x18\n" +
+	"\areacted\x18\x03 \x01(\bR\areacted\"\xbc\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12\x19\n" +
+	"\balt_text\x18\x04 \x01(\tR\aaltText\x12\x1b\n" +
+	"\tfile_name\x18\x05 \x01(\tR\bfileName\x12\x14\n" +
+	"\x05width\x18\x06 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\a \x01(\x05R\x06height\x12\x12\n" +
+	"\x04size\x18\b \x01(\x03R\x04size\"\x94\x02\n" +
+	"\x04Poll\x12\x17\n" +
+	"\apost_id\x18\x01 \x01(\x05R\x06postId\x12,\n" +
+	"\aoptions\x18\x02 \x03(\v2\x12.social.PollOptionR\aoptions\x12\x1a\n" +
+	"\bmultiple\x18\x03 \x01(\bR\bmultiple\x12\x1b\n" +
+	"\tcloses_at\x18\x04 \x01(\x03R\bclosesAt\x12\x16\n" +
+	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x16\n" +
+	"\x06voters\x18\x06 \x01(\x05R\x06voters\x12\x14\n" +
+	"\x05voted\x18\a \x01(\bR\x05voted\x12\x1d\n" +
+	"\n" +
+	"my_choices\x18\b \x03(\x05R\tmyChoices\x12'\n" +
+	"\x0fresults_visible\x18\t \x01(\bR\x0eresultsVisible\"H\n" +
+	"\n" +
+	"PollOption\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x14\n" +
+	"\x05texto\x18\x02 \x01(\tR\x05texto\x12\x14\n" +
+	"\x05votes\x18\x03 \x01(\x05R\x05votes\"\x91\x01\n" +
+	"\vLinkPreview\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1b\n" +
+	"\timage_url\x18\x04 \x01(\tR\bimageUrl\x12\x1b\n" +
+	"\tsite_name\x18\x05 \x01(\tR\bsiteName\"\x8e\x03\n" +
 	"\aProfile\x12\x1a\n" +
 	"\busername\x18\x01 \x01(\tR\busername\x12\x1f\n" +
 	"\vtotal_posts\x18\x02 \x01(\x05R\n" +
 	"totalPosts\x12\x1f\n" +
 	"\vtotal_likes\x18\x03 \x01(\x05R\n" +
 	"totalLikes\x12\"\n" +
-	"\x05posts\x18\x04 \x03(\v2\f.social.PostR\x05posts\";\n" +
+	"\x05posts\x18\x04 \x03(\v2\f.social.PostR\x05posts\x12-\n" +
+	"\vpinned_post\x18\x05 \x01(\v2\f.social.PostR\n" +
+	"pinnedPost\x12!\n" +
+	"\fdisplay_name\x18\x06 \x01(\tR\vdisplayName\x12\x10\n" +
+	"\x03bio\x18\a \x01(\tR\x03bio\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\b \x01(\tR\tavatarUrl\x12\x1c\n" +
+	"\tfollowers\x18\t \x01(\x05R\tfollowers\x12\x1c\n" +
+	"\tfollowing\x18\n" +
+	" \x01(\x05R\tfollowing\x12!\n" +
+	"\fis_following\x18\v \x01(\bR\visFollowing\x12\x1f\n" +
+	"\vnext_cursor\x18\f \x01(\tR\n" +
+	"nextCursor\"\x86\x01\n" +
 	"\n" +
 	"LikeResult\x12\x17\n" +
 	"\apost_id\x18\x01 \x01(\x05R\x06postId\x12\x14\n" +
-	"\x05likes\x18\x02 \x01(\x05R\x05likes\"6\n" +
+	"\x05likes\x18\x02 \x01(\x05R\x05likes\x12\x14\n" +
+	"\x05emoji\x18\x03 \x01(\tR\x05emoji\x123\n" +
+	"\treactions\x18\x04 \x03(\v2\x15.social.ReactionCountR\treactions\"6\n" +
 	"\fDeleteResult\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\";\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"g\n" +
 	"\vFeedRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"\x1f\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x12\n" +
+	"\x04mode\x18\x04 \x01(\tR\x04mode\"\x1f\n" +
 	"\rThreadRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\",\n" +
 	"\x0eProfileRequest\x12\x1a\n" +
@@ -943,10 +1828,14 @@ const file_proto_social_proto_rawDesc = "" +
 	"\vLikeRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x05R\x02id\"#\n" +
 	"\x11DeletePostRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x05R\x02id\"H\n" +
+	"\x02id\x18\x01 \x01(\x05R\x02id\"0\n" +
+	"\x14SubscribeFeedRequest\x12\x18\n" +
+	"\aactions\x18\x01 \x03(\tR\aactions\"i\n" +
 	"\fFeedResponse\x12\"\n" +
 	"\x05posts\x18\x01 \x03(\v2\f.social.PostR\x05posts\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total\"2\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"2\n" +
 	"\x0eThreadResponse\x12 \n" +
 	"\x04post\x18\x01 \x01(\v2\f.social.PostR\x04post\"<\n" +
 	"\x0fProfileResponse\x12)\n" +
@@ -956,7 +1845,26 @@ const file_proto_social_proto_rawDesc = "" +
 	"\fLikeResponse\x12*\n" +
 	"\x06result\x18\x01 \x01(\v2\x12.social.LikeResultR\x06result\">\n" +
 	"\x0eDeleteResponse\x12,\n" +
-	"\x06result\x18\x01 \x01(\v2\x14.social.DeleteResultR\x06resultB\x15Z\x13cacc/proto/socialpbb\x06proto3"
+	"\x06result\x18\x01 \x01(\v2\x14.social.DeleteResultR\x06result\"\x9f\x01\n" +
+	"\tFeedEvent\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x0e\n" +
+	"\x02ts\x18\x02 \x01(\x03R\x02ts\x12 \n" +
+	"\x04post\x18\x03 \x01(\v2\f.social.PostR\x04post\x12\x17\n" +
+	"\apost_id\x18\x04 \x01(\x05R\x06postId\x12\x1b\n" +
+	"\tparent_id\x18\x05 \x01(\x05R\bparentId\x12\x12\n" +
+	"\x04data\x18\x06 \x01(\fR\x04data2\x9c\x04\n" +
+	"\rSocialService\x121\n" +
+	"\x04Feed\x12\x13.social.FeedRequest\x1a\x14.social.FeedResponse\x127\n" +
+	"\x06Thread\x12\x15.social.ThreadRequest\x1a\x16.social.ThreadResponse\x12:\n" +
+	"\aProfile\x12\x16.social.ProfileRequest\x1a\x17.social.ProfileResponse\x12=\n" +
+	"\n" +
+	"CreatePost\x12\x19.social.CreatePostRequest\x1a\x14.social.PostResponse\x127\n" +
+	"\aComment\x12\x16.social.CommentRequest\x1a\x14.social.PostResponse\x121\n" +
+	"\x04Like\x12\x13.social.LikeRequest\x1a\x14.social.LikeResponse\x123\n" +
+	"\x06Unlike\x12\x13.social.LikeRequest\x1a\x14.social.LikeResponse\x12?\n" +
+	"\n" +
+	"DeletePost\x12\x19.social.DeletePostRequest\x1a\x16.social.DeleteResponse\x12B\n" +
+	"\rSubscribeFeed\x12\x1c.social.SubscribeFeedRequest\x1a\x11.social.FeedEvent0\x01B\x15Z\x13cacc/proto/socialpbb\x06proto3"
 
 var (
 	file_proto_social_proto_rawDescOnce sync.Once
@@ -970,40 +1878,74 @@ func file_proto_social_proto_rawDescGZIP() []byte {
 	return file_proto_social_proto_rawDescData
 }
 
-var file_proto_social_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_proto_social_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
 var file_proto_social_proto_goTypes = []any{
-	(*Post)(nil),              // 0: social.Post
-	(*Profile)(nil),           // 1: social.Profile
-	(*LikeResult)(nil),        // 2: social.LikeResult
-	(*DeleteResult)(nil),      // 3: social.DeleteResult
-	(*FeedRequest)(nil),       // 4: social.FeedRequest
-	(*ThreadRequest)(nil),     // 5: social.ThreadRequest
-	(*ProfileRequest)(nil),    // 6: social.ProfileRequest
-	(*CreatePostRequest)(nil), // 7: social.CreatePostRequest
-	(*CommentRequest)(nil),    // 8: social.CommentRequest
-	(*LikeRequest)(nil),       // 9: social.LikeRequest
-	(*DeletePostRequest)(nil), // 10: social.DeletePostRequest
-	(*FeedResponse)(nil),      // 11: social.FeedResponse
-	(*ThreadResponse)(nil),    // 12: social.ThreadResponse
-	(*ProfileResponse)(nil),   // 13: social.ProfileResponse
-	(*PostResponse)(nil),      // 14: social.PostResponse
-	(*LikeResponse)(nil),      // 15: social.LikeResponse
-	(*DeleteResponse)(nil),    // 16: social.DeleteResponse
+	(*Post)(nil),                 // 0: social.Post
+	(*ReactionCount)(nil),        // 1: social.ReactionCount
+	(*Attachment)(nil),           // 2: social.Attachment
+	(*Poll)(nil),                 // 3: social.Poll
+	(*PollOption)(nil),           // 4: social.PollOption
+	(*LinkPreview)(nil),          // 5: social.LinkPreview
+	(*Profile)(nil),              // 6: social.Profile
+	(*LikeResult)(nil),           // 7: social.LikeResult
+	(*DeleteResult)(nil),         // 8: social.DeleteResult
+	(*FeedRequest)(nil),          // 9: social.FeedRequest
+	(*ThreadRequest)(nil),        // 10: social.ThreadRequest
+	(*ProfileRequest)(nil),       // 11: social.ProfileRequest
+	(*CreatePostRequest)(nil),    // 12: social.CreatePostRequest
+	(*CommentRequest)(nil),       // 13: social.CommentRequest
+	(*LikeRequest)(nil),          // 14: social.LikeRequest
+	(*DeletePostRequest)(nil),    // 15: social.DeletePostRequest
+	(*SubscribeFeedRequest)(nil), // 16: social.SubscribeFeedRequest
+	(*FeedResponse)(nil),         // 17: social.FeedResponse
+	(*ThreadResponse)(nil),       // 18: social.ThreadResponse
+	(*ProfileResponse)(nil),      // 19: social.ProfileResponse
+	(*PostResponse)(nil),         // 20: social.PostResponse
+	(*LikeResponse)(nil),         // 21: social.LikeResponse
+	(*DeleteResponse)(nil),       // 22: social.DeleteResponse
+	(*FeedEvent)(nil),            // 23: social.FeedEvent
 }
 var file_proto_social_proto_depIdxs = []int32{
-	0, // 0: social.Post.replies:type_name -> social.Post
-	0, // 1: social.Profile.posts:type_name -> social.Post
-	0, // 2: social.FeedResponse.posts:type_name -> social.Post
-	0, // 3: social.ThreadResponse.post:type_name -> social.Post
-	1, // 4: social.ProfileResponse.profile:type_name -> social.Profile
-	0, // 5: social.PostResponse.post:type_name -> social.Post
-	2, // 6: social.LikeResponse.result:type_name -> social.LikeResult
-	3, // 7: social.DeleteResponse.result:type_name -> social.DeleteResult
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	0,  // 0: social.Post.replies:type_name -> social.Post
+	1,  // 1: social.Post.reactions:type_name -> social.ReactionCount
+	0,  // 2: social.Post.repost:type_name -> social.Post
+	2,  // 3: social.Post.attachments:type_name -> social.Attachment
+	3,  // 4: social.Post.poll:type_name -> social.Poll
+	5,  // 5: social.Post.preview:type_name -> social.LinkPreview
+	4,  // 6: social.Poll.options:type_name -> social.PollOption
+	0,  // 7: social.Profile.posts:type_name -> social.Post
+	0,  // 8: social.Profile.pinned_post:type_name -> social.Post
+	1,  // 9: social.LikeResult.reactions:type_name -> social.ReactionCount
+	0,  // 10: social.FeedResponse.posts:type_name -> social.Post
+	0,  // 11: social.ThreadResponse.post:type_name -> social.Post
+	6,  // 12: social.ProfileResponse.profile:type_name -> social.Profile
+	0,  // 13: social.PostResponse.post:type_name -> social.Post
+	7,  // 14: social.LikeResponse.result:type_name -> social.LikeResult
+	8,  // 15: social.DeleteResponse.result:type_name -> social.DeleteResult
+	0,  // 16: social.FeedEvent.post:type_name -> social.Post
+	9,  // 17: social.SocialService.Feed:input_type -> social.FeedRequest
+	10, // 18: social.SocialService.Thread:input_type -> social.ThreadRequest
+	11, // 19: social.SocialService.Profile:input_type -> social.ProfileRequest
+	12, // 20: social.SocialService.CreatePost:input_type -> social.CreatePostRequest
+	13, // 21: social.SocialService.Comment:input_type -> social.CommentRequest
+	14, // 22: social.SocialService.Like:input_type -> social.LikeRequest
+	14, // 23: social.SocialService.Unlike:input_type -> social.LikeRequest
+	15, // 24: social.SocialService.DeletePost:input_type -> social.DeletePostRequest
+	16, // 25: social.SocialService.SubscribeFeed:input_type -> social.SubscribeFeedRequest
+	17, // 26: social.SocialService.Feed:output_type -> social.FeedResponse
+	18, // 27: social.SocialService.Thread:output_type -> social.ThreadResponse
+	19, // 28: social.SocialService.Profile:output_type -> social.ProfileResponse
+	20, // 29: social.SocialService.CreatePost:output_type -> social.PostResponse
+	20, // 30: social.SocialService.Comment:output_type -> social.PostResponse
+	21, // 31: social.SocialService.Like:output_type -> social.LikeResponse
+	21, // 32: social.SocialService.Unlike:output_type -> social.LikeResponse
+	22, // 33: social.SocialService.DeletePost:output_type -> social.DeleteResponse
+	23, // 34: social.SocialService.SubscribeFeed:output_type -> social.FeedEvent
+	26, // [26:35] is the sub-list for method output_type
+	17, // [17:26] is the sub-list for method input_type
+	17, // [17:17] is the sub-list for extension type_name
+	17, // [17:17] is the sub-list for extension extendee
+	0,  // [0:17] is the sub-list for field type_name
 }
 
 func init() { file_proto_social_proto_init() }
@@ -1017,9 +1959,9 @@ func file_proto_social_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_social_proto_rawDesc), len(file_proto_social_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   17,
+			NumMessages:   24,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_proto_social_proto_goTypes,
 		DependencyIndexes: file_proto_social_proto_depIdxs,